@@ -35,6 +35,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
@@ -53,6 +54,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/tools/couchbase"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/http"
+	_ "github.com/googleapis/genai-toolbox/internal/tools/mcpproxy"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssql/mssqlexecutesql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mssql/mssqlsql"
 	_ "github.com/googleapis/genai-toolbox/internal/tools/mysql/mysqlexecutesql"
@@ -82,6 +84,7 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/couchbase"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/dgraph"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/http"
+	_ "github.com/googleapis/genai-toolbox/internal/sources/mcp"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mssql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/neo4j"
@@ -90,6 +93,15 @@ import (
 	_ "github.com/googleapis/genai-toolbox/internal/sources/spanner"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	_ "github.com/googleapis/genai-toolbox/internal/sources/valkey"
+
+	// Import auth service packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/auth/apikey"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/google"
+	_ "github.com/googleapis/genai-toolbox/internal/auth/oidc"
+
+	// Import prompt packages for side effect of registration
+	_ "github.com/googleapis/genai-toolbox/internal/prompts/custom"
 )
 
 var (
@@ -190,6 +202,19 @@ func NewCommand(opts ...Option) *Command {
 	flags.StringVar(&cmd.prebuiltConfig, "prebuilt", "", "Use a prebuilt tool configuration by source type. Cannot be used with --tools-file. Allowed: 'alloydb-postgres', 'bigquery', 'cloudshell', 'cloud-sql-mysql', 'cloud-sql-postgres', 'cloud-sql-mssql', 'postgres', 'spanner', 'spanner-postgres'.")
 	flags.BoolVar(&cmd.cfg.Stdio, "stdio", false, "Listens via MCP STDIO instead of acting as a remote HTTP server.")
 	flags.BoolVar(&cmd.cfg.DisableReload, "disable-reload", false, "Disables dynamic reloading of tools file.")
+	flags.IntVar(&cmd.cfg.ToolsPageSize, "tools-page-size", 0, "Maximum number of tools listed per page by MCP `tools/list` and the toolset API. Lists all tools at once if 0.")
+	flags.IntVar(&cmd.cfg.McpBatchConcurrency, "mcp-batch-concurrency", 1, "Maximum number of messages of an MCP batch request that are processed concurrently.")
+	flags.IntVar(&cmd.cfg.ApiBatchConcurrency, "api-batch-concurrency", 8, "Maximum number of invocations of an API batch request that run concurrently.")
+	flags.IntVar(&cmd.cfg.ApiBatchMaxSize, "api-batch-max-size", 100, "Maximum number of invocations of an API batch request. Set to 0 to accept batches of any size.")
+	flags.StringSliceVar(&cmd.cfg.AdminAuthServices, "admin-auth-services", []string{}, "Auth services whose tokens authorize the requests to the admin API endpoints, such as `/api/sources`. The admin endpoints are disabled if not set.")
+	flags.StringVar(&cmd.cfg.JobsStore, "jobs-store", "", "Path of a SQLite database that stores the asynchronous invocation jobs, so that they survive restarts. Jobs are kept in memory if not set.")
+	flags.DurationVar(&cmd.cfg.JobsTTL, "jobs-ttl", time.Hour, "How long finished asynchronous invocation jobs are kept.")
+	flags.IntVar(&cmd.cfg.JobsMax, "jobs-max", 1000, "Maximum number of stored asynchronous invocation jobs, including the finished jobs that are kept. Unlimited if 0.")
+	flags.IntVar(&cmd.cfg.JobsMaxRunning, "jobs-max-running", 16, "Maximum number of asynchronous invocation jobs that run at once. Unlimited if 0.")
+	flags.StringVar(&cmd.cfg.TLSCertFile, "tls-cert", "", "Path of a PEM certificate that the server uses to serve HTTPS. Must be set with --tls-key.")
+	flags.StringVar(&cmd.cfg.TLSKeyFile, "tls-key", "", "Path of the PEM private key of --tls-cert.")
+	flags.StringVar(&cmd.cfg.TLSClientCAFile, "tls-client-ca", "", "Path of the PEM certificates of the CAs that verify client certificates. Clients may present a certificate unless --tls-require-client-cert is set.")
+	flags.BoolVar(&cmd.cfg.TLSRequireClientCert, "tls-require-client-cert", false, "Requires clients to present a certificate verified by --tls-client-ca.")
 
 	// wrap RunE command so that we have access to original Command object
 	cmd.RunE = func(*cobra.Command, []string) error { return run(cmd) }
@@ -203,6 +228,7 @@ type ToolsFile struct {
 	AuthServices server.AuthServiceConfigs `yaml:"authServices"`
 	Tools        server.ToolConfigs        `yaml:"tools"`
 	Toolsets     server.ToolsetConfigs     `yaml:"toolsets"`
+	Prompts      server.PromptConfigs      `yaml:"prompts"`
 }
 
 // parseEnv replaces environment variables ${ENV_NAME} with their values.
@@ -239,14 +265,15 @@ func parseToolsFile(ctx context.Context, raw []byte) (ToolsFile, error) {
 }
 
 // mergeToolsFiles merges multiple ToolsFile structs into one.
-// Detects and raises errors for resource conflicts in sources, authServices, tools, and toolsets.
-// All resource names (sources, authServices, tools, toolsets) must be unique across all files.
+// Detects and raises errors for resource conflicts in sources, authServices, tools, toolsets, and prompts.
+// All resource names (sources, authServices, tools, toolsets, prompts) must be unique across all files.
 func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 	merged := ToolsFile{
 		Sources:      make(server.SourceConfigs),
 		AuthServices: make(server.AuthServiceConfigs),
 		Tools:        make(server.ToolConfigs),
 		Toolsets:     make(server.ToolsetConfigs),
+		Prompts:      make(server.PromptConfigs),
 	}
 
 	var conflicts []string
@@ -296,11 +323,20 @@ func mergeToolsFiles(files ...ToolsFile) (ToolsFile, error) {
 				merged.Toolsets[name] = toolset
 			}
 		}
+
+		// Check for conflicts and merge prompts
+		for name, prompt := range file.Prompts {
+			if _, exists := merged.Prompts[name]; exists {
+				conflicts = append(conflicts, fmt.Sprintf("prompt '%s' (file #%d)", name, fileIndex+1))
+			} else {
+				merged.Prompts[name] = prompt
+			}
+		}
 	}
 
 	// If conflicts were detected, return an error
 	if len(conflicts) > 0 {
-		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, and toolset has a unique name across all files", strings.Join(conflicts, "\n  - "))
+		return ToolsFile{}, fmt.Errorf("resource conflicts detected:\n  - %s\n\nPlease ensure each source, authService, tool, toolset, and prompt has a unique name across all files", strings.Join(conflicts, "\n  - "))
 	}
 
 	return merged, nil
@@ -374,22 +410,27 @@ func handleDynamicReload(ctx context.Context, toolsFile ToolsFile, s *server.Ser
 		panic(err)
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := validateReloadEdits(ctx, toolsFile)
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := validateReloadEdits(ctx, toolsFile, s.TLSClientCAFile())
 	if err != nil {
 		errMsg := fmt.Errorf("unable to validate reloaded edits: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
 		return err
 	}
 
-	s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	// the replaced sources are closed, a failure to close them does not fail
+	// the reload
+	if err := s.ResourceMgr.SetResources(sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap); err != nil {
+		logger.WarnContext(ctx, err.Error())
+	}
 
 	return nil
 }
 
-// validateReloadEdits checks that the reloaded tools file configs can initialized without failing
+// validateReloadEdits checks that the reloaded tools file configs can initialized without failing.
+// The auth services are checked against the client CA of the running server.
 func validateReloadEdits(
-	ctx context.Context, toolsFile ToolsFile,
-) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, error,
+	ctx context.Context, toolsFile ToolsFile, tlsClientCAFile string,
+) (map[string]sources.Source, map[string]auth.AuthService, map[string]tools.Tool, map[string]tools.Toolset, map[string]prompts.Prompt, map[string]prompts.Promptset, error,
 ) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -412,16 +453,18 @@ func validateReloadEdits(
 		AuthServiceConfigs: toolsFile.AuthServices,
 		ToolConfigs:        toolsFile.Tools,
 		ToolsetConfigs:     toolsFile.Toolsets,
+		PromptConfigs:      toolsFile.Prompts,
+		TLSClientCAFile:    tlsClientCAFile,
 	}
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := server.InitializeConfigs(ctx, reloadedConfig)
 	if err != nil {
 		errMsg := fmt.Errorf("unable to initialize reloaded configs: %w", err)
 		logger.WarnContext(ctx, errMsg.Error())
-		return nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
+	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, nil
 }
 
 // watchChanges checks for changes in the provided yaml tools file(s) or folder.
@@ -531,7 +574,7 @@ func watchChanges(ctx context.Context, watchDirs map[string]bool, watchedFiles m
 
 			err = handleDynamicReload(ctx, reloadedToolsFile, s)
 			if err != nil {
-				errMsg := fmt.Errorf("unable to parse reloaded tools file at %q: %w", reloadedToolsFile, err)
+				errMsg := fmt.Errorf("unable to parse reloaded tools file: %w", err)
 				logger.WarnContext(ctx, errMsg.Error())
 				continue
 			}
@@ -723,7 +766,7 @@ func run(cmd *Command) error {
 		}
 	}
 
-	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets
+	cmd.cfg.SourceConfigs, cmd.cfg.AuthServiceConfigs, cmd.cfg.ToolConfigs, cmd.cfg.ToolsetConfigs, cmd.cfg.PromptConfigs = toolsFile.Sources, toolsFile.AuthServices, toolsFile.Tools, toolsFile.Toolsets, toolsFile.Prompts
 	authSourceConfigs := toolsFile.AuthSources
 	if authSourceConfigs != nil {
 		cmd.logger.WarnContext(ctx, "`authSources` is deprecated, use `authServices` instead")
@@ -790,6 +833,10 @@ func run(cmd *Command) error {
 	// wait for either the server to error out or the command's context to be canceled
 	select {
 	case err := <-srvErr:
+		// the server stopped, e.g. at the end of the stdio session
+		if closeErr := s.ResourceMgr.Close(); closeErr != nil {
+			cmd.logger.WarnContext(ctx, closeErr.Error())
+		}
 		if err != nil {
 			errMsg := fmt.Errorf("toolbox crashed with the following error: %w", err)
 			cmd.logger.ErrorContext(ctx, errMsg.Error())
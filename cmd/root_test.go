@@ -30,10 +30,14 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
 	"github.com/googleapis/genai-toolbox/internal/auth/google"
 	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/prebuiltconfigs"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/prompts/custom"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	cloudsqlpgsrc "github.com/googleapis/genai-toolbox/internal/sources/cloudsqlpg"
 	httpsrc "github.com/googleapis/genai-toolbox/internal/sources/http"
@@ -60,6 +64,27 @@ func withDefaults(c server.ServerConfig) server.ServerConfig {
 	if c.TelemetryServiceName == "" {
 		c.TelemetryServiceName = "toolbox"
 	}
+	if c.McpBatchConcurrency == 0 {
+		c.McpBatchConcurrency = 1
+	}
+	if c.ApiBatchConcurrency == 0 {
+		c.ApiBatchConcurrency = 8
+	}
+	if c.ApiBatchMaxSize == 0 {
+		c.ApiBatchMaxSize = 100
+	}
+	if c.AdminAuthServices == nil {
+		c.AdminAuthServices = []string{}
+	}
+	if c.JobsTTL == 0 {
+		c.JobsTTL = time.Hour
+	}
+	if c.JobsMax == 0 {
+		c.JobsMax = 1000
+	}
+	if c.JobsMaxRunning == 0 {
+		c.JobsMaxRunning = 16
+	}
 	return c
 }
 
@@ -191,6 +216,73 @@ func TestServerConfigFlags(t *testing.T) {
 				DisableReload: true,
 			}),
 		},
+		{
+			desc: "tools page size",
+			args: []string{"--tools-page-size", "50"},
+			want: withDefaults(server.ServerConfig{
+				ToolsPageSize: 50,
+			}),
+		},
+		{
+			desc: "mcp batch concurrency",
+			args: []string{"--mcp-batch-concurrency", "4"},
+			want: withDefaults(server.ServerConfig{
+				McpBatchConcurrency: 4,
+			}),
+		},
+		{
+			desc: "api batch concurrency",
+			args: []string{"--api-batch-concurrency", "2"},
+			want: withDefaults(server.ServerConfig{
+				ApiBatchConcurrency: 2,
+			}),
+		},
+		{
+			desc: "api batch max size",
+			args: []string{"--api-batch-max-size", "10"},
+			want: withDefaults(server.ServerConfig{
+				ApiBatchMaxSize: 10,
+			}),
+		},
+		{
+			desc: "admin auth services",
+			args: []string{"--admin-auth-services", "my-auth,other-auth"},
+			want: withDefaults(server.ServerConfig{
+				AdminAuthServices: []string{"my-auth", "other-auth"},
+			}),
+		},
+		{
+			desc: "jobs store",
+			args: []string{"--jobs-store", "jobs.db"},
+			want: withDefaults(server.ServerConfig{
+				JobsStore: "jobs.db",
+			}),
+		},
+		{
+			desc: "jobs ttl",
+			args: []string{"--jobs-ttl", "10m"},
+			want: withDefaults(server.ServerConfig{
+				JobsTTL: 10 * time.Minute,
+			}),
+		},
+		{
+			desc: "jobs limits",
+			args: []string{"--jobs-max", "10", "--jobs-max-running", "2"},
+			want: withDefaults(server.ServerConfig{
+				JobsMax:        10,
+				JobsMaxRunning: 2,
+			}),
+		},
+		{
+			desc: "tls",
+			args: []string{"--tls-cert", "cert.pem", "--tls-key", "key.pem", "--tls-client-ca", "ca.pem", "--tls-require-client-cert"},
+			want: withDefaults(server.ServerConfig{
+				TLSCertFile:          "cert.pem",
+				TLSKeyFile:           "key.pem",
+				TLSClientCAFile:      "ca.pem",
+				TLSRequireClientCert: true,
+			}),
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -425,6 +517,17 @@ func TestParseToolFile(t *testing.T) {
 			toolsets:
 				example_toolset:
 					- example_tool
+					- example_prompt
+			prompts:
+				example_prompt:
+					kind: custom
+					description: some description
+					messages:
+						- content: Summarize the sales in {{.country}}.
+					arguments:
+						- name: country
+							type: string
+							description: some description
 			`,
 			wantToolsFile: ToolsFile{
 				Sources: server.SourceConfigs{
@@ -456,7 +559,59 @@ func TestParseToolFile(t *testing.T) {
 				Toolsets: server.ToolsetConfigs{
 					"example_toolset": tools.ToolsetConfig{
 						Name:      "example_toolset",
+						ToolNames: []string{"example_tool", "example_prompt"},
+					},
+				},
+				Prompts: server.PromptConfigs{
+					"example_prompt": custom.Config{
+						Name:        "example_prompt",
+						Kind:        "custom",
+						Description: "some description",
+						Messages: []prompts.Message{
+							{Content: "Summarize the sales in {{.country}}."},
+						},
+						Arguments: []tools.Parameter{
+							tools.NewStringParameter("country", "some description"),
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "toolsets in list and mapping form",
+			in: `
+			toolsets:
+				list_toolset:
+					- example_tool
+				strict_toolset:
+					tools:
+						- example_tool
+					strict: true
+				described_toolset:
+					tools:
+						- example_tool
+					description: some description
+					instructions: some instructions
+					sources:
+						- my-pg-source
+			`,
+			wantToolsFile: ToolsFile{
+				Toolsets: server.ToolsetConfigs{
+					"list_toolset": tools.ToolsetConfig{
+						Name:      "list_toolset",
+						ToolNames: []string{"example_tool"},
+					},
+					"strict_toolset": tools.ToolsetConfig{
+						Name:      "strict_toolset",
 						ToolNames: []string{"example_tool"},
+						Strict:    true,
+					},
+					"described_toolset": tools.ToolsetConfig{
+						Name:         "described_toolset",
+						ToolNames:    []string{"example_tool"},
+						Description:  "some description",
+						Instructions: "some instructions",
+						Sources:      []string{"my-pg-source"},
 					},
 				},
 			},
@@ -480,9 +635,24 @@ func TestParseToolFile(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
+			if diff := cmp.Diff(tc.wantToolsFile.Prompts, toolsFile.Prompts); diff != "" {
+				t.Fatalf("incorrect prompts parse: diff %v", diff)
+			}
 		})
 	}
 
+	// misspelled options of toolsets are rejected
+	in := `
+	toolsets:
+		strict_toolset:
+			tools:
+				- example_tool
+			stict: true
+	`
+	_, err = parseToolsFile(ctx, testutils.FormatYaml(in))
+	if err == nil || !strings.Contains(err.Error(), `unable to parse toolset "strict_toolset"`) {
+		t.Fatalf("unexpected error for unknown toolset field: %v", err)
+	}
 }
 
 func TestParseToolFileWithAuth(t *testing.T) {
@@ -490,6 +660,7 @@ func TestParseToolFileWithAuth(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
+	existsTrue := true
 	tcs := []struct {
 		description   string
 		in            string
@@ -794,6 +965,102 @@ func TestParseToolFileWithAuth(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "auth policies",
+			in: `
+			sources:
+				my-pg-instance:
+					kind: cloud-sql-postgres
+					project: my-project
+					region: my-region
+					instance: my-instance
+					database: my_db
+					user: my_user
+					password: my_pass
+			authServices:
+				my-google-service:
+					kind: google
+					clientId: my-client-id
+
+			tools:
+				example_tool:
+					kind: postgres-sql
+					source: my-pg-instance
+					description: some description
+					statement: |
+						SELECT * FROM SQL_STATEMENT;
+					authRequired:
+						- my-google-service
+					authPolicies:
+						- authService: my-google-service
+						  claim: email
+						  regex: '.*@example\.com'
+
+			toolsets:
+				example_toolset:
+					tools:
+						- example_tool
+					authPolicies:
+						- authService: my-google-service
+						  any:
+							- claim: hd
+							  equals: example.com
+							- claim: email_verified
+							  exists: true
+			`,
+			wantToolsFile: ToolsFile{
+				Sources: server.SourceConfigs{
+					"my-pg-instance": cloudsqlpgsrc.Config{
+						Name:     "my-pg-instance",
+						Kind:     cloudsqlpgsrc.SourceKind,
+						Project:  "my-project",
+						Region:   "my-region",
+						Instance: "my-instance",
+						IPType:   "public",
+						Database: "my_db",
+						User:     "my_user",
+						Password: "my_pass",
+					},
+				},
+				AuthServices: server.AuthServiceConfigs{
+					"my-google-service": google.Config{
+						Name:     "my-google-service",
+						Kind:     google.AuthServiceKind,
+						ClientID: "my-client-id",
+					},
+				},
+				Tools: server.ToolConfigs{
+					"example_tool": tools.AuthPolicyToolConfig{
+						ToolConfig: postgressql.Config{
+							Name:         "example_tool",
+							Kind:         "postgres-sql",
+							Source:       "my-pg-instance",
+							Description:  "some description",
+							Statement:    "SELECT * FROM SQL_STATEMENT;\n",
+							AuthRequired: []string{"my-google-service"},
+						},
+						AuthPolicies: tools.AuthPolicies{
+							{AuthService: "my-google-service", Claim: "email", Regex: `.*@example\.com`},
+						},
+					},
+				},
+				Toolsets: server.ToolsetConfigs{
+					"example_toolset": tools.ToolsetConfig{
+						Name:      "example_toolset",
+						ToolNames: []string{"example_tool"},
+						AuthPolicies: tools.AuthPolicies{
+							{
+								AuthService: "my-google-service",
+								Any: []tools.AuthPolicy{
+									{Claim: "hd", Equals: "example.com"},
+									{Claim: "email_verified", Exists: &existsTrue},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -807,10 +1074,10 @@ func TestParseToolFileWithAuth(t *testing.T) {
 			if diff := cmp.Diff(tc.wantToolsFile.AuthServices, toolsFile.AuthServices); diff != "" {
 				t.Fatalf("incorrect authServices parse: diff %v", diff)
 			}
-			if diff := cmp.Diff(tc.wantToolsFile.Tools, toolsFile.Tools); diff != "" {
+			if diff := cmp.Diff(tc.wantToolsFile.Tools, toolsFile.Tools, cmpopts.IgnoreUnexported(tools.AuthPolicy{})); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
-			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets); diff != "" {
+			if diff := cmp.Diff(tc.wantToolsFile.Toolsets, toolsFile.Toolsets, cmpopts.IgnoreUnexported(tools.AuthPolicy{})); diff != "" {
 				t.Fatalf("incorrect tools parse: diff %v", diff)
 			}
 		})
@@ -1159,6 +1426,35 @@ func TestSingleEdit(t *testing.T) {
 	}
 }
 
+func TestReloadClientCertAuthService(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation(versionString)
+	if err != nil {
+		t.Fatalf("failed to setup instrumentation %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	toolsFile := ToolsFile{
+		AuthServices: server.AuthServiceConfigs{
+			"my-certs": clientcert.Config{Name: "my-certs", Kind: clientcert.AuthServiceKind},
+		},
+	}
+	// the running server does not verify client certificates, so the reload
+	// is rejected as the startup would be
+	err = handleDynamicReload(ctx, toolsFile, &server.Server{})
+	want := `unable to initialize TLS: auth service "my-certs" of kind "client-cert" requires --tls-cert, --tls-key and --tls-client-ca to be set`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+
+	if _, _, _, _, _, _, err := validateReloadEdits(ctx, toolsFile, "ca.pem"); err != nil {
+		t.Fatalf("unexpected error with a client CA: %s", err)
+	}
+}
+
 func TestPrebuiltTools(t *testing.T) {
 	alloydb_config, _ := prebuiltconfigs.Get("alloydb-postgres")
 	bigquery_config, _ := prebuiltconfigs.Get("bigquery")
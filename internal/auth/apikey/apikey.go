@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const AuthServiceKind string = "api-key"
+
+// sha256Prefix is the prefix of the hex-encoded SHA-256 hashes of keys.
+const sha256Prefix = "sha256:"
+
+// bcryptMaxKeyLength is the length of the keys that bcrypt hashes. Longer
+// keys would be truncated, so they are never compared with bcrypt hashes.
+const bcryptMaxKeyLength = 72
+
+// maxRejected is the number of invalid keys whose digests are cached, so that
+// keys presented again are rejected without comparing bcrypt hashes.
+const maxRejected = 1024
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+func init() {
+	if !auth.Register(AuthServiceKind, newConfig) {
+		panic(fmt.Sprintf("auth service kind %q already registered", AuthServiceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// Auth service configuration
+type Config struct {
+	Name       string      `yaml:"name" validate:"required"`
+	Kind       string      `yaml:"kind" validate:"required"`
+	Keys       []KeyConfig `yaml:"keys"`
+	KeysFile   string      `yaml:"keysFile"`
+	HeaderName string      `yaml:"headerName"`
+}
+
+// KeyConfig is the configuration of an API key, which is only known by its
+// hash.
+type KeyConfig struct {
+	ID   string `yaml:"id" validate:"required"`
+	Hash string `yaml:"hash" validate:"required"`
+	// Prefix is the non-secret start of the key. The bcrypt hash of a key
+	// with a prefix is only compared with presented keys that start with it.
+	// Keys hashed by bcrypt, including their prefix, are at most
+	// bcryptMaxKeyLength bytes long.
+	Prefix    string         `yaml:"prefix"`
+	ExpiresAt string         `yaml:"expiresAt"`
+	Claims    map[string]any `yaml:"claims"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// Initialize an API key auth service
+func (cfg Config) Initialize(ctx context.Context, tracer trace.Tracer) (auth.AuthService, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
+	}
+
+	keyConfigs := cfg.Keys
+	if cfg.KeysFile != "" {
+		fileKeys, err := readKeysFile(cfg.KeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keyConfigs = append(keyConfigs, fileKeys...)
+	}
+	if len(keyConfigs) == 0 {
+		return nil, fmt.Errorf("no keys are configured, set keys or keysFile")
+	}
+	keys := make([]key, 0, len(keyConfigs))
+	ids := make(map[string]bool)
+	for _, kc := range keyConfigs {
+		if ids[kc.ID] {
+			return nil, fmt.Errorf("duplicate key ID %q", kc.ID)
+		}
+		ids[kc.ID] = true
+		k, err := kc.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", kc.ID, err)
+		}
+		keys = append(keys, k)
+	}
+	headerName := cfg.HeaderName
+	if headerName == "" {
+		headerName = cfg.Name + "_token"
+	}
+
+	a := &AuthService{
+		Name:       cfg.Name,
+		Kind:       AuthServiceKind,
+		HeaderName: headerName,
+		keys:       keys,
+		verified:   make(map[[sha256.Size]byte]int),
+		rejected:   make(map[[sha256.Size]byte]bool),
+		logger:     logger,
+	}
+	return a, nil
+}
+
+// readKeysFile returns the keys of a YAML or JSON file holding a list of
+// keys.
+func readKeysFile(path string) ([]KeyConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keysFile: %w", err)
+	}
+	var keys []KeyConfig
+	if err := yaml.UnmarshalWithOptions(b, &keys, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("unable to parse keysFile: %w", err)
+	}
+	for _, k := range keys {
+		if k.ID == "" || k.Hash == "" {
+			return nil, fmt.Errorf("unable to parse keysFile: keys require an id and a hash")
+		}
+	}
+	return keys, nil
+}
+
+// key is an API key that verifies the keys presented by callers.
+type key struct {
+	id string
+	// sha256 is the SHA-256 hash of the key, if it is not hashed by bcrypt.
+	sha256 []byte
+	// bcrypt is the bcrypt hash of the key, if it is not hashed by SHA-256.
+	bcrypt    []byte
+	prefix    string
+	expiresAt time.Time
+	claims    map[string]any
+}
+
+// parse validates the hash and the expiry of a key. The claims of a key
+// include its ID as the `sub` claim, unless it is set.
+func (kc KeyConfig) parse() (key, error) {
+	k := key{id: kc.ID, prefix: kc.Prefix, claims: map[string]any{"sub": kc.ID}}
+	maps.Copy(k.claims, kc.Claims)
+
+	switch {
+	case strings.HasPrefix(kc.Hash, sha256Prefix):
+		h, err := hex.DecodeString(strings.TrimPrefix(kc.Hash, sha256Prefix))
+		if err != nil || len(h) != sha256.Size {
+			return key{}, fmt.Errorf("hash is not a hex-encoded SHA-256 hash")
+		}
+		k.sha256 = h
+	case strings.HasPrefix(kc.Hash, "$2"):
+		if _, err := bcrypt.Cost([]byte(kc.Hash)); err != nil {
+			return key{}, fmt.Errorf("hash is not a bcrypt hash: %w", err)
+		}
+		if len(kc.Prefix) >= bcryptMaxKeyLength {
+			return key{}, fmt.Errorf("prefix must be shorter than the %d bytes of the keys hashed by bcrypt", bcryptMaxKeyLength)
+		}
+		k.bcrypt = []byte(kc.Hash)
+	default:
+		return key{}, fmt.Errorf("hash must be a bcrypt hash or a SHA-256 hash prefixed by %q", sha256Prefix)
+	}
+
+	if kc.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, kc.ExpiresAt)
+		if err != nil {
+			return key{}, fmt.Errorf("unable to parse expiresAt as RFC 3339 time: %w", err)
+		}
+		k.expiresAt = t
+	}
+	return k, nil
+}
+
+var _ auth.AuthService = &AuthService{}
+var _ auth.TokenHeaderProvider = &AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name       string `yaml:"name"`
+	Kind       string `yaml:"kind"`
+	HeaderName string `yaml:"headerName"`
+	keys       []key
+	logger     log.Logger
+
+	// mu guards verified, which caches the indexes of the keys that verified
+	// the SHA-256 digests of presented keys, so that bcrypt hashes are only
+	// compared once per key, and rejected, which caches the digests of up to
+	// maxRejected invalid keys.
+	mu       sync.Mutex
+	verified map[[sha256.Size]byte]int
+	rejected map[[sha256.Size]byte]bool
+}
+
+// Returns the auth service kind
+func (a *AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a *AuthService) GetName() string {
+	return a.Name
+}
+
+// Returns the header that holds the key
+func (a *AuthService) TokenHeader() string {
+	return a.HeaderName
+}
+
+// Verifies the API key of the header and return the claims of the key. The
+// errors and logs only ever name the ID of a key, never the key itself.
+func (a *AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	presented := strings.TrimSpace(h.Get(a.HeaderName))
+	if len(presented) > len("Bearer ") && strings.EqualFold(presented[:len("Bearer ")], "Bearer ") {
+		presented = strings.TrimSpace(presented[len("Bearer "):])
+	}
+	if presented == "" {
+		return nil, nil
+	}
+
+	k, ok := a.lookup(presented)
+	if !ok {
+		return nil, fmt.Errorf("API key verification failure: invalid key for auth service %q", a.Name)
+	}
+	if !k.expiresAt.IsZero() && time.Now().After(k.expiresAt) {
+		return nil, fmt.Errorf("API key verification failure: key %q of auth service %q expired at %s", k.id, a.Name, k.expiresAt.Format(time.RFC3339))
+	}
+	a.logger.DebugContext(ctx, fmt.Sprintf("API key %q of auth service %q verified", k.id, a.Name))
+	return maps.Clone(k.claims), nil
+}
+
+// lookup returns the key that verifies a presented key. The SHA-256 hashes
+// are compared first, since comparing bcrypt hashes is slow on purpose. Only
+// the bcrypt hashes of the keys whose prefix starts the presented key are
+// compared, and keys that were rejected recently are not compared again.
+// Presented keys longer than bcryptMaxKeyLength are not compared with bcrypt
+// hashes, since bcrypt would only compare their first bytes.
+func (a *AuthService) lookup(presented string) (key, bool) {
+	sum := sha256.Sum256([]byte(presented))
+	for _, k := range a.keys {
+		if k.sha256 != nil && subtle.ConstantTimeCompare(k.sha256, sum[:]) == 1 {
+			return k, true
+		}
+	}
+
+	a.mu.Lock()
+	i, ok := a.verified[sum]
+	rejected := a.rejected[sum]
+	a.mu.Unlock()
+	if ok {
+		return a.keys[i], true
+	}
+	if rejected {
+		return key{}, false
+	}
+	for i, k := range a.keys {
+		if k.bcrypt == nil || len(presented) > bcryptMaxKeyLength || !strings.HasPrefix(presented, k.prefix) {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword(k.bcrypt, []byte(presented)) == nil {
+			a.mu.Lock()
+			a.verified[sum] = i
+			a.mu.Unlock()
+			return k, true
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.rejected) >= maxRejected {
+		clear(a.rejected)
+	}
+	a.rejected[sum] = true
+	return key{}, false
+}
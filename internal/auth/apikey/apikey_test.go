@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func sha256Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return sha256Prefix + hex.EncodeToString(sum[:])
+}
+
+func bcryptHash(t *testing.T, key string) string {
+	h, err := bcrypt.GenerateFromPassword([]byte(key), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("unable to hash key: %s", err)
+	}
+	return string(h)
+}
+
+func initialize(cfg Config) (auth.AuthService, error) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	cfg := Config{
+		Name: "my-keys",
+		Kind: AuthServiceKind,
+		Keys: []KeyConfig{
+			{ID: "billing", Hash: sha256Hash("billing-secret"), Claims: map[string]any{"team": "billing", "email": "billing@example.com"}},
+			{ID: "reports", Hash: bcryptHash(t, "reports-secret")},
+			{ID: "expired", Hash: sha256Hash("expired-secret"), ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			{ID: "valid", Hash: sha256Hash("valid-secret"), ExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339), Claims: map[string]any{"sub": "valid-service"}},
+		},
+	}
+	a, err := initialize(cfg)
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	testCases := []struct {
+		name    string
+		key     string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "sha256 key",
+			key:  "billing-secret",
+			want: map[string]any{"sub": "billing", "team": "billing", "email": "billing@example.com"},
+		},
+		{
+			name: "bcrypt key",
+			key:  "reports-secret",
+			want: map[string]any{"sub": "reports"},
+		},
+		{
+			name: "cached bcrypt key",
+			key:  "Bearer reports-secret",
+			want: map[string]any{"sub": "reports"},
+		},
+		{
+			name: "key with expiry",
+			key:  "valid-secret",
+			want: map[string]any{"sub": "valid-service"},
+		},
+		{
+			name:    "expired key",
+			key:     "expired-secret",
+			wantErr: `key "expired" of auth service "my-keys" expired`,
+		},
+		{
+			name:    "invalid key",
+			key:     "some-secret",
+			wantErr: `invalid key for auth service "my-keys"`,
+		},
+		{
+			name: "no key",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.key != "" {
+				h.Set("my-keys_token", tc.key)
+			}
+			got, err := a.GetClaimsFromHeader(context.Background(), h)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				if strings.Contains(err.Error(), tc.key) {
+					t.Fatalf("error leaks the key: %s", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKeysFileAndHeaderName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keys := "- id: from-file\n  hash: " + sha256Hash("file-secret") + "\n  claims:\n    team: data\n"
+	if err := os.WriteFile(path, []byte(keys), 0o600); err != nil {
+		t.Fatalf("unable to write keys file: %s", err)
+	}
+	a, err := initialize(Config{Name: "my-keys", Kind: AuthServiceKind, KeysFile: path, HeaderName: "X-API-Key"})
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	if got := auth.TokenHeader(a); got != "X-API-Key" {
+		t.Fatalf("unexpected token header: %q", got)
+	}
+
+	h := http.Header{}
+	h.Set("X-API-Key", "file-secret")
+	got, err := a.GetClaimsFromHeader(context.Background(), h)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(map[string]any{"sub": "from-file", "team": "data"}, got); diff != "" {
+		t.Fatalf("incorrect claims (-want +got):\n%s", diff)
+	}
+}
+
+func TestBcryptLookup(t *testing.T) {
+	longKey := strings.Repeat("k", bcryptMaxKeyLength)
+	a, err := initialize(Config{
+		Name: "my-keys",
+		Kind: AuthServiceKind,
+		Keys: []KeyConfig{
+			{ID: "reports", Hash: bcryptHash(t, "rpt_reports-secret"), Prefix: "rpt_"},
+			{ID: "long", Hash: bcryptHash(t, longKey)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	s := a.(*AuthService)
+
+	if k, ok := s.lookup("rpt_reports-secret"); !ok || k.id != "reports" {
+		t.Fatalf("key with prefix was not verified: %+v", k)
+	}
+
+	// bcrypt only hashes the first 72 bytes of the keys, longer keys are
+	// rejected instead of being truncated
+	if k, ok := s.lookup(longKey); !ok || k.id != "long" {
+		t.Fatalf("key of %d bytes was not verified: %+v", len(longKey), k)
+	}
+	if _, ok := s.lookup(longKey + "-other"); ok {
+		t.Fatalf("key longer than %d bytes was verified", bcryptMaxKeyLength)
+	}
+
+	// invalid keys are cached, but only up to maxRejected of them
+	if _, ok := s.lookup("rpt_other-secret"); ok {
+		t.Fatalf("invalid key was verified")
+	}
+	if !s.rejected[sha256.Sum256([]byte("rpt_other-secret"))] {
+		t.Fatalf("invalid key was not cached")
+	}
+	for i := range maxRejected {
+		// keys without the prefix are rejected without comparing the hash
+		s.lookup(fmt.Sprintf("other_%d", i))
+	}
+	if len(s.rejected) > maxRejected {
+		t.Fatalf("too many cached invalid keys: %d", len(s.rejected))
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		keys    []KeyConfig
+		wantErr string
+	}{
+		{
+			name:    "no keys",
+			wantErr: "no keys are configured",
+		},
+		{
+			name:    "duplicate key",
+			keys:    []KeyConfig{{ID: "a", Hash: sha256Hash("a")}, {ID: "a", Hash: sha256Hash("b")}},
+			wantErr: `duplicate key ID "a"`,
+		},
+		{
+			name:    "plaintext key",
+			keys:    []KeyConfig{{ID: "a", Hash: "some-secret"}},
+			wantErr: "hash must be a bcrypt hash or a SHA-256 hash",
+		},
+		{
+			name:    "invalid sha256 hash",
+			keys:    []KeyConfig{{ID: "a", Hash: "sha256:abcd"}},
+			wantErr: "hash is not a hex-encoded SHA-256 hash",
+		},
+		{
+			name:    "invalid bcrypt hash",
+			keys:    []KeyConfig{{ID: "a", Hash: "$2a$xx"}},
+			wantErr: "hash is not a bcrypt hash",
+		},
+		{
+			name:    "bcrypt prefix longer than the key",
+			keys:    []KeyConfig{{ID: "a", Hash: bcryptHash(t, "a"), Prefix: strings.Repeat("p", bcryptMaxKeyLength)}},
+			wantErr: "prefix must be shorter than the 72 bytes of the keys hashed by bcrypt",
+		},
+		{
+			name:    "invalid expiry",
+			keys:    []KeyConfig{{ID: "a", Hash: sha256Hash("a"), ExpiresAt: "tomorrow"}},
+			wantErr: "unable to parse expiresAt",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := initialize(Config{Name: "my-keys", Kind: AuthServiceKind, Keys: tc.keys})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
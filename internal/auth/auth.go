@@ -16,13 +16,46 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+
+	"github.com/goccy/go-yaml"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// AuthServiceConfigFactory defines the function signature for creating an AuthServiceConfig.
+type AuthServiceConfigFactory func(ctx context.Context, name string, decoder *yaml.Decoder) (AuthServiceConfig, error)
+
+var authServiceRegistry = make(map[string]AuthServiceConfigFactory)
+
+// Register registers a new auth service kind with its factory.
+// It returns false if the kind is already registered.
+func Register(kind string, factory AuthServiceConfigFactory) bool {
+	if _, exists := authServiceRegistry[kind]; exists {
+		// Auth service with this kind already exists, do not overwrite.
+		return false
+	}
+	authServiceRegistry[kind] = factory
+	return true
+}
+
+// DecodeConfig decodes an auth service configuration using the registered factory for the given kind.
+func DecodeConfig(ctx context.Context, kind string, name string, decoder *yaml.Decoder) (AuthServiceConfig, error) {
+	factory, found := authServiceRegistry[kind]
+	if !found {
+		return nil, fmt.Errorf("%q is not a valid kind of auth source", kind)
+	}
+	authServiceConfig, err := factory(ctx, name, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse as %q: %w", kind, err)
+	}
+	return authServiceConfig, nil
+}
+
 // AuthServiceConfig is the interface for configuring authentication services.
 type AuthServiceConfig interface {
 	AuthServiceConfigKind() string
-	Initialize() (AuthService, error)
+	Initialize(ctx context.Context, tracer trace.Tracer) (AuthService, error)
 }
 
 // AuthService is the interface for authentication services.
@@ -31,3 +64,20 @@ type AuthService interface {
 	GetName() string
 	GetClaimsFromHeader(context.Context, http.Header) (map[string]any, error)
 }
+
+// TokenHeaderProvider is an optional interface that an AuthService can
+// implement when it reads its token from another header than
+// `<name>_token`. An empty header means that the AuthService reads no
+// header, e.g. because it verifies client certificates.
+type TokenHeaderProvider interface {
+	TokenHeader() string
+}
+
+// TokenHeader returns the name of the header that an auth service reads its
+// token from.
+func TokenHeader(a AuthService) string {
+	if p, ok := a.(TokenHeaderProvider); ok {
+		return p.TokenHeader()
+	}
+	return a.GetName() + "_token"
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientcert
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const AuthServiceKind string = "client-cert"
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+func init() {
+	if !auth.Register(AuthServiceKind, newConfig) {
+		panic(fmt.Sprintf("auth service kind %q already registered", AuthServiceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// Auth service configuration
+type Config struct {
+	Name string `yaml:"name" validate:"required"`
+	Kind string `yaml:"kind" validate:"required"`
+	// CAFile is the path of the PEM certificates of the CAs that the client
+	// certificates must chain to, in addition to the client CAs of the
+	// server. Any client certificate verified by the server is accepted if
+	// it is not set.
+	CAFile string `yaml:"caFile"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// Initialize a client certificate auth service
+func (cfg Config) Initialize(ctx context.Context, tracer trace.Tracer) (auth.AuthService, error) {
+	a := AuthService{
+		Name: cfg.Name,
+		Kind: AuthServiceKind,
+	}
+	if cfg.CAFile != "" {
+		b, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read caFile: %w", err)
+		}
+		a.roots = x509.NewCertPool()
+		if !a.roots.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("unable to parse caFile: no PEM certificates found")
+		}
+	}
+	return a, nil
+}
+
+var _ auth.AuthService = AuthService{}
+var _ auth.TokenHeaderProvider = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name  string `yaml:"name"`
+	Kind  string `yaml:"kind"`
+	roots *x509.CertPool
+}
+
+// Returns the auth service kind
+func (a AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Returns no header, since the client certificate is presented in the TLS
+// handshake
+func (a AuthService) TokenHeader() string {
+	return ""
+}
+
+// Returns the claims of the client certificate that the server verified. The
+// headers are ignored.
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, _ http.Header) (map[string]any, error) {
+	chain := util.ClientCertificatesFromContext(ctx)
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	cert := chain[0]
+	if a.roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, c := range chain[1:] {
+			intermediates.AddCert(c)
+		}
+		opts := x509.VerifyOptions{
+			Roots:         a.roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		if _, err := cert.Verify(opts); err != nil {
+			return nil, fmt.Errorf("client certificate verification failure: %w", err)
+		}
+	}
+	return certificateClaims(cert), nil
+}
+
+// certificateClaims returns the identity of a certificate as claims. The
+// `sub` claim is the SPIFFE ID of the certificate, or else the common name of
+// its subject, or else its subject. Lists of SANs are only set if they are
+// not empty.
+func certificateClaims(cert *x509.Certificate) map[string]any {
+	sum := sha256.Sum256(cert.Raw)
+	claims := map[string]any{
+		"subject":            cert.Subject.String(),
+		"issuer":             cert.Issuer.String(),
+		"serial_number":      cert.SerialNumber.Text(16),
+		"fingerprint_sha256": hex.EncodeToString(sum[:]),
+		"sub":                cert.Subject.String(),
+	}
+	if cert.Subject.CommonName != "" {
+		claims["common_name"] = cert.Subject.CommonName
+		claims["sub"] = cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		claims["dns_names"] = toList(cert.DNSNames)
+	}
+	if len(cert.EmailAddresses) > 0 {
+		claims["email_addresses"] = toList(cert.EmailAddresses)
+		claims["email"] = cert.EmailAddresses[0]
+	}
+	if len(cert.IPAddresses) > 0 {
+		ips := make([]any, 0, len(cert.IPAddresses))
+		for _, ip := range cert.IPAddresses {
+			ips = append(ips, ip.String())
+		}
+		claims["ip_addresses"] = ips
+	}
+	if len(cert.URIs) > 0 {
+		uris := make([]any, 0, len(cert.URIs))
+		var spiffeIDs []string
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+			if strings.EqualFold(u.Scheme, "spiffe") {
+				spiffeIDs = append(spiffeIDs, u.String())
+			}
+		}
+		claims["uris"] = uris
+		// a SPIFFE certificate holds exactly one SPIFFE ID
+		if len(spiffeIDs) == 1 {
+			claims["spiffe_id"] = spiffeIDs[0]
+			claims["sub"] = spiffeIDs[0]
+		}
+	}
+	return claims
+}
+
+// toList returns the values as a list of claim values.
+func toList(values []string) []any {
+	l := make([]any, 0, len(values))
+	for _, v := range values {
+		l = append(l, v)
+	}
+	return l
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientcert
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// issue returns a certificate issued by ca.
+func issue(t *testing.T, ca *testutils.CA, template x509.Certificate) *x509.Certificate {
+	certPEM, _, err := ca.Issue(template)
+	if err != nil {
+		t.Fatalf("unable to issue certificate: %s", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+	return cert
+}
+
+func newCA(t *testing.T, commonName string) *testutils.CA {
+	ca, err := testutils.NewCA(commonName)
+	if err != nil {
+		t.Fatalf("unable to create CA: %s", err)
+	}
+	return ca
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	ca := newCA(t, "my-ca")
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/prod/sa/agent")
+	otherURI, _ := url.Parse("https://example.org/agent")
+	spiffeCert := issue(t, ca, x509.Certificate{
+		SerialNumber:   big.NewInt(0xabc),
+		Subject:        pkix.Name{CommonName: "agent", Organization: []string{"Example"}},
+		DNSNames:       []string{"agent.example.org"},
+		EmailAddresses: []string{"agent@example.org", "ops@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{spiffeID, otherURI},
+	})
+	spiffeSum := sha256.Sum256(spiffeCert.Raw)
+	plainCert := issue(t, ca, x509.Certificate{
+		SerialNumber: big.NewInt(0xdef),
+		Subject:      pkix.Name{Organization: []string{"Example"}},
+	})
+	plainSum := sha256.Sum256(plainCert.Raw)
+
+	a, err := Config{Name: "my-certs", Kind: AuthServiceKind}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	if got := auth.TokenHeader(a); got != "" {
+		t.Fatalf("unexpected token header: %q", got)
+	}
+
+	testCases := []struct {
+		name  string
+		chain []*x509.Certificate
+		want  map[string]any
+	}{
+		{
+			name: "no certificate",
+		},
+		{
+			name:  "SPIFFE certificate",
+			chain: []*x509.Certificate{spiffeCert, ca.Cert},
+			want: map[string]any{
+				"sub":                "spiffe://example.org/ns/prod/sa/agent",
+				"spiffe_id":          "spiffe://example.org/ns/prod/sa/agent",
+				"subject":            "CN=agent,O=Example",
+				"common_name":        "agent",
+				"issuer":             "CN=my-ca",
+				"serial_number":      "abc",
+				"fingerprint_sha256": hex.EncodeToString(spiffeSum[:]),
+				"dns_names":          []any{"agent.example.org"},
+				"email":              "agent@example.org",
+				"email_addresses":    []any{"agent@example.org", "ops@example.org"},
+				"ip_addresses":       []any{"10.0.0.1"},
+				"uris":               []any{"spiffe://example.org/ns/prod/sa/agent", "https://example.org/agent"},
+			},
+		},
+		{
+			name:  "certificate without common name",
+			chain: []*x509.Certificate{plainCert, ca.Cert},
+			want: map[string]any{
+				"sub":                "O=Example",
+				"subject":            "O=Example",
+				"issuer":             "CN=my-ca",
+				"serial_number":      "def",
+				"fingerprint_sha256": hex.EncodeToString(plainSum[:]),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.chain != nil {
+				ctx = util.WithClientCertificates(ctx, tc.chain)
+			}
+			got, err := a.GetClaimsFromHeader(ctx, http.Header{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect claims (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCAFile(t *testing.T) {
+	ca := newCA(t, "my-ca")
+	otherCA := newCA(t, "other-ca")
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, ca.PEM, 0o600); err != nil {
+		t.Fatalf("unable to write CA file: %s", err)
+	}
+	a, err := Config{Name: "my-certs", Kind: AuthServiceKind, CAFile: path}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+
+	cert := issue(t, ca, x509.Certificate{Subject: pkix.Name{CommonName: "agent"}})
+	ctx := util.WithClientCertificates(context.Background(), []*x509.Certificate{cert, ca.Cert})
+	got, err := a.GetClaimsFromHeader(ctx, http.Header{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got["sub"] != "agent" {
+		t.Fatalf("unexpected sub claim: %v", got["sub"])
+	}
+
+	otherCert := issue(t, otherCA, x509.Certificate{Subject: pkix.Name{CommonName: "agent"}})
+	ctx = util.WithClientCertificates(context.Background(), []*x509.Certificate{otherCert, otherCA.Cert})
+	_, err = a.GetClaimsFromHeader(ctx, http.Header{})
+	if err == nil || !strings.Contains(err.Error(), "client certificate verification failure") {
+		t.Fatalf("unexpected error: got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("unable to write CA file: %s", err)
+	}
+	_, err = Config{Name: "my-certs", Kind: AuthServiceKind, CAFile: path}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err == nil || !strings.Contains(err.Error(), "no PEM certificates found") {
+		t.Fatalf("unexpected error: got %v", err)
+	}
+}
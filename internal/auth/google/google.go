@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/idtoken"
 )
 
@@ -28,6 +30,20 @@ const AuthServiceKind string = "google"
 // validate interface
 var _ auth.AuthServiceConfig = Config{}
 
+func init() {
+	if !auth.Register(AuthServiceKind, newConfig) {
+		panic(fmt.Sprintf("auth service kind %q already registered", AuthServiceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
 // Auth service configuration
 type Config struct {
 	Name     string `yaml:"name" validate:"required"`
@@ -41,7 +57,7 @@ func (cfg Config) AuthServiceConfigKind() string {
 }
 
 // Initialize a Google auth service
-func (cfg Config) Initialize() (auth.AuthService, error) {
+func (cfg Config) Initialize(ctx context.Context, tracer trace.Tracer) (auth.AuthService, error) {
 	a := &AuthService{
 		Name:     cfg.Name,
 		Kind:     AuthServiceKind,
@@ -0,0 +1,182 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// keysMaxAge is how long fetched keys are cached.
+	keysMaxAge = time.Hour
+	// keysMinRefreshInterval bounds how often the keys are fetched again
+	// because a token has an unknown key ID.
+	keysMinRefreshInterval = 30 * time.Second
+	// keysFetchTimeout bounds the requests to the issuer.
+	keysFetchTimeout = 10 * time.Second
+)
+
+// keySet holds the keys that verify the tokens. The keys of a remote set are
+// fetched lazily from its JWKS URL, and fetched again once they expire or
+// once a token is signed by an unknown key, to follow the key rotations.
+// A single fetch runs at a time, without holding the lock, so that the tokens
+// signed by cached keys are verified while the keys are fetched.
+type keySet struct {
+	remote bool
+	tracer trace.Tracer
+	issuer string
+	client *http.Client
+	// minRefreshInterval is keysMinRefreshInterval, unless overridden by tests.
+	minRefreshInterval time.Duration
+	// url is only accessed by the running fetch once the key set is created.
+	url string
+
+	mu          sync.RWMutex
+	keys        []jose.JSONWebKey
+	err         error
+	refreshedAt time.Time
+	// refreshing is closed once the running fetch ends, and is nil if no
+	// fetch runs.
+	refreshing chan struct{}
+}
+
+// newRemoteKeySet returns the key set of a JWKS URL, which is discovered
+// from the issuer if url is empty.
+func newRemoteKeySet(tracer trace.Tracer, issuer, url string) *keySet {
+	return &keySet{
+		remote:             true,
+		tracer:             tracer,
+		issuer:             issuer,
+		url:                url,
+		client:             &http.Client{Timeout: keysFetchTimeout},
+		minRefreshInterval: keysMinRefreshInterval,
+	}
+}
+
+// get returns the signing keys with a key ID, or all signing keys if kid is
+// empty.
+func (ks *keySet) get(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	if !ks.remote {
+		return lookup(ks.keys, kid), nil
+	}
+
+	ks.mu.RLock()
+	keys := lookup(ks.keys, kid)
+	fetched := !ks.refreshedAt.IsZero()
+	sinceRefresh := time.Since(ks.refreshedAt)
+	refreshing := ks.refreshing != nil
+	ks.mu.RUnlock()
+
+	switch {
+	case len(keys) > 0:
+		if sinceRefresh >= keysMaxAge {
+			// the cached keys are served while they are fetched again
+			ks.refresh(ctx)
+		}
+		return keys, nil
+	case refreshing || !fetched || sinceRefresh >= ks.minRefreshInterval:
+		// the keys are being fetched, were never fetched, or might have been
+		// rotated since they were fetched
+		select {
+		case <-ks.refresh(ctx):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys = lookup(ks.keys, kid)
+	if len(keys) == 0 && ks.err != nil {
+		return nil, fmt.Errorf("unable to fetch JWKS: %w", ks.err)
+	}
+	return keys, nil
+}
+
+// refresh starts fetching the keys, unless a fetch already runs, and returns
+// a channel that is closed once the fetch ends. The previous keys are kept if
+// it fails. The fetch is shared by the callers, so it is not cancelled with
+// ctx.
+func (ks *keySet) refresh(ctx context.Context) <-chan struct{} {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.refreshing != nil {
+		return ks.refreshing
+	}
+	done := make(chan struct{})
+	ks.refreshing = done
+	ks.refreshedAt = time.Now()
+	go func() {
+		defer close(done)
+		keys, err := ks.fetch(context.WithoutCancel(ctx))
+		ks.mu.Lock()
+		defer ks.mu.Unlock()
+		ks.err = err
+		if err == nil {
+			ks.keys = keys
+		}
+		ks.refreshing = nil
+	}()
+	return done
+}
+
+// fetch returns the keys of the JWKS URL, discovering it first if needed.
+func (ks *keySet) fetch(ctx context.Context) (_ []jose.JSONWebKey, err error) {
+	ctx, span := ks.tracer.Start(ctx, "toolbox/auth/oidc/jwks/fetch", trace.WithAttributes(attribute.String("issuer", ks.issuer)))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if ks.url == "" {
+		url, err := discoverJwksURL(ctx, ks.client, ks.issuer)
+		if err != nil {
+			return nil, err
+		}
+		ks.url = url
+	}
+	var jwks jose.JSONWebKeySet
+	if err := getJSON(ctx, ks.client, ks.url, &jwks); err != nil {
+		return nil, err
+	}
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no keys")
+	}
+	return jwks.Keys, nil
+}
+
+// lookup returns the signing keys with a key ID, or all signing keys if kid
+// is empty.
+func lookup(keys []jose.JSONWebKey, kid string) []jose.JSONWebKey {
+	var found []jose.JSONWebKey
+	for _, key := range keys {
+		if key.Use == "enc" || (kid != "" && key.KeyID != kid) {
+			continue
+		}
+		found = append(found, key)
+	}
+	return found
+}
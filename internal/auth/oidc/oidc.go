@@ -0,0 +1,308 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const AuthServiceKind string = "oidc"
+
+// default settings of the auth service
+const (
+	defaultAlgorithm = "RS256"
+	defaultClockSkew = "1m"
+)
+
+// supportedAlgorithms are the asymmetric signature algorithms of tokens that
+// can be verified with a JWKS.
+var supportedAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// validate interface
+var _ auth.AuthServiceConfig = Config{}
+
+func init() {
+	if !auth.Register(AuthServiceKind, newConfig) {
+		panic(fmt.Sprintf("auth service kind %q already registered", AuthServiceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (auth.AuthServiceConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+// Auth service configuration
+type Config struct {
+	Name       string   `yaml:"name" validate:"required"`
+	Kind       string   `yaml:"kind" validate:"required"`
+	Issuer     string   `yaml:"issuer" validate:"required"`
+	Audience   string   `yaml:"audience" validate:"required"`
+	JwksURL    string   `yaml:"jwksUrl"`
+	Jwks       string   `yaml:"jwks"`
+	JwksFile   string   `yaml:"jwksFile"`
+	Algorithms []string `yaml:"algorithms"`
+	ClockSkew  string   `yaml:"clockSkew"`
+	HeaderName string   `yaml:"headerName"`
+}
+
+// Returns the auth service kind
+func (cfg Config) AuthServiceConfigKind() string {
+	return AuthServiceKind
+}
+
+// Initialize an OIDC auth service. The keys of a JWKS URL are fetched ahead
+// of the first token, but the auth service is still initialized if the
+// issuer is unavailable, since the keys are fetched again on demand.
+func (cfg Config) Initialize(ctx context.Context, tracer trace.Tracer) (auth.AuthService, error) {
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get logger from ctx: %s", err)
+	}
+
+	algs, err := parseAlgorithms(cfg.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+	clockSkew := cfg.ClockSkew
+	if clockSkew == "" {
+		clockSkew = defaultClockSkew
+	}
+	skew, err := time.ParseDuration(clockSkew)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse clockSkew string as time.Duration: %w", err)
+	}
+	headerName := cfg.HeaderName
+	if headerName == "" {
+		headerName = cfg.Name + "_token"
+	}
+	keys, err := cfg.keySet(tracer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := keys.get(ctx, ""); err != nil {
+		logger.WarnContext(ctx, fmt.Sprintf("unable to fetch the keys of auth service %q, they will be fetched again on demand: %s", cfg.Name, err))
+	}
+
+	a := &AuthService{
+		Name:       cfg.Name,
+		Kind:       AuthServiceKind,
+		Issuer:     cfg.Issuer,
+		Audience:   cfg.Audience,
+		Algorithms: algs,
+		ClockSkew:  skew,
+		HeaderName: headerName,
+		keys:       keys,
+	}
+	return a, nil
+}
+
+// keySet returns the key set of the JWKS source of the config, which is
+// discovered from the issuer if none is set.
+func (cfg Config) keySet(tracer trace.Tracer) (*keySet, error) {
+	set := 0
+	for _, v := range []string{cfg.JwksURL, cfg.Jwks, cfg.JwksFile} {
+		if v != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("only one of jwksUrl, jwks and jwksFile can be set")
+	}
+
+	switch {
+	case cfg.Jwks != "":
+		return newStaticKeySet([]byte(cfg.Jwks))
+	case cfg.JwksFile != "":
+		b, err := os.ReadFile(cfg.JwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read jwksFile: %w", err)
+		}
+		return newStaticKeySet(b)
+	default:
+		return newRemoteKeySet(tracer, cfg.Issuer, cfg.JwksURL), nil
+	}
+}
+
+// parseAlgorithms returns the signature algorithms of names, RS256 if none.
+func parseAlgorithms(names []string) ([]jose.SignatureAlgorithm, error) {
+	if len(names) == 0 {
+		names = []string{defaultAlgorithm}
+	}
+	algs := make([]jose.SignatureAlgorithm, 0, len(names))
+	for _, name := range names {
+		alg := jose.SignatureAlgorithm(name)
+		if !slices.Contains(supportedAlgorithms, alg) {
+			return nil, fmt.Errorf("unsupported algorithm %q", name)
+		}
+		algs = append(algs, alg)
+	}
+	return algs, nil
+}
+
+var _ auth.AuthService = AuthService{}
+var _ auth.TokenHeaderProvider = AuthService{}
+
+// struct used to store auth service info
+type AuthService struct {
+	Name       string                    `yaml:"name"`
+	Kind       string                    `yaml:"kind"`
+	Issuer     string                    `yaml:"issuer"`
+	Audience   string                    `yaml:"audience"`
+	Algorithms []jose.SignatureAlgorithm `yaml:"algorithms"`
+	ClockSkew  time.Duration             `yaml:"clockSkew"`
+	HeaderName string                    `yaml:"headerName"`
+	keys       *keySet
+}
+
+// Returns the auth service kind
+func (a AuthService) AuthServiceKind() string {
+	return AuthServiceKind
+}
+
+// Returns the name of the auth service
+func (a AuthService) GetName() string {
+	return a.Name
+}
+
+// Returns the header that holds the token
+func (a AuthService) TokenHeader() string {
+	return a.HeaderName
+}
+
+// Verifies the JWT of the token header and return its claims
+func (a AuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := strings.TrimSpace(h.Get(a.HeaderName))
+	if len(token) > len("Bearer ") && strings.EqualFold(token[:len("Bearer ")], "Bearer ") {
+		token = strings.TrimSpace(token[len("Bearer "):])
+	}
+	if token == "" {
+		return nil, nil
+	}
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC token verification failure: %w", err)
+	}
+	return claims, nil
+}
+
+// verify checks the signature and the registered claims of a token, and
+// returns all its claims.
+func (a AuthService) verify(ctx context.Context, token string) (map[string]any, error) {
+	tok, err := jwt.ParseSigned(token, a.Algorithms)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := a.keys.get(ctx, tok.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key found for key ID %q", tok.Headers[0].KeyID)
+	}
+
+	var registered jwt.Claims
+	var claims map[string]any
+	for _, key := range keys {
+		if err = tok.Claims(key, &registered, &claims); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if registered.Expiry == nil {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	expected := jwt.Expected{
+		Issuer:      a.Issuer,
+		AnyAudience: jwt.Audience{a.Audience},
+		Time:        time.Now(),
+	}
+	if err := registered.ValidateWithLeeway(expected, a.ClockSkew); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// newStaticKeySet returns the key set of a JWKS document.
+func newStaticKeySet(b []byte) (*keySet, error) {
+	var jwks jose.JSONWebKeySet
+	if err := json.Unmarshal(b, &jwks); err != nil {
+		return nil, fmt.Errorf("unable to parse JWKS: %w", err)
+	}
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("JWKS has no keys")
+	}
+	return &keySet{keys: jwks.Keys}, nil
+}
+
+// discoveryDocument is the subset of the OpenID provider metadata that holds
+// the JWKS URL.
+type discoveryDocument struct {
+	JwksURI string `json:"jwks_uri"`
+}
+
+// discoverJwksURL returns the JWKS URL from the OpenID provider metadata of
+// an issuer.
+func discoverJwksURL(ctx context.Context, client *http.Client, issuer string) (string, error) {
+	var doc discoveryDocument
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &doc); err != nil {
+		return "", fmt.Errorf("unable to discover JWKS URL: %w", err)
+	}
+	if doc.JwksURI == "" {
+		return "", fmt.Errorf("unable to discover JWKS URL: issuer metadata has no jwks_uri")
+	}
+	return doc.JwksURI, nil
+}
+
+// getJSON decodes the JSON body of a GET request.
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %q from %s", resp.Status, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
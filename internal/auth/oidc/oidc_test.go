@@ -0,0 +1,473 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testAudience = "my-client-id"
+
+type testKey struct {
+	kid string
+	alg jose.SignatureAlgorithm
+	key any
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	return testKey{kid: kid, alg: jose.RS256, key: k}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+	return testKey{kid: kid, alg: jose.ES256, key: k}
+}
+
+// public returns the JWKS entry of the key.
+func (k testKey) public() jose.JSONWebKey {
+	return jose.JSONWebKey{Key: k.key.(crypto.Signer).Public(), KeyID: k.kid, Algorithm: string(k.alg), Use: "sig"}
+}
+
+// sign returns a token with the claims, signed by the key.
+func (k testKey) sign(t *testing.T, claims map[string]any) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: k.alg, Key: jose.JSONWebKey{Key: k.key, KeyID: k.kid}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		t.Fatalf("unable to create signer: %s", err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatalf("unable to sign token: %s", err)
+	}
+	return token
+}
+
+func jwksOf(keys ...testKey) jose.JSONWebKeySet {
+	var jwks jose.JSONWebKeySet
+	for _, k := range keys {
+		jwks.Keys = append(jwks.Keys, k.public())
+	}
+	return jwks
+}
+
+// testIssuer serves the OpenID provider metadata and the JWKS of an issuer.
+type testIssuer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	jwks    jose.JSONWebKeySet
+	fetches int
+	// gate blocks the JWKS requests until it is closed, if set
+	gate chan struct{}
+}
+
+func newTestIssuer(t *testing.T, keys ...testKey) *testIssuer {
+	iss := &testIssuer{jwks: jwksOf(keys...)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"issuer": iss.URL, "jwks_uri": iss.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		iss.mu.Lock()
+		gate := iss.gate
+		iss.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+		iss.mu.Lock()
+		defer iss.mu.Unlock()
+		iss.fetches++
+		_ = json.NewEncoder(w).Encode(iss.jwks)
+	})
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+	return iss
+}
+
+func (iss *testIssuer) rotate(keys ...testKey) {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.jwks = jwksOf(keys...)
+}
+
+func (iss *testIssuer) fetchCount() int {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	return iss.fetches
+}
+
+func (iss *testIssuer) claims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"iss":   iss.URL,
+		"aud":   testAudience,
+		"sub":   "some_user",
+		"email": "user@example.com",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func newContext(t *testing.T) context.Context {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unable to create context: %s", err)
+	}
+	return ctx
+}
+
+func initialize(t *testing.T, cfg Config) AuthService {
+	a, err := cfg.Initialize(newContext(t), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	return *a.(*AuthService)
+}
+
+func TestGetClaimsFromHeader(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	otherKey := newRSAKey(t, "key-2")
+	ecKey := newECKey(t, "key-3")
+	iss := newTestIssuer(t, key, ecKey)
+	a := initialize(t, Config{Name: "my-auth", Kind: AuthServiceKind, Issuer: iss.URL, Audience: testAudience, JwksURL: iss.URL + "/keys"})
+
+	testCases := []struct {
+		name      string
+		token     string
+		wantErr   string
+		wantEmpty bool
+	}{
+		{
+			name:  "valid token",
+			token: key.sign(t, iss.claims(nil)),
+		},
+		{
+			name:  "valid bearer token",
+			token: "Bearer " + key.sign(t, iss.claims(nil)),
+		},
+		{
+			name:  "expired within clock skew",
+			token: key.sign(t, iss.claims(map[string]any{"exp": time.Now().Add(-30 * time.Second).Unix()})),
+		},
+		{
+			name:      "no token",
+			wantEmpty: true,
+		},
+		{
+			name:    "expired token",
+			token:   key.sign(t, iss.claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})),
+			wantErr: "expired",
+		},
+		{
+			name:    "token without expiry",
+			token:   key.sign(t, iss.claims(map[string]any{"exp": nil})),
+			wantErr: "token has no expiry",
+		},
+		{
+			name:    "wrong audience",
+			token:   key.sign(t, iss.claims(map[string]any{"aud": "other-client-id"})),
+			wantErr: "audience",
+		},
+		{
+			name:    "wrong issuer",
+			token:   key.sign(t, iss.claims(map[string]any{"iss": "https://other.example.com"})),
+			wantErr: "issuer",
+		},
+		{
+			name:    "unknown key",
+			token:   otherKey.sign(t, iss.claims(nil)),
+			wantErr: `no key found for key ID "key-2"`,
+		},
+		{
+			name:    "algorithm not allowed",
+			token:   ecKey.sign(t, iss.claims(nil)),
+			wantErr: "unexpected signature algorithm",
+		},
+		{
+			name:    "malformed token",
+			token:   "not-a-token",
+			wantErr: "OIDC token verification failure",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if tc.token != "" {
+				h.Set("my-auth_token", tc.token)
+			}
+			claims, err := a.GetClaimsFromHeader(context.Background(), h)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantEmpty {
+				if claims != nil {
+					t.Fatalf("unexpected claims: %v", claims)
+				}
+				return
+			}
+			if claims["sub"] != "some_user" || claims["email"] != "user@example.com" {
+				t.Fatalf("unexpected claims: %v", claims)
+			}
+		})
+	}
+	// the keys are fetched once initialized, and the unknown key does not
+	// fetch them again within the minimum interval
+	if got := iss.fetchCount(); got != 1 {
+		t.Fatalf("unexpected number of JWKS fetches: got %d, want 1", got)
+	}
+}
+
+func TestDiscoveryAndHeaderName(t *testing.T) {
+	key := newECKey(t, "key-1")
+	iss := newTestIssuer(t, key)
+	a := initialize(t, Config{
+		Name:       "my-auth",
+		Kind:       AuthServiceKind,
+		Issuer:     iss.URL,
+		Audience:   testAudience,
+		Algorithms: []string{"ES256"},
+		HeaderName: "Authorization",
+	})
+	if got := a.TokenHeader(); got != "Authorization" {
+		t.Fatalf("unexpected token header: %q", got)
+	}
+
+	h := http.Header{}
+	h.Set("Authorization", "Bearer "+key.sign(t, iss.claims(nil)))
+	claims, err := a.GetClaimsFromHeader(context.Background(), h)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if claims["sub"] != "some_user" {
+		t.Fatalf("unexpected claims: %v", claims)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newRSAKey(t, "new")
+	iss := newTestIssuer(t, oldKey)
+	a := initialize(t, Config{Name: "my-auth", Kind: AuthServiceKind, Issuer: iss.URL, Audience: testAudience, JwksURL: iss.URL + "/keys"})
+	verify := func(key testKey) error {
+		h := http.Header{}
+		h.Set("my-auth_token", key.sign(t, iss.claims(nil)))
+		_, err := a.GetClaimsFromHeader(context.Background(), h)
+		return err
+	}
+
+	for range 3 {
+		if err := verify(oldKey); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if got := iss.fetchCount(); got != 1 {
+		t.Fatalf("keys were not cached: got %d fetches, want 1", got)
+	}
+
+	// unknown keys are only fetched again after the minimum refresh interval
+	iss.rotate(oldKey, newKey)
+	if err := verify(newKey); err == nil {
+		t.Fatalf("unexpected success before the keys were refreshed")
+	}
+	a.keys.minRefreshInterval = 0
+	if err := verify(newKey); err != nil {
+		t.Fatalf("unexpected error after rotation: %s", err)
+	}
+	if err := verify(oldKey); err != nil {
+		t.Fatalf("unexpected error of previous key: %s", err)
+	}
+	if got := iss.fetchCount(); got != 2 {
+		t.Fatalf("unexpected number of JWKS fetches: got %d, want 2", got)
+	}
+
+	// the cached keys are kept if the issuer is unavailable
+	iss.Close()
+	if err := verify(newKey); err != nil {
+		t.Fatalf("unexpected error with unavailable issuer: %s", err)
+	}
+}
+
+func TestKeysServedWhileRefreshing(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	iss := newTestIssuer(t, key)
+	a := initialize(t, Config{Name: "my-auth", Kind: AuthServiceKind, Issuer: iss.URL, Audience: testAudience, JwksURL: iss.URL + "/keys"})
+	verify := func() error {
+		h := http.Header{}
+		h.Set("my-auth_token", key.sign(t, iss.claims(nil)))
+		_, err := a.GetClaimsFromHeader(context.Background(), h)
+		return err
+	}
+	if err := verify(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// once the keys expire, the cached keys verify the tokens while the
+	// keys are fetched again
+	gate := make(chan struct{})
+	iss.mu.Lock()
+	iss.gate = gate
+	iss.mu.Unlock()
+	a.keys.mu.Lock()
+	a.keys.refreshedAt = time.Now().Add(-keysMaxAge)
+	a.keys.mu.Unlock()
+	for range 3 {
+		if err := verify(); err != nil {
+			t.Fatalf("unexpected error while refreshing: %s", err)
+		}
+	}
+	a.keys.mu.RLock()
+	refreshing := a.keys.refreshing
+	a.keys.mu.RUnlock()
+	if refreshing == nil {
+		t.Fatalf("the expired keys were not refreshed")
+	}
+	close(gate)
+	<-refreshing
+	if got := iss.fetchCount(); got != 2 {
+		t.Fatalf("unexpected number of JWKS fetches: got %d, want 2", got)
+	}
+}
+
+func TestStaticKeys(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	jwks, err := json.Marshal(jwksOf(key))
+	if err != nil {
+		t.Fatalf("unable to marshal JWKS: %s", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("unable to write JWKS: %s", err)
+	}
+	issuer := "https://issuer.example.com"
+	claims := map[string]any{"iss": issuer, "aud": testAudience, "sub": "some_user", "exp": time.Now().Add(time.Hour).Unix()}
+
+	configs := map[string]Config{
+		"inline": {Name: "my-auth", Kind: AuthServiceKind, Issuer: issuer, Audience: testAudience, Jwks: string(jwks)},
+		"file":   {Name: "my-auth", Kind: AuthServiceKind, Issuer: issuer, Audience: testAudience, JwksFile: path},
+	}
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			a := initialize(t, cfg)
+			h := http.Header{}
+			h.Set("my-auth_token", key.sign(t, claims))
+			got, err := a.GetClaimsFromHeader(context.Background(), h)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got["sub"] != "some_user" {
+				t.Fatalf("unexpected claims: %v", got)
+			}
+		})
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	base := Config{Name: "my-auth", Kind: AuthServiceKind, Issuer: "https://issuer.example.com", Audience: testAudience}
+	testCases := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{
+			name:    "several JWKS",
+			modify:  func(c *Config) { c.JwksURL = "https://issuer.example.com/keys"; c.Jwks = `{"keys": []}` },
+			wantErr: "only one of jwksUrl, jwks and jwksFile can be set",
+		},
+		{
+			name:    "empty JWKS",
+			modify:  func(c *Config) { c.Jwks = `{"keys": []}` },
+			wantErr: "JWKS has no keys",
+		},
+		{
+			name:    "missing JWKS file",
+			modify:  func(c *Config) { c.JwksFile = filepath.Join(t.TempDir(), "missing.json") },
+			wantErr: "unable to read jwksFile",
+		},
+		{
+			name:    "symmetric algorithm",
+			modify:  func(c *Config) { c.Algorithms = []string{"HS256"} },
+			wantErr: `unsupported algorithm "HS256"`,
+		},
+		{
+			name:    "invalid clock skew",
+			modify:  func(c *Config) { c.ClockSkew = "a minute" },
+			wantErr: "unable to parse clockSkew",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := base
+			tc.modify(&cfg)
+			_, err := cfg.Initialize(newContext(t), noop.NewTracerProvider().Tracer(""))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnavailableIssuer(t *testing.T) {
+	key := newRSAKey(t, "key-1")
+	iss := newTestIssuer(t, key)
+	iss.Close()
+	a := initialize(t, Config{Name: "my-auth", Kind: AuthServiceKind, Issuer: iss.URL, Audience: testAudience})
+
+	h := http.Header{}
+	h.Set("my-auth_token", key.sign(t, iss.claims(nil)))
+	_, err := a.GetClaimsFromHeader(context.Background(), h)
+	if err == nil || !strings.Contains(err.Error(), "unable to fetch JWKS") {
+		t.Fatalf("unexpected error: got %v, want %q", err, "unable to fetch JWKS")
+	}
+}
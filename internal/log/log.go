@@ -20,6 +20,7 @@ import (
 	"io"
 	"log/slog"
 	"strings"
+	"sync"
 )
 
 // StdLogger is the standard logger
@@ -183,3 +184,74 @@ func (sl *StructuredLogger) WarnContext(ctx context.Context, msg string, keysAnd
 func (sl *StructuredLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
 	sl.errLogger.ErrorContext(ctx, msg, keysAndValues...)
 }
+
+// SendFunc sends a log message with the given severity, e.g. to an MCP client.
+type SendFunc func(ctx context.Context, severity, msg string, keysAndValues ...interface{})
+
+// TeeLogger is a Logger that logs messages with an underlying Logger, and
+// also sends them with a SendFunc. The level of the messages that are sent is
+// independent of the level of the underlying Logger, and no messages are sent
+// until it is set with SetLevel.
+type TeeLogger struct {
+	logger Logger
+	send   SendFunc
+
+	mu      sync.Mutex
+	enabled bool
+	level   slog.Level
+}
+
+// NewTeeLogger creates a TeeLogger that logs messages with logger and sends
+// them with send.
+func NewTeeLogger(logger Logger, send SendFunc) *TeeLogger {
+	return &TeeLogger{logger: logger, send: send}
+}
+
+// SetLevel sets the minimum severity of the messages that are sent.
+func (tl *TeeLogger) SetLevel(logLevel string) error {
+	level, err := SeverityToLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.enabled = true
+	tl.level = level
+	return nil
+}
+
+// tee sends the message if its level is at least the level of the logger.
+func (tl *TeeLogger) tee(ctx context.Context, level slog.Level, msg string, keysAndValues ...interface{}) {
+	tl.mu.Lock()
+	enabled := tl.enabled && level >= tl.level
+	tl.mu.Unlock()
+	if !enabled {
+		return
+	}
+	sev, _ := levelToSeverity(level.String())
+	tl.send(ctx, sev, msg, keysAndValues...)
+}
+
+// DebugContext logs debug messages
+func (tl *TeeLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	tl.logger.DebugContext(ctx, msg, keysAndValues...)
+	tl.tee(ctx, slog.LevelDebug, msg, keysAndValues...)
+}
+
+// InfoContext logs info messages
+func (tl *TeeLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	tl.logger.InfoContext(ctx, msg, keysAndValues...)
+	tl.tee(ctx, slog.LevelInfo, msg, keysAndValues...)
+}
+
+// WarnContext logs warning messages
+func (tl *TeeLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	tl.logger.WarnContext(ctx, msg, keysAndValues...)
+	tl.tee(ctx, slog.LevelWarn, msg, keysAndValues...)
+}
+
+// ErrorContext logs error messages
+func (tl *TeeLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	tl.logger.ErrorContext(ctx, msg, keysAndValues...)
+	tl.tee(ctx, slog.LevelError, msg, keysAndValues...)
+}
@@ -483,3 +483,80 @@ func TestStructuredLoggerDebugLog(t *testing.T) {
 		})
 	}
 }
+
+func TestTeeLogger(t *testing.T) {
+	tcs := []struct {
+		name     string
+		logLevel string
+		teeLevel string
+		logMsg   string
+		wantOut  string
+		wantTeed []string
+	}{
+		{
+			name:     "tee level not set",
+			logLevel: "debug",
+			logMsg:   "info",
+			wantOut:  "INFO \"log info\" \n",
+		},
+		{
+			name:     "tee level below the logger level",
+			logLevel: "error",
+			teeLevel: "debug",
+			logMsg:   "debug",
+			wantTeed: []string{"DEBUG", "log debug"},
+		},
+		{
+			name:     "tee level above the message level",
+			logLevel: "debug",
+			teeLevel: "warn",
+			logMsg:   "info",
+			wantOut:  "INFO \"log info\" \n",
+		},
+		{
+			name:     "tee level equal to the message level",
+			logLevel: "info",
+			teeLevel: "warn",
+			logMsg:   "warn",
+			wantTeed: []string{"WARN", "log warn"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			outW := new(bytes.Buffer)
+			errW := new(bytes.Buffer)
+			logger, err := NewStdLogger(outW, errW, tc.logLevel)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var teed []string
+			teeLogger := NewTeeLogger(logger, func(ctx context.Context, severity, msg string, keysAndValues ...interface{}) {
+				teed = append(teed, severity, msg)
+			})
+			if tc.teeLevel != "" {
+				if err := teeLogger.SetLevel(tc.teeLevel); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+			runLogger(teeLogger, tc.logMsg)
+
+			outWString := outW.String()
+			spaceIndexOut := strings.Index(outWString, " ")
+			gotOut := outWString[spaceIndexOut+1:]
+
+			if diff := cmp.Diff(gotOut, tc.wantOut); diff != "" {
+				t.Fatalf("incorrect log: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantTeed, teed); diff != "" {
+				t.Fatalf("incorrect teed messages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTeeLoggerSetLevelError(t *testing.T) {
+	teeLogger := NewTeeLogger(nil, nil)
+	if err := teeLogger.SetLevel("fail"); err == nil {
+		t.Fatalf("expected error on incorrect log level")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom
+
+import (
+	"context"
+	"fmt"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "custom"
+
+func init() {
+	if !prompts.Register(kind, newConfig) {
+		panic(fmt.Sprintf("prompt kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (prompts.PromptConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name        string            `yaml:"name" validate:"required"`
+	Kind        string            `yaml:"kind" validate:"required"`
+	Description string            `yaml:"description"`
+	Messages    []prompts.Message `yaml:"messages" validate:"required"`
+	Arguments   tools.Parameters  `yaml:"arguments"`
+}
+
+// validate interface
+var _ prompts.PromptConfig = Config{}
+
+func (cfg Config) PromptConfigKind() string {
+	return kind
+}
+
+func (cfg Config) Initialize() (prompts.Prompt, error) {
+	if !tools.IsValidName(cfg.Name) {
+		return nil, fmt.Errorf("invalid prompt name: %s", cfg.Name)
+	}
+
+	messages := make([]prompts.Message, 0, len(cfg.Messages))
+	for i, m := range cfg.Messages {
+		switch m.Role {
+		case "":
+			m.Role = prompts.RoleUser
+		case prompts.RoleUser, prompts.RoleAssistant:
+		default:
+			return nil, fmt.Errorf("invalid role %q for message %d: must be one of %q or %q", m.Role, i, prompts.RoleUser, prompts.RoleAssistant)
+		}
+		messages = append(messages, m)
+	}
+
+	mcpManifest := prompts.McpManifest{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		Arguments:   prompts.ArgumentsMcpManifest(cfg.Arguments),
+	}
+
+	p := Prompt{
+		Name:        cfg.Name,
+		Kind:        kind,
+		Messages:    messages,
+		Arguments:   cfg.Arguments,
+		mcpManifest: mcpManifest,
+	}
+	return p, nil
+}
+
+// validate interface
+var _ prompts.Prompt = Prompt{}
+
+type Prompt struct {
+	Name      string            `yaml:"name"`
+	Kind      string            `yaml:"kind"`
+	Messages  []prompts.Message `yaml:"messages"`
+	Arguments tools.Parameters  `yaml:"arguments"`
+
+	mcpManifest prompts.McpManifest
+}
+
+func (p Prompt) ParseArgs(args map[string]string) (tools.ParamValues, error) {
+	return prompts.ParseArgs(p.Arguments, args)
+}
+
+func (p Prompt) Render(args tools.ParamValues) ([]prompts.Message, error) {
+	argsMap := args.AsMap()
+	messages := make([]prompts.Message, 0, len(p.Messages))
+	for _, m := range p.Messages {
+		content, err := tools.ResolveTemplateParams(p.Arguments, m.Content, argsMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render prompt %q: %w", p.Name, err)
+		}
+		messages = append(messages, prompts.Message{Role: m.Role, Content: content})
+	}
+	return messages, nil
+}
+
+func (p Prompt) McpManifest() prompts.McpManifest {
+	return p.mcpManifest
+}
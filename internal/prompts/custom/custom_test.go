@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package custom_test
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/prompts/custom"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestParseFromYamlCustom(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.PromptConfigs
+	}{
+		{
+			desc: "basic example",
+			in: `
+			prompts:
+				sales_report:
+					kind: custom
+					description: Summarize the sales of a country
+					messages:
+						- role: assistant
+						  content: You are a sales analyst.
+						- content: Summarize the {{.limit}} largest sales in {{.country}}.
+					arguments:
+						- name: country
+						  type: string
+						  description: the country to report on
+						- name: limit
+						  type: integer
+						  description: the number of sales
+						  default: 10
+			`,
+			want: server.PromptConfigs{
+				"sales_report": custom.Config{
+					Name:        "sales_report",
+					Kind:        "custom",
+					Description: "Summarize the sales of a country",
+					Messages: []prompts.Message{
+						{Role: "assistant", Content: "You are a sales analyst."},
+						{Content: "Summarize the {{.limit}} largest sales in {{.country}}."},
+					},
+					Arguments: []tools.Parameter{
+						tools.NewStringParameter("country", "the country to report on"),
+						tools.NewIntParameterWithDefault("limit", 10, "the number of sales"),
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Prompts server.PromptConfigs `yaml:"prompts"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Prompts); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestRenderCustom(t *testing.T) {
+	cfg := custom.Config{
+		Name:        "sales_report",
+		Kind:        "custom",
+		Description: "Summarize the sales of a country",
+		Messages: []prompts.Message{
+			{Role: "assistant", Content: "You are a sales analyst."},
+			{Content: "Summarize the {{.limit}} largest sales in {{.country}}."},
+		},
+		Arguments: []tools.Parameter{
+			tools.NewStringParameter("country", "the country to report on"),
+			tools.NewIntParameterWithDefault("limit", 10, "the number of sales"),
+		},
+	}
+	p, err := cfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+
+	wantManifest := prompts.McpManifest{
+		Name:        "sales_report",
+		Description: "Summarize the sales of a country",
+		Arguments: []prompts.McpArgument{
+			{Name: "country", Description: "the country to report on", Required: true},
+			{Name: "limit", Description: "the number of sales"},
+		},
+	}
+	if diff := cmp.Diff(wantManifest, p.McpManifest()); diff != "" {
+		t.Fatalf("incorrect manifest: diff %v", diff)
+	}
+
+	tcs := []struct {
+		desc  string
+		args  map[string]string
+		want  []prompts.Message
+		isErr bool
+	}{
+		{
+			desc: "default argument",
+			args: map[string]string{"country": "France"},
+			want: []prompts.Message{
+				{Role: "assistant", Content: "You are a sales analyst."},
+				{Role: "user", Content: "Summarize the 10 largest sales in France."},
+			},
+		},
+		{
+			desc: "all arguments",
+			args: map[string]string{"country": "Japan", "limit": "3"},
+			want: []prompts.Message{
+				{Role: "assistant", Content: "You are a sales analyst."},
+				{Role: "user", Content: "Summarize the 3 largest sales in Japan."},
+			},
+		},
+		{
+			desc:  "missing argument",
+			args:  map[string]string{},
+			isErr: true,
+		},
+		{
+			desc:  "invalid integer",
+			args:  map[string]string{"country": "Japan", "limit": "three"},
+			isErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			args, err := p.ParseArgs(tc.args)
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error parsing %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to parse arguments: %s", err)
+			}
+			got, err := p.Render(args)
+			if err != nil {
+				t.Fatalf("unable to render prompt: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("incorrect messages: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailInitializeCustom(t *testing.T) {
+	cfg := custom.Config{
+		Name:     "bad_role",
+		Kind:     "custom",
+		Messages: []prompts.Message{{Role: "system", Content: "hello"}},
+	}
+	if _, err := cfg.Initialize(); err == nil {
+		t.Fatalf("expected error for invalid role")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// PromptConfigFactory defines the signature for a function that creates and
+// decodes a specific prompt's configuration. It takes the context, the prompt's
+// name, and a YAML decoder to parse the config.
+type PromptConfigFactory func(ctx context.Context, name string, decoder *yaml.Decoder) (PromptConfig, error)
+
+var promptRegistry = make(map[string]PromptConfigFactory)
+
+// Register allows individual prompt packages to register their configuration
+// factory function. This is typically called from an init() function in the
+// prompt's package. It associates a 'kind' string with a function that can
+// produce the specific PromptConfig type. It returns true if the registration
+// was successful, and false if a prompt with the same kind was already
+// registered.
+func Register(kind string, factory PromptConfigFactory) bool {
+	if _, exists := promptRegistry[kind]; exists {
+		// Prompt with this kind already exists, do not overwrite.
+		return false
+	}
+	promptRegistry[kind] = factory
+	return true
+}
+
+// DecodeConfig looks up the registered factory for the given kind and uses it
+// to decode the prompt configuration.
+func DecodeConfig(ctx context.Context, kind string, name string, decoder *yaml.Decoder) (PromptConfig, error) {
+	factory, found := promptRegistry[kind]
+	if !found {
+		return nil, fmt.Errorf("unknown prompt kind: %q", kind)
+	}
+	promptConfig, err := factory(ctx, name, decoder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse prompt %q as kind %q: %w", name, kind, err)
+	}
+	return promptConfig, nil
+}
+
+type PromptConfig interface {
+	PromptConfigKind() string
+	Initialize() (Prompt, error)
+}
+
+type Prompt interface {
+	// ParseArgs parses the arguments provided by the client. MCP clients send
+	// every prompt argument as a string.
+	ParseArgs(map[string]string) (tools.ParamValues, error)
+	// Render returns the messages of the prompt with the arguments applied.
+	Render(tools.ParamValues) ([]Message, error)
+	McpManifest() McpManifest
+}
+
+// Roles that a prompt message can be sent as.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is a single message returned as part of a prompt.
+type Message struct {
+	Role    string `yaml:"role"`
+	Content string `yaml:"content" validate:"required"`
+}
+
+// Definition for a prompt the MCP client can get.
+type McpManifest struct {
+	// The name of the prompt.
+	Name string `json:"name"`
+	// A human-readable description of the prompt.
+	Description string `json:"description,omitempty"`
+	// A list of arguments to use for templating the prompt.
+	Arguments []McpArgument `json:"arguments,omitempty"`
+}
+
+// McpArgument describes an argument that a prompt can accept.
+type McpArgument struct {
+	// The name of the argument.
+	Name string `json:"name"`
+	// A human-readable description of the argument.
+	Description string `json:"description,omitempty"`
+	// Whether this argument must be provided.
+	Required bool `json:"required,omitempty"`
+	// Completion describes how values are suggested for the argument. It is
+	// not sent to clients.
+	Completion *tools.Completion `json:"-"`
+}
+
+// ArgumentsMcpManifest returns the MCP manifest of the prompt arguments.
+func ArgumentsMcpManifest(params tools.Parameters) []McpArgument {
+	args := make([]McpArgument, 0, len(params))
+	for _, m := range params.Manifest() {
+		args = append(args, McpArgument{
+			Name:        m.Name,
+			Description: m.Description,
+			// arguments that doesn't have a default value are required
+			Required:   m.Required,
+			Completion: m.Completion,
+		})
+	}
+	return args
+}
+
+// ValidateCompletionSources returns an error if the completion source of an
+// argument is not a tool of toolsMap.
+func ValidateCompletionSources(args []McpArgument, toolsMap map[string]tools.Tool) error {
+	params := make([]tools.ParameterManifest, 0, len(args))
+	for _, a := range args {
+		params = append(params, tools.ParameterManifest{Name: a.Name, Completion: a.Completion})
+	}
+	return tools.ValidateCompletionSources(params, toolsMap)
+}
+
+// ParseArgs parses the string arguments sent by an MCP client against the
+// parameters of a prompt. Values of non-string parameters are decoded as JSON,
+// e.g. "10" for an integer or `["a", "b"]` for an array.
+func ParseArgs(params tools.Parameters, args map[string]string) (tools.ParamValues, error) {
+	data := make(map[string]any, len(args))
+	for _, p := range params {
+		v, ok := args[p.GetName()]
+		if !ok {
+			continue
+		}
+		if p.GetType() == "string" {
+			data[p.GetName()] = v
+			continue
+		}
+		var decoded any
+		d := json.NewDecoder(strings.NewReader(v))
+		d.UseNumber()
+		if err := d.Decode(&decoded); err != nil {
+			return nil, fmt.Errorf("unable to parse argument %q as %s: %w", p.GetName(), p.GetType(), err)
+		}
+		data[p.GetName()] = decoded
+	}
+	return tools.ParseParams(params, data, map[string]map[string]any{})
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prompts
+
+import (
+	"fmt"
+)
+
+// PromptsetConfig scopes prompts to the toolset with the same name.
+type PromptsetConfig struct {
+	Name        string   `yaml:"name"`
+	PromptNames []string `yaml:",inline"`
+}
+
+// Promptset is the set of prompts that are served with a toolset.
+type Promptset struct {
+	Name        string            `yaml:"name"`
+	Prompts     map[string]Prompt `yaml:",inline"`
+	McpManifest []McpManifest     `yaml:",inline"`
+}
+
+func (p PromptsetConfig) Initialize(promptsMap map[string]Prompt) (Promptset, error) {
+	promptset := Promptset{
+		Name:        p.Name,
+		Prompts:     make(map[string]Prompt),
+		McpManifest: make([]McpManifest, 0, len(p.PromptNames)),
+	}
+	for _, promptName := range p.PromptNames {
+		prompt, ok := promptsMap[promptName]
+		if !ok {
+			return promptset, fmt.Errorf("prompt does not exist: %s", promptName)
+		}
+		promptset.Prompts[promptName] = prompt
+		promptset.McpManifest = append(promptset.McpManifest, prompt.McpManifest())
+	}
+	return promptset, nil
+}
@@ -15,7 +15,9 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -39,10 +41,32 @@ func apiRouter(s *Server) (chi.Router, error) {
 
 	r.Get("/toolset", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
 	r.Get("/toolset/{toolsetName}", func(w http.ResponseWriter, r *http.Request) { toolsetHandler(s, w, r) })
+	r.Get("/openapi.json", func(w http.ResponseWriter, r *http.Request) { openAPIHandler(s, w, r) })
+	r.Get("/toolset/{toolsetName}/openapi.json", func(w http.ResponseWriter, r *http.Request) { openAPIHandler(s, w, r) })
 
 	r.Route("/tool/{toolName}", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
 		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+		r.Post("/jobs", func(w http.ResponseWriter, r *http.Request) { toolJobCreateHandler(s, w, r) })
+		r.Post("/invoke:batch", func(w http.ResponseWriter, r *http.Request) { toolBatchInvokeHandler(s, w, r) })
+	})
+	// toolset-scoped routes only serve the tools of the toolset
+	r.Route("/toolset/{toolsetName}/tool/{toolName}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { toolGetHandler(s, w, r) })
+		r.Post("/invoke", func(w http.ResponseWriter, r *http.Request) { toolInvokeHandler(s, w, r) })
+		r.Post("/jobs", func(w http.ResponseWriter, r *http.Request) { toolJobCreateHandler(s, w, r) })
+		r.Post("/invoke:batch", func(w http.ResponseWriter, r *http.Request) { toolBatchInvokeHandler(s, w, r) })
+	})
+
+	r.Post("/invoke:batch", func(w http.ResponseWriter, r *http.Request) { batchInvokeHandler(s, w, r) })
+	r.Post("/toolset/{toolsetName}/invoke:batch", func(w http.ResponseWriter, r *http.Request) { batchInvokeHandler(s, w, r) })
+
+	r.Get("/sources", func(w http.ResponseWriter, r *http.Request) { sourcesHandler(s, w, r) })
+
+	r.Get("/jobs", func(w http.ResponseWriter, r *http.Request) { jobListHandler(s, w, r) })
+	r.Route("/jobs/{jobId}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { jobGetHandler(s, w, r) })
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { jobCancelHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -82,7 +106,25 @@ func toolsetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
-	render.JSON(w, r, toolset.Manifest)
+	// the tools are listed in pages, in the order of the toolset, leaving out
+	// the tools that the auth policies do not allow
+	toolNames := toolset.AllowedToolNames(claimsFromHeader(ctx, s, r.Header))
+	start, end, next, err := util.Paginate(len(toolNames), r.URL.Query().Get("pageToken"), s.toolsPageSize)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	m := tools.ToolsetManifest{
+		ServerVersion: toolset.Manifest.ServerVersion,
+		Description:   toolset.Manifest.Description,
+		ToolsManifest: make(map[string]tools.Manifest),
+		NextPageToken: next,
+	}
+	for _, name := range toolNames[start:end] {
+		m.ToolsManifest[name] = toolset.Manifest.ToolsManifest[name]
+	}
+	render.JSON(w, r, m)
 }
 
 // toolGetHandler handles requests for a single Tool.
@@ -118,6 +160,20 @@ func toolGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
 		return
 	}
+	// tools are only served through the toolsets that include them, routes
+	// without a toolset use the default toolset
+	toolset, err := checkToolsetIncludes(s, chi.URLParam(r, "toolsetName"), toolName)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	if !toolset.Allows(toolName, claimsFromHeader(ctx, s, r.Header)) {
+		err = errNotAllowed(toolName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
 	// TODO: this can be optimized later with some caching
 	m := tools.ToolsetManifest{
 		ServerVersion: s.version,
@@ -156,65 +212,28 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
-	tool, ok := s.ResourceMgr.GetTool(toolName)
-	if !ok {
-		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
-		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
-		return
-	}
-
-	// Tool authentication
-	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	claimsFromAuth := make(map[string]map[string]any)
-	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
-		claims, err := aS.GetClaimsFromHeader(ctx, r.Header)
-		if err != nil {
-			s.logger.DebugContext(ctx, err.Error())
-			continue
-		}
-		if claims == nil {
-			// authService not present in header
-			continue
-		}
-		claimsFromAuth[aS.GetName()] = claims
-	}
-
-	// Tool authorization check
-	verifiedAuthServices := make([]string, len(claimsFromAuth))
-	i := 0
-	for k := range claimsFromAuth {
-		verifiedAuthServices[i] = k
-		i++
-	}
-
-	// Check if any of the specified auth services is verified
-	isAuthorized := tool.Authorized(verifiedAuthServices)
-	if !isAuthorized {
-		err = fmt.Errorf("tool invocation not authorized. Please make sure your specify correct auth headers")
+	tool, params, _, code, err := parseInvocation(ctx, s, r, toolName)
+	if err != nil {
 		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		_ = render.Render(w, r, newErrResponse(err, code))
 		return
 	}
-	s.logger.DebugContext(ctx, "tool invocation authorized")
 
-	var data map[string]any
-	if err = util.DecodeJSON(r.Body, &data); err != nil {
-		render.Status(r, http.StatusBadRequest)
-		err = fmt.Errorf("request body was invalid JSON: %w", err)
+	format, err := streamFormat(r)
+	if err != nil {
 		s.logger.DebugContext(ctx, err.Error())
 		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
 		return
 	}
 
-	params, err := tool.ParseParams(data, claimsFromAuth)
-	if err != nil {
-		err = fmt.Errorf("provided parameters were invalid: %w", err)
-		s.logger.DebugContext(ctx, err.Error())
-		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+	// streamed results are written as they are produced by the tool
+	if format != "" {
+		span.SetAttributes(attribute.String("stream_format", format))
+		if err = writeResultStream(w, r, format, tools.Stream(ctx, tool, params)); err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+		}
 		return
 	}
-	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
 	res, err := tool.Invoke(ctx, params)
 	if err != nil {
@@ -235,6 +254,99 @@ func toolInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	_ = render.Render(w, r, &resultResponse{Result: string(resMarshal)})
 }
 
+// parseInvocation returns the tool of an invocation request and its parsed
+// parameters, along with the claims of the verified auth services. On
+// failure, it returns the HTTP status code of the error.
+func parseInvocation(ctx context.Context, s *Server, r *http.Request, toolName string) (tools.Tool, tools.ParamValues, map[string]map[string]any, int, error) {
+	// Tool authentication
+	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
+	claimsFromAuth := claimsFromHeader(ctx, s, r.Header)
+
+	tool, code, err := authorizeTool(ctx, s, chi.URLParam(r, "toolsetName"), toolName, claimsFromAuth)
+	if err != nil {
+		return nil, nil, nil, code, err
+	}
+
+	var data map[string]any
+	if err := util.DecodeJSON(r.Body, &data); err != nil {
+		return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("request body was invalid JSON: %w", err)
+	}
+
+	params, err := tool.ParseParams(data, claimsFromAuth)
+	if err != nil {
+		return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("provided parameters were invalid: %w", err)
+	}
+	s.logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
+	return tool, params, claimsFromAuth, http.StatusOK, nil
+}
+
+// authorizeTool returns a tool of a toolset if the verified auth services
+// authorize its invocation. On failure, it returns the HTTP status code of
+// the error.
+func authorizeTool(ctx context.Context, s *Server, toolsetName, toolName string, claimsFromAuth map[string]map[string]any) (tools.Tool, int, error) {
+	tool, ok := s.ResourceMgr.GetTool(toolName)
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
+	}
+	// tools are only served through the toolsets that include them, routes
+	// without a toolset use the default toolset
+	toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Errorf("toolset %q does not exist", toolsetName)
+	}
+	if err := toolset.Authorize(toolName, tool, claimsFromAuth); err != nil {
+		switch {
+		case errors.Is(err, tools.ErrToolNotIncluded):
+			return nil, http.StatusNotFound, err
+		case errors.Is(err, tools.ErrToolUnauthorized):
+			return nil, http.StatusUnauthorized, err
+		default:
+			return nil, http.StatusForbidden, err
+		}
+	}
+	s.logger.DebugContext(ctx, "tool invocation authorized")
+	return tool, http.StatusOK, nil
+}
+
+// claimsFromHeader maps the name of the auth services to the claims that they
+// retrieved from the headers. Auth services that are not present in the
+// headers, or that fail to verify them, are left out.
+func claimsFromHeader(ctx context.Context, s *Server, h http.Header) map[string]map[string]any {
+	claimsFromAuth := make(map[string]map[string]any)
+	for _, aS := range s.ResourceMgr.GetAuthServiceMap() {
+		claims, err := aS.GetClaimsFromHeader(ctx, h)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			continue
+		}
+		if claims == nil {
+			// authService not present in header
+			continue
+		}
+		claimsFromAuth[aS.GetName()] = claims
+	}
+	return claimsFromAuth
+}
+
+// checkToolsetIncludes returns the toolset, or an error if the toolset does
+// not exist or does not include the tool.
+func checkToolsetIncludes(s *Server, toolsetName, toolName string) (tools.Toolset, error) {
+	toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
+	if !ok {
+		return tools.Toolset{}, fmt.Errorf("toolset %q does not exist", toolsetName)
+	}
+	if !toolset.Contains(toolName) {
+		return tools.Toolset{}, fmt.Errorf("invalid tool name: tool with name %q is not part of toolset %q", toolName, toolsetName)
+	}
+	return toolset, nil
+}
+
+// errNotAllowed is the error of requests whose claims the auth policies of a
+// tool or toolset do not allow.
+func errNotAllowed(toolName string) error {
+	return fmt.Errorf("tool %q is %w", toolName, tools.ErrToolNotAllowed)
+}
+
 var _ render.Renderer = &resultResponse{} // Renderer interface for managing response payloads.
 
 // resultResponse is the response sent back when the tool was invocated successfully.
@@ -16,10 +16,13 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -123,6 +126,88 @@ func TestToolsetEndpoint(t *testing.T) {
 	}
 }
 
+func TestToolsetEndpointPagination(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	server.toolsPageSize = 2
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	// the tools are listed in pages, in the order of the toolset
+	var got []string
+	path := "/toolset/"
+	for page := 0; page < 2; page++ {
+		resp, body, err := runRequest(ts, http.MethodGet, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var m tools.ToolsetManifest
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("unable to parse ToolsetManifest: %s", err)
+		}
+		for _, name := range []string{tool1.Name, tool2.Name, tool3.Name} {
+			if _, ok := m.ToolsManifest[name]; ok {
+				got = append(got, name)
+			}
+		}
+		if page == 0 && m.NextPageToken == "" {
+			t.Fatalf("expected a next page token")
+		}
+		if page == 1 && m.NextPageToken != "" {
+			t.Fatalf("unexpected next page token on the last page: %s", m.NextPageToken)
+		}
+		path = "/toolset/?pageToken=" + m.NextPageToken
+	}
+	want := []string{tool1.Name, tool2.Name, tool3.Name}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tools: got %v, want %v", got, want)
+	}
+
+	// invalid page tokens are rejected
+	resp, _, err := runRequest(ts, http.MethodGet, "/toolset/?pageToken=invalid", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func TestToolsetEndpointDescription(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tc := tools.ToolsetConfig{Name: "described", ToolNames: []string{tool1.Name}, Description: "A described toolset."}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets[tc.Name] = toolset
+	r, shutdown := setUpServer(t, "api", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	_, body, err := runRequest(ts, http.MethodGet, "/toolset/described", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var m tools.ToolsetManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatalf("unable to parse ToolsetManifest: %s", err)
+	}
+	if m.Description != tc.Description {
+		t.Fatalf("unexpected description: got %q, want %q", m.Description, tc.Description)
+	}
+}
+
 func TestToolGetEndpoint(t *testing.T) {
 	mockTools := []MockTool{tool1, tool2}
 	toolsMap, toolsets := setUpResources(t, mockTools)
@@ -221,6 +306,7 @@ func TestToolInvokeEndpoint(t *testing.T) {
 
 	testCases := []struct {
 		name        string
+		toolsetName string
 		toolName    string
 		requestBody io.Reader
 		want        string
@@ -233,6 +319,30 @@ func TestToolInvokeEndpoint(t *testing.T) {
 			want:        "{result:[no_params]}\n",
 			isErr:       false,
 		},
+		{
+			name:        "tool1 of toolset",
+			toolsetName: "tool1_only",
+			toolName:    tool1.Name,
+			requestBody: bytes.NewBuffer([]byte(`{}`)),
+			want:        "{result:[no_params]}\n",
+			isErr:       false,
+		},
+		{
+			name:        "tool not part of toolset",
+			toolsetName: "tool1_only",
+			toolName:    tool2.Name,
+			requestBody: bytes.NewBuffer([]byte(`{"param1": 1, "param2": 2}`)),
+			want:        "",
+			isErr:       true,
+		},
+		{
+			name:        "invalid toolset",
+			toolsetName: "some_imaginary_toolset",
+			toolName:    tool1.Name,
+			requestBody: bytes.NewBuffer([]byte(`{}`)),
+			want:        "",
+			isErr:       true,
+		},
 		{
 			name:        "tool2",
 			toolName:    tool2.Name,
@@ -251,7 +361,11 @@ func TestToolInvokeEndpoint(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			resp, body, err := runRequest(ts, http.MethodPost, fmt.Sprintf("/tool/%s/invoke", tc.toolName), tc.requestBody, nil)
+			path := fmt.Sprintf("/tool/%s/invoke", tc.toolName)
+			if tc.toolsetName != "" {
+				path = fmt.Sprintf("/toolset/%s/tool/%s/invoke", tc.toolsetName, tc.toolName)
+			}
+			resp, body, err := runRequest(ts, http.MethodPost, path, tc.requestBody, nil)
 			if err != nil {
 				t.Fatalf("unexpected error during request: %s", err)
 			}
@@ -281,3 +395,182 @@ func TestToolInvokeEndpoint(t *testing.T) {
 		})
 	}
 }
+
+// mockStreamingTool is a MockTool that yields its Results one at a time,
+// followed by Err if it is set.
+type mockStreamingTool struct {
+	MockTool
+	Results []any
+	Err     error
+}
+
+func (t mockStreamingTool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
+
+func (t mockStreamingTool) InvokeStream(ctx context.Context, _ tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for _, r := range t.Results {
+			if !yield(r, nil) {
+				return
+			}
+		}
+		if t.Err != nil {
+			yield(nil, t.Err)
+		}
+	}
+}
+
+func TestToolInvokeStream(t *testing.T) {
+	rows := mockStreamingTool{
+		MockTool: MockTool{Name: "rows", Params: []tools.Parameter{}},
+		Results:  []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+	}
+	failing := mockStreamingTool{
+		MockTool: MockTool{Name: "failing", Params: []tools.Parameter{}},
+		Results:  []any{map[string]any{"id": 1}},
+		Err:      fmt.Errorf("connection lost"),
+	}
+	failingFirst := mockStreamingTool{
+		MockTool: MockTool{Name: "failing_first", Params: []tools.Parameter{}},
+		Err:      fmt.Errorf("connection lost"),
+	}
+	empty := mockStreamingTool{
+		MockTool: MockTool{Name: "empty", Params: []tools.Parameter{}},
+	}
+	toolsMap := map[string]tools.Tool{tool1.Name: tool1}
+	for _, tool := range []mockStreamingTool{rows, failing, failingFirst, empty} {
+		toolsMap[tool.Name] = tool
+	}
+	toolset, err := tools.ToolsetConfig{Name: "", ToolNames: []string{tool1.Name, rows.Name, failing.Name, failingFirst.Name, empty.Name}}.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	r, shutdown := setUpServer(t, "api", toolsMap, map[string]tools.Toolset{"": toolset})
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name            string
+		toolName        string
+		query           string
+		header          map[string]string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+		wantTrailer     string
+	}{
+		{
+			name:            "ndjson",
+			toolName:        rows.Name,
+			query:           "?stream=ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:            "ndjson from accept header",
+			toolName:        rows.Name,
+			header:          map[string]string{"Accept": "application/x-ndjson"},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:            "ndjson of non-streaming tool",
+			toolName:        tool1.Name,
+			query:           "?stream=ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "\"no_params\"\n",
+		},
+		{
+			name:            "ndjson without results",
+			toolName:        empty.Name,
+			query:           "?stream=ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "",
+		},
+		{
+			name:            "ndjson error after first result",
+			toolName:        failing.Name,
+			query:           "?stream=ndjson",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "{\"id\":1}\n",
+			wantTrailer:     "error while invoking tool: connection lost",
+		},
+		{
+			name:            "ndjson error before first result",
+			toolName:        failingFirst.Name,
+			query:           "?stream=ndjson",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        "{\"status\":\"Bad Request\",\"error\":\"error while invoking tool: connection lost\"}\n",
+		},
+		{
+			name:            "sse",
+			toolName:        rows.Name,
+			query:           "?stream=sse",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/event-stream",
+			wantBody:        "event: result\ndata: {\"id\":1}\n\nevent: result\ndata: {\"id\":2}\n\nevent: done\ndata: {}\n\n",
+		},
+		{
+			name:            "sse from accept header",
+			toolName:        rows.Name,
+			header:          map[string]string{"Accept": "text/event-stream"},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/event-stream",
+			wantBody:        "event: result\ndata: {\"id\":1}\n\nevent: result\ndata: {\"id\":2}\n\nevent: done\ndata: {}\n\n",
+		},
+		{
+			name:            "sse error after first result",
+			toolName:        failing.Name,
+			query:           "?stream=sse",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/event-stream",
+			wantBody:        "event: result\ndata: {\"id\":1}\n\nevent: error\ndata: {\"status\":\"Bad Request\",\"error\":\"error while invoking tool: connection lost\"}\n\n",
+		},
+		{
+			name:            "query takes precedence over accept header",
+			toolName:        rows.Name,
+			query:           "?stream=ndjson",
+			header:          map[string]string{"Accept": "text/event-stream"},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:            "invalid stream format",
+			toolName:        rows.Name,
+			query:           "?stream=csv",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "application/json",
+			wantBody:        "{\"status\":\"Bad Request\",\"error\":\"invalid stream format \\\"csv\\\": must be \\\"ndjson\\\" or \\\"sse\\\"\"}\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := fmt.Sprintf("/tool/%s/invoke%s", tc.toolName, tc.query)
+			resp, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBufferString(`{}`), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if contentType := resp.Header.Get("Content-Type"); contentType != tc.wantContentType {
+				t.Fatalf("unexpected content-type header: got %q, want %q", contentType, tc.wantContentType)
+			}
+			if got := string(body); got != tc.wantBody {
+				t.Fatalf("unexpected body: got %q, want %q", got, tc.wantBody)
+			}
+			if got := resp.Trailer.Get("Toolbox-Error"); got != tc.wantTrailer {
+				t.Fatalf("unexpected error trailer: got %q, want %q", got, tc.wantTrailer)
+			}
+		})
+	}
+}
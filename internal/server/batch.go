@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// batchInvocation is an invocation of a batch request.
+type batchInvocation struct {
+	Tool   string         `json:"tool"`
+	Params map[string]any `json:"params"`
+}
+
+// batchResponse is the response of a batch request, with the outcome of each
+// invocation in the order of the request.
+type batchResponse struct {
+	Results []batchItemResponse `json:"results"`
+}
+
+// batchItemResponse is the outcome of an invocation of a batch request. It
+// holds either the result of the invocation as the invoke endpoint, or its
+// error as the error responses.
+type batchItemResponse struct {
+	Result     string `json:"result,omitempty"`
+	StatusText string `json:"status,omitempty"`
+	ErrorText  string `json:"error,omitempty"`
+}
+
+func newBatchItemError(err error, code int) batchItemResponse {
+	return batchItemResponse{StatusText: http.StatusText(code), ErrorText: err.Error()}
+}
+
+// toolBatchInvokeHandler handles the API request to invoke a tool with each
+// of the parameters of an array.
+func toolBatchInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/tool/invoke/batch")
+	r = r.WithContext(ctx)
+
+	toolName := chi.URLParam(r, "toolName")
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	// the whole batch fails if the tool can not be invoked at all
+	claimsFromAuth := claimsFromHeader(ctx, s, r.Header)
+	_, code, err := authorizeTool(ctx, s, toolsetName, toolName, claimsFromAuth)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, code))
+		return
+	}
+
+	var data []map[string]any
+	if err = util.DecodeJSON(r.Body, &data); err != nil {
+		err = fmt.Errorf("request body was invalid JSON: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	invocations := make([]batchInvocation, 0, len(data))
+	for _, params := range data {
+		invocations = append(invocations, batchInvocation{Tool: toolName, Params: params})
+	}
+	renderBatch(ctx, s, w, r, toolsetName, invocations, claimsFromAuth)
+}
+
+// batchInvokeHandler handles the API request to invoke several tools.
+func batchInvokeHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/invoke/batch")
+	r = r.WithContext(ctx)
+
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	var invocations []batchInvocation
+	if err = util.DecodeJSON(r.Body, &invocations); err != nil {
+		err = fmt.Errorf("request body was invalid JSON: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	renderBatch(ctx, s, w, r, toolsetName, invocations, claimsFromHeader(ctx, s, r.Header))
+}
+
+// renderBatch runs the invocations of a batch request, up to
+// `s.apiBatchConcurrency` at once, and renders their outcomes. The failure of
+// an invocation does not fail the batch, but batches of more than
+// `s.apiBatchMaxSize` invocations are rejected.
+func renderBatch(ctx context.Context, s *Server, w http.ResponseWriter, r *http.Request, toolsetName string, invocations []batchInvocation, claimsFromAuth map[string]map[string]any) {
+	if len(invocations) == 0 {
+		err := fmt.Errorf("batch request is empty")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+	if s.apiBatchMaxSize > 0 && len(invocations) > s.apiBatchMaxSize {
+		err := fmt.Errorf("batch request has %d invocations, more than the maximum of %d", len(invocations), s.apiBatchMaxSize)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	results := make([]batchItemResponse, len(invocations))
+	sem := make(chan struct{}, max(s.apiBatchConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, inv := range invocations {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = invokeBatchItem(ctx, s, toolsetName, inv, claimsFromAuth)
+		}()
+	}
+	wg.Wait()
+	render.JSON(w, r, batchResponse{Results: results})
+}
+
+// invokeBatchItem runs an invocation of a batch request through the same
+// checks as the invoke endpoint.
+func invokeBatchItem(ctx context.Context, s *Server, toolsetName string, inv batchInvocation, claimsFromAuth map[string]map[string]any) batchItemResponse {
+	ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/tool/invoke")
+	span.SetAttributes(attribute.String("tool_name", inv.Tool))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		s.instrumentation.ToolInvoke.Add(
+			ctx,
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", inv.Tool)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+	}()
+
+	tool, code, err := authorizeTool(ctx, s, toolsetName, inv.Tool, claimsFromAuth)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		return newBatchItemError(err, code)
+	}
+	params, err := tool.ParseParams(inv.Params, claimsFromAuth)
+	if err != nil {
+		err = fmt.Errorf("provided parameters were invalid: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		return newBatchItemError(err, http.StatusBadRequest)
+	}
+	res, err := tool.Invoke(ctx, params)
+	if err != nil {
+		err = fmt.Errorf("error while invoking tool: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		return newBatchItemError(err, http.StatusBadRequest)
+	}
+	resMarshal, err := json.Marshal(res)
+	if err != nil {
+		err = fmt.Errorf("unable to marshal result: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		return newBatchItemError(err, http.StatusInternalServerError)
+	}
+	return batchItemResponse{Result: string(resMarshal)}
+}
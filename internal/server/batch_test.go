@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestBatchInvokeEndpoints(t *testing.T) {
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	authTool := MockTool{Name: "auth_required", Params: []tools.Parameter{}, AuthRequired: []string{authService.Name}}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2, authTool})
+	authServices := map[string]auth.AuthService{authService.Name: authService}
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	server.apiBatchMaxSize = 4
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		header     map[string]string
+		wantStatus int
+		want       []batchItemResponse
+	}{
+		{
+			name:       "tool batch",
+			path:       "/tool/some_params/invoke:batch",
+			body:       `[{"param1": 1, "param2": 2}, {"param1": "one", "param2": 2}, {"param1": 3, "param2": 4}]`,
+			wantStatus: http.StatusOK,
+			want: []batchItemResponse{
+				{Result: `["some_params"]`},
+				{StatusText: "Bad Request", ErrorText: `provided parameters were invalid: unable to parse value for "param1": "one" not type "integer"`},
+				{Result: `["some_params"]`},
+			},
+		},
+		{
+			name:       "tool batch of toolset",
+			path:       "/toolset/tool1_only/tool/no_params/invoke:batch",
+			body:       `[{}, {}]`,
+			wantStatus: http.StatusOK,
+			want:       []batchItemResponse{{Result: `["no_params"]`}, {Result: `["no_params"]`}},
+		},
+		{
+			name:       "tool batch of tool not part of toolset",
+			path:       "/toolset/tool1_only/tool/some_params/invoke:batch",
+			body:       `[{"param1": 1, "param2": 2}]`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "tool batch of invalid tool",
+			path:       "/tool/some_imaginary_tool/invoke:batch",
+			body:       `[{}]`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "tool batch not authorized",
+			path:       "/tool/auth_required/invoke:batch",
+			body:       `[{}]`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "tool batch authorized",
+			path:       "/tool/auth_required/invoke:batch",
+			body:       `[{}]`,
+			header:     map[string]string{"my-auth_token": "valid-token"},
+			wantStatus: http.StatusOK,
+			want:       []batchItemResponse{{Result: `["auth_required"]`}},
+		},
+		{
+			name:       "empty tool batch",
+			path:       "/tool/no_params/invoke:batch",
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "tool batch above the maximum size",
+			path:       "/tool/no_params/invoke:batch",
+			body:       `[{}, {}, {}, {}, {}]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid tool batch",
+			path:       "/tool/no_params/invoke:batch",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "multi-tool batch",
+			path:       "/invoke:batch",
+			body:       `[{"tool": "no_params"}, {"tool": "some_params", "params": {"param1": 1, "param2": 2}}, {"tool": "some_imaginary_tool"}, {"tool": "auth_required", "params": {}}]`,
+			wantStatus: http.StatusOK,
+			want: []batchItemResponse{
+				{Result: `["no_params"]`},
+				{Result: `["some_params"]`},
+				{StatusText: "Not Found", ErrorText: `invalid tool name: tool with name "some_imaginary_tool" does not exist`},
+				{StatusText: "Unauthorized", ErrorText: "tool invocation not authorized. Please make sure your specify correct auth headers"},
+			},
+		},
+		{
+			name:       "multi-tool batch of toolset",
+			path:       "/toolset/tool1_only/invoke:batch",
+			body:       `[{"tool": "no_params"}, {"tool": "some_params", "params": {"param1": 1, "param2": 2}}]`,
+			wantStatus: http.StatusOK,
+			want: []batchItemResponse{
+				{Result: `["no_params"]`},
+				{StatusText: "Not Found", ErrorText: `invalid tool name: tool with name "some_params" is not part of toolset "tool1_only"`},
+			},
+		},
+		{
+			name:       "empty multi-tool batch",
+			path:       "/invoke:batch",
+			body:       `[]`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "multi-tool batch above the maximum size",
+			path:       "/invoke:batch",
+			body:       `[{"tool": "no_params"}, {"tool": "no_params"}, {"tool": "no_params"}, {"tool": "no_params"}, {"tool": "no_params"}]`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, tc.path, bytes.NewBufferString(tc.body), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got batchResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Results); diff != "" {
+				t.Fatalf("incorrect results (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatchInvokeConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	slowTool := MockTool{
+		Name:   "slow",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(context.Context) ([]any, error) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return []any{"slow"}, nil
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{slowTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	server.apiBatchConcurrency = 2
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	resp, body, err := runRequest(ts, http.MethodPost, "/tool/slow/invoke:batch", bytes.NewBufferString(`[{}, {}, {}, {}, {}, {}]`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+	var got batchResponse
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to parse response: %s", err)
+	}
+	if len(got.Results) != 6 {
+		t.Fatalf("unexpected number of results: got %d, want 6", len(got.Results))
+	}
+	if maxRunning != 2 {
+		t.Fatalf("unexpected number of concurrent invocations: got %d, want 2", maxRunning)
+	}
+}
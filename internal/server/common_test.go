@@ -22,9 +22,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -36,13 +39,22 @@ var _ tools.Tool = &MockTool{}
 
 // MockTool is used to mock tools in tests
 type MockTool struct {
-	Name        string
-	Description string
-	Params      []tools.Parameter
-	manifest    tools.Manifest
+	Name         string
+	Title        string
+	Description  string
+	Params       []tools.Parameter
+	OutputSchema *tools.McpSchema
+	Annotations  *tools.ToolAnnotations
+	AuthRequired []string
+	// InvokeFunc replaces the default invocation, which returns the name.
+	InvokeFunc func(context.Context) ([]any, error)
+	manifest   tools.Manifest
 }
 
-func (t MockTool) Invoke(context.Context, tools.ParamValues) ([]any, error) {
+func (t MockTool) Invoke(ctx context.Context, _ tools.ParamValues) ([]any, error) {
+	if t.InvokeFunc != nil {
+		return t.InvokeFunc(ctx)
+	}
 	mock := []any{t.Name}
 	return mock, nil
 }
@@ -53,14 +65,10 @@ func (t MockTool) ParseParams(data map[string]any, claimsMap map[string]map[stri
 }
 
 func (t MockTool) Manifest() tools.Manifest {
-	pMs := make([]tools.ParameterManifest, 0, len(t.Params))
-	for _, p := range t.Params {
-		pMs = append(pMs, p.Manifest())
-	}
-	return tools.Manifest{Description: t.Description, Parameters: pMs}
+	return tools.Manifest{Description: t.Description, Parameters: tools.Parameters(t.Params).Manifest(), AuthRequired: t.AuthRequired}
 }
 func (t MockTool) Authorized(verifiedAuthServices []string) bool {
-	return true
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
 }
 
 func (t MockTool) McpManifest() tools.McpManifest {
@@ -80,9 +88,12 @@ func (t MockTool) McpManifest() tools.McpManifest {
 	}
 
 	return tools.McpManifest{
-		Name:        t.Name,
-		Description: t.Description,
-		InputSchema: toolsSchema,
+		Name:         t.Name,
+		Title:        t.Title,
+		Description:  t.Description,
+		InputSchema:  toolsSchema,
+		OutputSchema: t.OutputSchema,
+		Annotations:  t.Annotations,
 	}
 }
 
@@ -107,6 +118,67 @@ var tool3 = MockTool{
 	},
 }
 
+var _ auth.AuthService = MockAuthService{}
+
+// MockAuthService is used to mock auth services in tests. Its token header is
+// verified if it matches Token.
+type MockAuthService struct {
+	Name  string
+	Token string
+}
+
+func (a MockAuthService) AuthServiceKind() string {
+	return "mock"
+}
+
+func (a MockAuthService) GetName() string {
+	return a.Name
+}
+
+func (a MockAuthService) GetClaimsFromHeader(ctx context.Context, h http.Header) (map[string]any, error) {
+	token := h.Get(a.Name + "_token")
+	if token == "" {
+		return nil, nil
+	}
+	if token != a.Token {
+		return nil, fmt.Errorf("invalid token for auth service %q", a.Name)
+	}
+	return map[string]any{"sub": "some_user"}, nil
+}
+
+var _ sources.ResourceProvider = &MockSource{}
+
+// MockSource is used to mock sources that publish resources in tests
+type MockSource struct {
+	Name   string
+	Tables map[string]string
+}
+
+func (s *MockSource) SourceKind() string {
+	return "mock"
+}
+
+func (s *MockSource) ListResources(context.Context) ([]sources.Resource, error) {
+	tables := make([]string, 0, len(s.Tables))
+	for t := range s.Tables {
+		tables = append(tables, t)
+	}
+	return sources.NewTableResources(s.Name, s.SourceKind(), tables), nil
+}
+
+func (s *MockSource) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	table, err := sources.TableFromResourceURI(s.Name, uri)
+	if err != nil {
+		return sources.ResourceContents{}, err
+	}
+	dataType, ok := s.Tables[table]
+	if !ok {
+		return sources.ResourceContents{}, fmt.Errorf("table %q does not exist", table)
+	}
+	schema := sources.TableSchema{Source: s.Name, Name: table, Columns: []sources.ColumnSchema{{Name: "id", DataType: dataType}}}
+	return sources.NewTableSchemaContents(uri, schema)
+}
+
 // setUpResources setups resources to test against
 func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool, map[string]tools.Toolset) {
 	toolsMap := make(map[string]tools.Tool)
@@ -135,6 +207,29 @@ func setUpResources(t *testing.T, mockTools []MockTool) (map[string]tools.Tool,
 
 // setUpServer create a new server with tools and toolsets that are given
 func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, toolsets map[string]tools.Toolset) (chi.Router, func()) {
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, tools, toolsets, nil, nil))
+
+	var r chi.Router
+	var err error
+	switch router {
+	case "api":
+		r, err = apiRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize api router: %s", err)
+		}
+	case "mcp":
+		r, err = mcpRouter(server)
+		if err != nil {
+			t.Fatalf("unable to initialize mcp router: %s", err)
+		}
+	default:
+		t.Fatalf("unknown router")
+	}
+	return r, shutdown
+}
+
+// newTestServer create a new server that serves the resources of resourceManager
+func newTestServer(t *testing.T, resourceManager *ResourceManager) (*Server, func()) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	testLogger, err := log.NewStdLogger(os.Stdout, os.Stderr, "info")
@@ -153,32 +248,23 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 	}
 
 	sseManager := newSseManager(ctx)
-
-	resourceManager := NewResourceManager(nil, nil, tools, toolsets)
-
-	server := Server{
-		version:         fakeVersionString,
-		logger:          testLogger,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+	streamableManager := newStreamableManager(ctx)
+	jobManager, err := newJobManager(ctx, newMemoryJobStore(), time.Hour, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create job manager: %s", err)
 	}
 
-	var r chi.Router
-	switch router {
-	case "api":
-		r, err = apiRouter(&server)
-		if err != nil {
-			t.Fatalf("unable to initialize api router: %s", err)
-		}
-	case "mcp":
-		r, err = mcpRouter(&server)
-		if err != nil {
-			t.Fatalf("unable to initialize mcp router: %s", err)
-		}
-	default:
-		t.Fatalf("unknown router")
+	server := &Server{
+		version:           fakeVersionString,
+		logger:            testLogger,
+		instrumentation:   instrumentation,
+		sseManager:        sseManager,
+		streamableManager: streamableManager,
+		jobManager:        jobManager,
+		ResourceMgr:       resourceManager,
 	}
+	server.ResourceMgr.Subscribe(server.notifyListChanged)
+
 	shutdown := func() {
 		// cancel context
 		cancel()
@@ -189,7 +275,7 @@ func setUpServer(t *testing.T, router string, tools map[string]tools.Tool, tools
 		}
 	}
 
-	return r, shutdown
+	return server, shutdown
 }
 
 func runServer(r chi.Router, tls bool) *httptest.Server {
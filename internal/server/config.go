@@ -17,10 +17,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/auth"
-	"github.com/googleapis/genai-toolbox/internal/auth/google"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
@@ -41,6 +42,8 @@ type ServerConfig struct {
 	ToolConfigs ToolConfigs
 	// ToolsetConfigs defines what tools are available.
 	ToolsetConfigs ToolsetConfigs
+	// PromptConfigs defines what prompts are available.
+	PromptConfigs PromptConfigs
 	// LoggingFormat defines whether structured loggings are used.
 	LoggingFormat logFormat
 	// LogLevel defines the levels to log.
@@ -55,6 +58,41 @@ type ServerConfig struct {
 	Stdio bool
 	// DisableReload indicates if the user has disabled dynamic reloading for Toolbox.
 	DisableReload bool
+	// McpBatchConcurrency is the maximum number of messages of an MCP batch
+	// request that are processed concurrently.
+	McpBatchConcurrency int
+	// ApiBatchConcurrency is the maximum number of invocations of an API
+	// batch request that run concurrently.
+	ApiBatchConcurrency int
+	// ApiBatchMaxSize is the maximum number of invocations of an API batch
+	// request. Batches of any size are accepted if it is 0.
+	ApiBatchMaxSize int
+	// ToolsPageSize is the maximum number of tools listed per page. All tools
+	// are listed at once if it is 0.
+	ToolsPageSize int
+	// AdminAuthServices are the auth services whose tokens authorize the
+	// requests to the admin endpoints. The admin endpoints are disabled if it
+	// is empty.
+	AdminAuthServices []string
+	// JobsStore is the path of the SQLite database that stores the jobs. Jobs
+	// are stored in memory if it is empty.
+	JobsStore string
+	// JobsTTL is how long finished jobs are kept.
+	JobsTTL time.Duration
+	// JobsMax is the maximum number of stored jobs, and JobsMaxRunning the
+	// maximum number of running jobs. They are unlimited if 0.
+	JobsMax        int
+	JobsMaxRunning int
+	// TLSCertFile and TLSKeyFile are the paths of the PEM certificate and key
+	// of the server. The server serves HTTPS if they are set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile is the path of the PEM certificates of the CAs that
+	// verify the certificates of clients.
+	TLSClientCAFile string
+	// TLSRequireClientCert indicates if clients must present a certificate
+	// verified by the client CAs.
+	TLSRequireClientCert bool
 }
 
 type logFormat string
@@ -178,20 +216,20 @@ func (c *AuthServiceConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(i
 			return fmt.Errorf("missing 'kind' field for %q", name)
 		}
 
+		kindStr, ok := kind.(string)
+		if !ok {
+			return fmt.Errorf("invalid 'kind' field for %q (must be a string)", name)
+		}
+
 		dec, err := util.NewStrictDecoder(v)
 		if err != nil {
 			return fmt.Errorf("error creating decoder: %w", err)
 		}
-		switch kind {
-		case google.AuthServiceKind:
-			actual := google.Config{Name: name}
-			if err := dec.DecodeContext(ctx, &actual); err != nil {
-				return fmt.Errorf("unable to parse as %q: %w", kind, err)
-			}
-			(*c)[name] = actual
-		default:
-			return fmt.Errorf("%q is not a valid kind of auth source", kind)
+		authServiceConfig, err := auth.DecodeConfig(ctx, kindStr, name, dec)
+		if err != nil {
+			return err
 		}
+		(*c)[name] = authServiceConfig
 	}
 	return nil
 }
@@ -221,6 +259,19 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 			v["authRequired"] = []string{}
 		}
 
+		// the auth policies are enforced by the server for every kind of tool
+		var policies tools.AuthPolicies
+		if rawPolicies, ok := v["authPolicies"]; ok {
+			delete(v, "authPolicies")
+			dec, err := util.NewStrictDecoder(rawPolicies)
+			if err != nil {
+				return fmt.Errorf("error creating YAML decoder for auth policies of tool %q: %w", name, err)
+			}
+			if err := dec.DecodeContext(ctx, &policies); err != nil {
+				return fmt.Errorf("unable to parse auth policies of tool %q: %w", name, err)
+			}
+		}
+
 		kindVal, ok := v["kind"]
 		if !ok {
 			return fmt.Errorf("missing 'kind' field for tool %q", name)
@@ -239,6 +290,9 @@ func (c *ToolConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interfac
 		if err != nil {
 			return err
 		}
+		if policies != nil {
+			toolCfg = tools.AuthPolicyToolConfig{ToolConfig: toolCfg, AuthPolicies: policies}
+		}
 		(*c)[name] = toolCfg
 	}
 	return nil
@@ -253,13 +307,91 @@ var _ yaml.InterfaceUnmarshalerContext = &ToolsetConfigs{}
 func (c *ToolsetConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
 	*c = make(ToolsetConfigs)
 
-	var raw map[string][]string
+	// a toolset is either a list of tool names, or a mapping with the list
+	// of tools and options
+	var raw map[string]util.DelayedUnmarshaler
 	if err := unmarshal(&raw); err != nil {
 		return err
 	}
 
-	for name, toolList := range raw {
-		(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+	for name, u := range raw {
+		var toolList []string
+		if err := u.Unmarshal(&toolList); err == nil {
+			(*c)[name] = tools.ToolsetConfig{Name: name, ToolNames: toolList}
+			continue
+		}
+		var m map[string]any
+		if err := u.Unmarshal(&m); err != nil {
+			return fmt.Errorf("unable to parse toolset %q: %w", name, err)
+		}
+		var v struct {
+			Tools        []string `yaml:"tools"`
+			Strict       bool     `yaml:"strict"`
+			Description  string   `yaml:"description"`
+			Instructions string   `yaml:"instructions"`
+
+			AuthPolicies tools.AuthPolicies `yaml:"authPolicies"`
+			Sources      []string           `yaml:"sources"`
+		}
+		dec, err := util.NewStrictDecoder(m)
+		if err != nil {
+			return fmt.Errorf("error creating YAML decoder for toolset %q: %w", name, err)
+		}
+		if err := dec.DecodeContext(ctx, &v); err != nil {
+			return fmt.Errorf("unable to parse toolset %q: %w", name, err)
+		}
+		(*c)[name] = tools.ToolsetConfig{
+			Name:         name,
+			ToolNames:    v.Tools,
+			Strict:       v.Strict,
+			Description:  v.Description,
+			Instructions: v.Instructions,
+			AuthPolicies: v.AuthPolicies,
+			Sources:      v.Sources,
+		}
+	}
+	return nil
+}
+
+// PromptConfigs is a type used to allow unmarshal of the prompt configs
+type PromptConfigs map[string]prompts.PromptConfig
+
+// validate interface
+var _ yaml.InterfaceUnmarshalerContext = &PromptConfigs{}
+
+func (c *PromptConfigs) UnmarshalYAML(ctx context.Context, unmarshal func(interface{}) error) error {
+	*c = make(PromptConfigs)
+	// Parse the 'kind' fields for each prompt
+	var raw map[string]util.DelayedUnmarshaler
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for name, u := range raw {
+		var v map[string]any
+		if err := u.Unmarshal(&v); err != nil {
+			return fmt.Errorf("unable to unmarshal %q: %w", name, err)
+		}
+
+		kindVal, ok := v["kind"]
+		if !ok {
+			return fmt.Errorf("missing 'kind' field for prompt %q", name)
+		}
+		kindStr, ok := kindVal.(string)
+		if !ok {
+			return fmt.Errorf("invalid 'kind' field for prompt %q (must be a string)", name)
+		}
+
+		yamlDecoder, err := util.NewStrictDecoder(v)
+		if err != nil {
+			return fmt.Errorf("error creating YAML decoder for prompt %q: %w", name, err)
+		}
+
+		promptCfg, err := prompts.DecodeConfig(ctx, kindStr, name, yamlDecoder)
+		if err != nil {
+			return err
+		}
+		(*c)[name] = promptCfg
 	}
 	return nil
 }
@@ -0,0 +1,227 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// sourceHealthTimeout bounds the health check of a source.
+const sourceHealthTimeout = 5 * time.Second
+
+// readinessInterval is the interval during which the readiness probe serves
+// the last health check of the sources instead of checking them again.
+const readinessInterval = 10 * time.Second
+
+// Health statuses of sources.
+const (
+	sourceHealthy   = "healthy"
+	sourceUnhealthy = "unhealthy"
+	// sourceUnknown is the status of sources that do not check their health.
+	sourceUnknown = "unknown"
+)
+
+// sourceStatus is the health of a source.
+type sourceStatus struct {
+	Name      string             `json:"name"`
+	Kind      string             `json:"kind"`
+	Status    string             `json:"status"`
+	Error     string             `json:"error,omitempty"`
+	CheckedAt *time.Time         `json:"checkedAt,omitempty"`
+	Latency   string             `json:"latency,omitempty"`
+	Pool      *sources.PoolStats `json:"pool,omitempty"`
+}
+
+// sourcesResponse is the response of the sources endpoint.
+type sourcesResponse struct {
+	Sources []sourceStatus `json:"sources"`
+}
+
+// checkSources checks the health of the sources concurrently, and returns
+// their statuses in the order of their names.
+func checkSources(ctx context.Context, srcs map[string]sources.Source) []sourceStatus {
+	names := slices.Sorted(maps.Keys(srcs))
+	statuses := make([]sourceStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = checkSource(ctx, name, srcs[name])
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// checkSource checks the health of a source.
+func checkSource(ctx context.Context, name string, s sources.Source) sourceStatus {
+	status := sourceStatus{Name: name, Kind: s.SourceKind(), Status: sourceUnknown}
+	if p, ok := s.(sources.PoolStatsProvider); ok {
+		stats := p.PoolStats()
+		status.Pool = &stats
+	}
+	hc, ok := s.(sources.HealthChecker)
+	if !ok {
+		return status
+	}
+	ctx, cancel := context.WithTimeout(ctx, sourceHealthTimeout)
+	defer cancel()
+	start := time.Now()
+	err := hc.CheckHealth(ctx)
+	status.CheckedAt = &start
+	status.Latency = time.Since(start).String()
+	status.Status = sourceHealthy
+	if err != nil {
+		status.Status = sourceUnhealthy
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// readiness caches the health checks of the sources for the readiness probe,
+// so that probes do not fan out to the sources on every request. A single
+// check runs at a time, in the background of the probes.
+type readiness struct {
+	mu sync.Mutex
+	// interval overrides readinessInterval if set.
+	interval  time.Duration
+	checkedAt time.Time
+	unhealthy []sourceStatus
+	// checking is closed once the running check ends, and is nil if no check
+	// runs.
+	checking chan struct{}
+}
+
+// unhealthySources returns the unhealthy sources of the last check, and
+// starts a new check in the background if the last one is older than the
+// interval. It only waits for the check if no check has ended yet.
+func (rd *readiness) unhealthySources(ctx context.Context, s *Server) ([]sourceStatus, error) {
+	rd.mu.Lock()
+	if !rd.checkedAt.IsZero() && time.Since(rd.checkedAt) < cmp.Or(rd.interval, readinessInterval) {
+		defer rd.mu.Unlock()
+		return rd.unhealthy, nil
+	}
+	if rd.checking == nil {
+		rd.checking = make(chan struct{})
+		go rd.check(s, rd.checking)
+	}
+	if !rd.checkedAt.IsZero() {
+		defer rd.mu.Unlock()
+		return rd.unhealthy, nil
+	}
+	checking := rd.checking
+	rd.mu.Unlock()
+
+	select {
+	case <-checking:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	return rd.unhealthy, nil
+}
+
+// check checks the health of the sources, logs the unhealthy ones and closes
+// done. The details of unhealthy sources are only logged, since the probe is
+// not authenticated.
+func (rd *readiness) check(s *Server, done chan struct{}) {
+	defer close(done)
+	// the check is shared by the probes, and is not cancelled with any of them
+	ctx := context.Background()
+	var unhealthy []sourceStatus
+	for _, status := range checkSources(ctx, s.ResourceMgr.GetSourcesMap()) {
+		if status.Status == sourceUnhealthy {
+			unhealthy = append(unhealthy, status)
+			s.logger.WarnContext(ctx, fmt.Sprintf("source %q is unhealthy: %s", status.Name, status.Error))
+		}
+	}
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.unhealthy, rd.checkedAt, rd.checking = unhealthy, time.Now(), nil
+}
+
+// addHealthRoutes adds the liveness and readiness probes to a router.
+func addHealthRoutes(s *Server, r chi.Router) {
+	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { healthzHandler(s, w, r) })
+	r.Get("/readyz", func(w http.ResponseWriter, r *http.Request) { readyzHandler(s, w, r) })
+}
+
+// healthzHandler handles the liveness probe, which succeeds as long as the
+// server is up.
+func healthzHandler(_ *Server, w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("ok"))
+}
+
+// readyzHandler handles the readiness probe. The server is only created once
+// its configs were initialized, it is then ready while all its sources that
+// check their health were healthy at the last check.
+func readyzHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	unhealthy, err := s.readiness.unhealthySources(r.Context(), s)
+	if err != nil {
+		// the client went away before the first check ended
+		return
+	}
+	if len(unhealthy) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "not ready: %d unhealthy sources", len(unhealthy))
+		return
+	}
+	_, _ = w.Write([]byte("ready"))
+}
+
+// sourcesHandler handles the admin request for the health of the sources,
+// which is only served to callers verified by one of the admin auth services.
+func sourcesHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/sources/get")
+	r = r.WithContext(ctx)
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	if len(s.adminAuthServices) == 0 {
+		err = fmt.Errorf("admin endpoints are disabled: no admin auth services are configured")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusForbidden))
+		return
+	}
+	claimsFromAuth := claimsFromHeader(ctx, s, r.Header)
+	if !slices.ContainsFunc(s.adminAuthServices, func(name string) bool { _, ok := claimsFromAuth[name]; return ok }) {
+		err = fmt.Errorf("request not authorized. Please make sure you specify the token of an admin auth service")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusUnauthorized))
+		return
+	}
+
+	render.JSON(w, r, sourcesResponse{Sources: checkSources(ctx, s.ResourceMgr.GetSourcesMap())})
+}
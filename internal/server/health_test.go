@@ -0,0 +1,212 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+var _ sources.HealthChecker = &mockHealthSource{}
+var _ sources.PoolStatsProvider = &mockHealthSource{}
+
+// mockHealthSource is used to mock sources that check their health in tests
+type mockHealthSource struct {
+	mu     sync.Mutex
+	Err    error
+	checks int
+}
+
+func (s *mockHealthSource) SourceKind() string {
+	return "mock-health"
+}
+
+func (s *mockHealthSource) CheckHealth(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks++
+	return s.Err
+}
+
+func (s *mockHealthSource) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err
+}
+
+func (s *mockHealthSource) checkCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checks
+}
+
+func (s *mockHealthSource) PoolStats() sources.PoolStats {
+	return sources.PoolStats{MaxConns: 4, OpenConns: 2, InUseConns: 1, IdleConns: 1}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	healthy := &mockHealthSource{}
+	sourcesMap := map[string]sources.Source{
+		"healthy": healthy,
+		"other":   &MockSource{Name: "other"},
+	}
+	server, shutdown := newTestServer(t, NewResourceManager(sourcesMap, nil, nil, nil, nil, nil))
+	defer shutdown()
+	r := chi.NewRouter()
+	addHealthRoutes(server, r)
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	check := func(path string, wantStatus int, wantBody string) {
+		t.Helper()
+		resp, body, err := runRequest(ts, http.MethodGet, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != wantStatus || string(body) != wantBody {
+			t.Fatalf("unexpected response of %s: got %d %q, want %d %q", path, resp.StatusCode, body, wantStatus, wantBody)
+		}
+	}
+	check("/healthz", http.StatusOK, "ok")
+	check("/readyz", http.StatusOK, "ready")
+
+	// the last check is served during the interval
+	healthy.setErr(fmt.Errorf("connection refused"))
+	check("/healthz", http.StatusOK, "ok")
+	check("/readyz", http.StatusOK, "ready")
+	if got := healthy.checkCount(); got != 1 {
+		t.Fatalf("unexpected number of health checks: got %d, want 1", got)
+	}
+
+	// once the interval passed, the last check is served while the sources
+	// are checked again in the background
+	server.readiness.mu.Lock()
+	server.readiness.interval = time.Millisecond
+	server.readiness.mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		resp, _, err := runRequest(ts, http.MethodGet, "/readyz", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the readiness probe did not report the unhealthy source")
+		}
+	}
+	check("/readyz", http.StatusServiceUnavailable, "not ready: 1 unhealthy sources")
+}
+
+func TestSourcesEndpoint(t *testing.T) {
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	otherAuthService := MockAuthService{Name: "other-auth", Token: "other-token"}
+	authServices := map[string]auth.AuthService{authService.Name: authService, otherAuthService.Name: otherAuthService}
+	sourcesMap := map[string]sources.Source{
+		"healthy":   &mockHealthSource{},
+		"unhealthy": &mockHealthSource{Err: fmt.Errorf("connection refused")},
+		"other":     &MockSource{Name: "other"},
+	}
+	server, shutdown := newTestServer(t, NewResourceManager(sourcesMap, authServices, nil, nil, nil, nil))
+	defer shutdown()
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	pool := &sources.PoolStats{MaxConns: 4, OpenConns: 2, InUseConns: 1, IdleConns: 1}
+	testCases := []struct {
+		name              string
+		adminAuthServices []string
+		header            map[string]string
+		wantStatus        int
+		want              []sourceStatus
+	}{
+		{
+			name:       "admin endpoints disabled",
+			header:     map[string]string{"my-auth_token": "valid-token"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:              "missing token",
+			adminAuthServices: []string{"my-auth"},
+			wantStatus:        http.StatusUnauthorized,
+		},
+		{
+			name:              "token of other auth service",
+			adminAuthServices: []string{"my-auth"},
+			header:            map[string]string{"other-auth_token": "other-token"},
+			wantStatus:        http.StatusUnauthorized,
+		},
+		{
+			name:              "invalid token",
+			adminAuthServices: []string{"my-auth"},
+			header:            map[string]string{"my-auth_token": "other-token"},
+			wantStatus:        http.StatusUnauthorized,
+		},
+		{
+			name:              "authorized",
+			adminAuthServices: []string{"other-auth", "my-auth"},
+			header:            map[string]string{"my-auth_token": "valid-token"},
+			wantStatus:        http.StatusOK,
+			want: []sourceStatus{
+				{Name: "healthy", Kind: "mock-health", Status: sourceHealthy, Pool: pool},
+				{Name: "other", Kind: "mock", Status: sourceUnknown},
+				{Name: "unhealthy", Kind: "mock-health", Status: sourceUnhealthy, Error: "connection refused", Pool: pool},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server.adminAuthServices = tc.adminAuthServices
+			resp, body, err := runRequest(ts, http.MethodGet, "/sources", nil, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got sourcesResponse
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unable to parse response: %s", err)
+			}
+			for _, s := range got.Sources {
+				if (s.Status == sourceUnknown) != (s.CheckedAt == nil) {
+					t.Fatalf("unexpected check time of source %q: %v", s.Name, s.CheckedAt)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got.Sources, cmpopts.IgnoreFields(sourceStatus{}, "CheckedAt", "Latency")); diff != "" {
+				t.Fatalf("incorrect sources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,481 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Statuses of invocation jobs.
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCancelled = "cancelled"
+)
+
+// jobCleanupInterval is the interval at which expired jobs are deleted.
+const jobCleanupInterval = time.Minute
+
+// errJobNotFound is returned for jobs that do not exist, or that are not
+// visible to the caller.
+var errJobNotFound = errors.New("job does not exist")
+
+// errJobFinished is returned when cancelling a job that already finished.
+var errJobFinished = errors.New("job already finished")
+
+// errTooManyJobs is returned when creating a job while the limits of stored or
+// running jobs are reached.
+var errTooManyJobs = errors.New("too many jobs, retry once some jobs finished or expired")
+
+// job is an asynchronous invocation of a tool.
+type job struct {
+	Id          string `json:"id"`
+	ToolName    string `json:"tool"`
+	ToolsetName string `json:"toolset,omitempty"`
+	Status      string `json:"status"`
+	// Result holds the results of the tool encoded as JSON, as the result of
+	// the invoke endpoint.
+	Result    string    `json:"result,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Owner maps the auth services that were verified when the job was
+	// created to the subject of their claims. Only callers verified as the
+	// same subjects can access the job. Jobs without owner are not listed,
+	// and are only accessed by their ID.
+	Owner map[string]string `json:"-"`
+}
+
+// finished returns true if the job is no longer running.
+func (j job) finished() bool {
+	return j.Status != jobRunning
+}
+
+// jobOwner returns the owner of a job created by a caller with the given
+// claims. Auth services whose claims have no subject do not identify the
+// owner.
+func jobOwner(claimsFromAuth map[string]map[string]any) map[string]string {
+	owner := make(map[string]string, len(claimsFromAuth))
+	for name, claims := range claimsFromAuth {
+		if sub, _ := claims["sub"].(string); sub != "" {
+			owner[name] = sub
+		}
+	}
+	return owner
+}
+
+// visibleTo returns true if the job can be accessed by a caller with the
+// given claims. Jobs without owner can be accessed by any caller that knows
+// their ID.
+func (j job) visibleTo(claimsFromAuth map[string]map[string]any) bool {
+	for name, sub := range j.Owner {
+		claims, ok := claimsFromAuth[name]
+		if !ok {
+			return false
+		}
+		if s, _ := claims["sub"].(string); s != sub {
+			return false
+		}
+	}
+	return true
+}
+
+// jobStore persists jobs. Its methods are called by the jobManager, which
+// serializes them.
+type jobStore interface {
+	put(context.Context, job) error
+	// get returns errJobNotFound if the job does not exist.
+	get(context.Context, string) (job, error)
+	// list returns the jobs in the order they were created.
+	list(context.Context) ([]job, error)
+	// count returns the number of jobs.
+	count(context.Context) (int, error)
+	// deleteFinished deletes the finished jobs last updated before a time.
+	deleteFinished(context.Context, time.Time) error
+	close() error
+}
+
+// jobManager runs the jobs and controls access to their store. Finished jobs
+// are deleted once they are older than the ttl. No more than maxJobs jobs are
+// stored, and no more than maxRunning jobs run at once, unless they are 0.
+type jobManager struct {
+	mu         sync.Mutex
+	store      jobStore
+	ttl        time.Duration
+	maxJobs    int
+	maxRunning int
+	cancels    map[string]context.CancelFunc
+}
+
+// newJobManager returns a manager of the jobs of the store. Jobs that were
+// still running when the store was last used are marked as failed, since
+// they can not be resumed.
+func newJobManager(ctx context.Context, store jobStore, ttl time.Duration, maxJobs, maxRunning int) (*jobManager, error) {
+	m := &jobManager{
+		mu:         sync.Mutex{},
+		store:      store,
+		ttl:        ttl,
+		maxJobs:    maxJobs,
+		maxRunning: maxRunning,
+		cancels:    make(map[string]context.CancelFunc),
+	}
+	jobs, err := store.list(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list jobs: %w", err)
+	}
+	for _, j := range jobs {
+		if j.finished() {
+			continue
+		}
+		j.Status = jobFailed
+		j.Error = "job was interrupted by a restart of the server"
+		j.UpdatedAt = time.Now()
+		if err := store.put(ctx, j); err != nil {
+			return nil, fmt.Errorf("unable to update job %q: %w", j.Id, err)
+		}
+	}
+	go m.cleanupRoutine(ctx)
+	return m, nil
+}
+
+// start creates a job and runs it in the background. The job is not cancelled
+// with ctx, whose values are passed to run. It returns errTooManyJobs if the
+// limits of jobs are reached.
+func (m *jobManager) start(ctx context.Context, j job, run func(context.Context) ([]any, error)) (job, error) {
+	now := time.Now()
+	j.Id = uuid.New().String()
+	j.Status = jobRunning
+	j.CreatedAt = now
+	j.UpdatedAt = now
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxRunning > 0 && len(m.cancels) >= m.maxRunning {
+		return job{}, errTooManyJobs
+	}
+	if m.maxJobs > 0 {
+		n, err := m.store.count(ctx)
+		if err != nil {
+			return job{}, err
+		}
+		if n >= m.maxJobs {
+			return job{}, errTooManyJobs
+		}
+	}
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	if err := m.store.put(ctx, j); err != nil {
+		cancel()
+		return job{}, err
+	}
+	m.cancels[j.Id] = cancel
+
+	go func() {
+		res, err := run(runCtx)
+		m.finish(runCtx, j.Id, res, err)
+	}()
+	return j, nil
+}
+
+// finish records the results of a job, unless it was cancelled.
+func (m *jobManager) finish(ctx context.Context, id string, res []any, runErr error) {
+	ctx = context.WithoutCancel(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+	j, err := m.store.get(ctx, id)
+	if err != nil || j.finished() {
+		return
+	}
+	j.Status = jobSucceeded
+	if runErr == nil {
+		var b []byte
+		b, runErr = json.Marshal(res)
+		if runErr != nil {
+			runErr = fmt.Errorf("unable to marshal result: %w", runErr)
+		}
+		j.Result = string(b)
+	}
+	if runErr != nil {
+		j.Status = jobFailed
+		j.Result = ""
+		j.Error = runErr.Error()
+	}
+	j.UpdatedAt = time.Now()
+	_ = m.store.put(ctx, j)
+}
+
+// get returns a job visible to a caller with the given claims.
+func (m *jobManager) get(ctx context.Context, id string, claimsFromAuth map[string]map[string]any) (job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, err := m.store.get(ctx, id)
+	if err != nil {
+		return job{}, err
+	}
+	if !j.visibleTo(claimsFromAuth) {
+		return job{}, errJobNotFound
+	}
+	return j, nil
+}
+
+// list returns the jobs owned by a caller with the given claims. Jobs without
+// owner are never listed.
+func (m *jobManager) list(ctx context.Context, claimsFromAuth map[string]map[string]any) ([]job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs, err := m.store.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(jobs, func(j job) bool { return len(j.Owner) == 0 || !j.visibleTo(claimsFromAuth) }), nil
+}
+
+// cancel cancels a running job visible to a caller with the given claims.
+func (m *jobManager) cancel(ctx context.Context, id string, claimsFromAuth map[string]map[string]any) (job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, err := m.store.get(ctx, id)
+	if err != nil {
+		return job{}, err
+	}
+	if !j.visibleTo(claimsFromAuth) {
+		return job{}, errJobNotFound
+	}
+	if j.finished() {
+		return job{}, errJobFinished
+	}
+	j.Status = jobCancelled
+	j.UpdatedAt = time.Now()
+	if err := m.store.put(ctx, j); err != nil {
+		return job{}, err
+	}
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+	return j, nil
+}
+
+// cleanup deletes the jobs that finished more than the ttl before now.
+func (m *jobManager) cleanup(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.store.deleteFinished(ctx, now.Add(-m.ttl))
+}
+
+func (m *jobManager) cleanupRoutine(ctx context.Context) {
+	ticker := time.NewTicker(jobCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			_ = m.cleanup(ctx, now)
+		}
+	}
+}
+
+// close cancels the running jobs and closes the store.
+func (m *jobManager) close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, cancel := range m.cancels {
+		cancel()
+		delete(m.cancels, id)
+	}
+	return m.store.close()
+}
+
+// jobListResponse is the response of the jobs list endpoint.
+type jobListResponse struct {
+	Jobs []job `json:"jobs"`
+}
+
+// toolJobCreateHandler handles the API request to invoke a tool in a job.
+func toolJobCreateHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/job/create")
+	r = r.WithContext(ctx)
+
+	toolName := chi.URLParam(r, "toolName")
+	toolsetName := chi.URLParam(r, "toolsetName")
+	s.logger.DebugContext(ctx, fmt.Sprintf("tool name: %s", toolName))
+	span.SetAttributes(attribute.String("tool_name", toolName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	tool, params, claimsFromAuth, code, err := parseInvocation(ctx, s, r, toolName)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, code))
+		return
+	}
+
+	j := job{ToolName: toolName, ToolsetName: toolsetName, Owner: jobOwner(claimsFromAuth)}
+	j, err = s.jobManager.start(ctx, j, func(ctx context.Context) ([]any, error) {
+		ctx, span := s.instrumentation.Tracer.Start(ctx, "toolbox/server/job/run")
+		defer span.End()
+		res, err := tool.Invoke(ctx, params)
+		status := "success"
+		if err != nil {
+			status = "error"
+			err = fmt.Errorf("error while invoking tool: %w", err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		s.instrumentation.ToolInvoke.Add(
+			ctx,
+			1,
+			metric.WithAttributes(attribute.String("toolbox.name", toolName)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+		return res, err
+	})
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, errTooManyJobs) {
+			code = http.StatusTooManyRequests
+		}
+		err = fmt.Errorf("unable to create job: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, code))
+		return
+	}
+	span.SetAttributes(attribute.String("job_id", j.Id))
+	render.Status(r, http.StatusAccepted)
+	render.JSON(w, r, j)
+}
+
+// jobGetHandler handles the API request for the status and result of a job.
+func jobGetHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/job/get")
+	r = r.WithContext(ctx)
+
+	jobId := chi.URLParam(r, "jobId")
+	span.SetAttributes(attribute.String("job_id", jobId))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	j, err := s.jobManager.get(ctx, jobId, claimsFromHeader(ctx, s, r.Header))
+	if err != nil {
+		renderJobError(s, w, r, jobId, err)
+		return
+	}
+	render.JSON(w, r, j)
+}
+
+// jobListHandler handles the API request to list the jobs. The results of the
+// jobs are left out of the list.
+func jobListHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/job/list")
+	r = r.WithContext(ctx)
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	jobs, err := s.jobManager.list(ctx, claimsFromHeader(ctx, s, r.Header))
+	if err != nil {
+		err = fmt.Errorf("unable to list jobs: %w", err)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	res := jobListResponse{Jobs: make([]job, 0, len(jobs))}
+	for _, j := range jobs {
+		j.Result = ""
+		res.Jobs = append(res.Jobs, j)
+	}
+	render.JSON(w, r, res)
+}
+
+// jobCancelHandler handles the API request to cancel a running job.
+func jobCancelHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/job/cancel")
+	r = r.WithContext(ctx)
+
+	jobId := chi.URLParam(r, "jobId")
+	span.SetAttributes(attribute.String("job_id", jobId))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	j, err := s.jobManager.cancel(ctx, jobId, claimsFromHeader(ctx, s, r.Header))
+	if err != nil {
+		renderJobError(s, w, r, jobId, err)
+		return
+	}
+	render.JSON(w, r, j)
+}
+
+// renderJobError renders the error of a request for a job.
+func renderJobError(s *Server, w http.ResponseWriter, r *http.Request, jobId string, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errJobNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, errJobFinished):
+		code = http.StatusConflict
+	}
+	err = fmt.Errorf("job %q: %w", jobId, err)
+	s.logger.DebugContext(r.Context(), err.Error())
+	_ = render.Render(w, r, newErrResponse(err, code))
+}
+
+// newJobStore returns the store of the jobs. Jobs are stored in the SQLite
+// database at path, or in memory if it is empty.
+func newJobStore(ctx context.Context, path string) (jobStore, error) {
+	if strings.TrimSpace(path) == "" {
+		return newMemoryJobStore(), nil
+	}
+	return newSqliteJobStore(ctx, path)
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestJobsEndpoints(t *testing.T) {
+	cancelled := make(chan struct{})
+	blockingTool := MockTool{
+		Name:   "blocking",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	}
+	quickTool := MockTool{Name: "quick", Params: []tools.Parameter{}}
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	authTool := MockTool{Name: "auth_required", Params: []tools.Parameter{}, AuthRequired: []string{authService.Name}}
+	toolsMap, toolsets := setUpResources(t, []MockTool{blockingTool, quickTool, authTool})
+	authServices := map[string]auth.AuthService{authService.Name: authService}
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+	authHeader := map[string]string{"my-auth_token": "valid-token"}
+
+	request := func(method, path string, header map[string]string, wantStatus int) job {
+		t.Helper()
+		var body io.Reader
+		if method == http.MethodPost {
+			body = bytes.NewBufferString(`{}`)
+		}
+		resp, respBody, err := runRequest(ts, method, path, body, header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("unexpected status code of %s %s: got %d, want %d: %s", method, path, resp.StatusCode, wantStatus, respBody)
+		}
+		var j job
+		if wantStatus < 300 {
+			if err := json.Unmarshal(respBody, &j); err != nil {
+				t.Fatalf("unable to parse job: %s", err)
+			}
+		}
+		return j
+	}
+	waitFinished := func(id string, header map[string]string) job {
+		t.Helper()
+		for range 100 {
+			j := request(http.MethodGet, "/jobs/"+id, header, http.StatusOK)
+			if j.Status != jobRunning {
+				return j
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("job %q did not finish", id)
+		return job{}
+	}
+
+	// a job returns the result of the tool once it finished
+	quick := request(http.MethodPost, "/tool/quick/jobs", nil, http.StatusAccepted)
+	if quick.Id == "" || quick.ToolName != "quick" || quick.Status != jobRunning {
+		t.Fatalf("unexpected created job: %+v", quick)
+	}
+	quick = waitFinished(quick.Id, nil)
+	if quick.Status != jobSucceeded || quick.Result != `["quick"]` {
+		t.Fatalf("unexpected finished job: %+v", quick)
+	}
+
+	// a running job is cancelled, but only once
+	blocking := request(http.MethodPost, "/toolset/tool1_only/tool/blocking/jobs", nil, http.StatusAccepted)
+	if got := request(http.MethodGet, "/jobs/"+blocking.Id, nil, http.StatusOK); got.Status != jobRunning || got.ToolsetName != "tool1_only" {
+		t.Fatalf("unexpected running job: %+v", got)
+	}
+	if got := request(http.MethodDelete, "/jobs/"+blocking.Id, nil, http.StatusOK); got.Status != jobCancelled {
+		t.Fatalf("unexpected cancelled job: %+v", got)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("invocation of cancelled job was not cancelled")
+	}
+	request(http.MethodDelete, "/jobs/"+blocking.Id, nil, http.StatusConflict)
+	if got := request(http.MethodGet, "/jobs/"+blocking.Id, nil, http.StatusOK); got.Status != jobCancelled {
+		t.Fatalf("unexpected status of cancelled job: %q", got.Status)
+	}
+
+	// jobs are only created for the tools that the caller can invoke
+	request(http.MethodPost, "/toolset/tool1_only/tool/quick/jobs", nil, http.StatusNotFound)
+	request(http.MethodPost, "/tool/auth_required/jobs", nil, http.StatusUnauthorized)
+	request(http.MethodGet, "/jobs/some_imaginary_job", nil, http.StatusNotFound)
+
+	// jobs created with tokens are only visible to the same users
+	authJob := request(http.MethodPost, "/tool/auth_required/jobs", authHeader, http.StatusAccepted)
+	request(http.MethodGet, "/jobs/"+authJob.Id, nil, http.StatusNotFound)
+	request(http.MethodDelete, "/jobs/"+authJob.Id, nil, http.StatusNotFound)
+	if got := waitFinished(authJob.Id, authHeader); got.Status != jobSucceeded {
+		t.Fatalf("unexpected status of job: %q", got.Status)
+	}
+
+	list := func(header map[string]string) []string {
+		resp, body, err := runRequest(ts, http.MethodGet, "/jobs", nil, header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		var res jobListResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			t.Fatalf("unable to parse jobs: %s", err)
+		}
+		ids := make([]string, 0, len(res.Jobs))
+		for _, j := range res.Jobs {
+			if j.Result != "" {
+				t.Fatalf("unexpected result of listed job %q", j.Id)
+			}
+			ids = append(ids, j.Id)
+		}
+		return ids
+	}
+	// jobs created without tokens are only accessed by their ID
+	if diff := cmp.Diff([]string{}, list(nil)); diff != "" {
+		t.Fatalf("incorrect jobs (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{authJob.Id}, list(authHeader)); diff != "" {
+		t.Fatalf("incorrect jobs with token (-want +got):\n%s", diff)
+	}
+}
+
+func TestJobStores(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	old := job{Id: "old", ToolName: "tool", Status: jobSucceeded, Result: "[]", CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour), Owner: map[string]string{}}
+	running := job{Id: "running", ToolName: "tool", ToolsetName: "toolset", Status: jobRunning, CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour), Owner: map[string]string{"my-auth": "some_user"}}
+	recent := job{Id: "recent", ToolName: "tool", Status: jobFailed, Error: "failed", CreatedAt: now.Add(-time.Hour), UpdatedAt: now, Owner: map[string]string{}}
+
+	stores := map[string]func(t *testing.T) jobStore{
+		"memory": func(*testing.T) jobStore { return newMemoryJobStore() },
+		"sqlite": func(t *testing.T) jobStore {
+			s, err := newSqliteJobStore(ctx, filepath.Join(t.TempDir(), "jobs.db"))
+			if err != nil {
+				t.Fatalf("unable to create store: %s", err)
+			}
+			return s
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := newStore(t)
+			defer s.close()
+			for _, j := range []job{recent, running, old} {
+				if err := s.put(ctx, j); err != nil {
+					t.Fatalf("unable to put job: %s", err)
+				}
+			}
+			got, err := s.get(ctx, running.Id)
+			if err != nil {
+				t.Fatalf("unable to get job: %s", err)
+			}
+			if diff := cmp.Diff(running, got, cmp.Comparer(time.Time.Equal)); diff != "" {
+				t.Fatalf("incorrect job (-want +got):\n%s", diff)
+			}
+
+			if err := s.deleteFinished(ctx, now.Add(-time.Hour)); err != nil {
+				t.Fatalf("unable to delete jobs: %s", err)
+			}
+			if _, err := s.get(ctx, old.Id); !errors.Is(err, errJobNotFound) {
+				t.Fatalf("unexpected error of deleted job: got %v, want %v", err, errJobNotFound)
+			}
+			jobs, err := s.list(ctx)
+			if err != nil {
+				t.Fatalf("unable to list jobs: %s", err)
+			}
+			var ids []string
+			for _, j := range jobs {
+				ids = append(ids, j.Id)
+			}
+			if diff := cmp.Diff([]string{running.Id, recent.Id}, ids); diff != "" {
+				t.Fatalf("incorrect jobs (-want +got):\n%s", diff)
+			}
+			if n, err := s.count(ctx); err != nil || n != 2 {
+				t.Fatalf("unexpected count of jobs: got %d, %v", n, err)
+			}
+		})
+	}
+}
+
+func TestJobManagerRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	s, err := newSqliteJobStore(ctx, path)
+	if err != nil {
+		t.Fatalf("unable to create store: %s", err)
+	}
+	m, err := newJobManager(ctx, s, time.Hour, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create job manager: %s", err)
+	}
+	release := make(chan struct{})
+	j, err := m.start(ctx, job{ToolName: "tool"}, func(context.Context) ([]any, error) {
+		<-release
+		return []any{"result"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unable to start job: %s", err)
+	}
+	// the server stops while the job is running
+	if err := m.close(); err != nil {
+		t.Fatalf("unable to close job manager: %s", err)
+	}
+	close(release)
+
+	s, err = newSqliteJobStore(ctx, path)
+	if err != nil {
+		t.Fatalf("unable to reopen store: %s", err)
+	}
+	m, err = newJobManager(ctx, s, time.Hour, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to create job manager: %s", err)
+	}
+	defer m.close()
+	got, err := m.get(ctx, j.Id, nil)
+	if err != nil {
+		t.Fatalf("unable to get job: %s", err)
+	}
+	if got.Status != jobFailed || got.Error == "" {
+		t.Fatalf("unexpected job after restart: %+v", got)
+	}
+
+	// finished jobs are deleted once they expire
+	if err := m.cleanup(ctx, time.Now()); err != nil {
+		t.Fatalf("unable to clean up jobs: %s", err)
+	}
+	if _, err := m.get(ctx, j.Id, nil); err != nil {
+		t.Fatalf("job was deleted before it expired: %s", err)
+	}
+	if err := m.cleanup(ctx, time.Now().Add(2*time.Hour)); err != nil {
+		t.Fatalf("unable to clean up jobs: %s", err)
+	}
+	if _, err := m.get(ctx, j.Id, nil); !errors.Is(err, errJobNotFound) {
+		t.Fatalf("unexpected error of expired job: got %v, want %v", err, errJobNotFound)
+	}
+}
+
+func TestJobManagerLimits(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m, err := newJobManager(ctx, newMemoryJobStore(), time.Hour, 3, 2)
+	if err != nil {
+		t.Fatalf("unable to create job manager: %s", err)
+	}
+	defer m.close()
+	release := make(chan struct{})
+	run := func(context.Context) ([]any, error) {
+		<-release
+		return nil, nil
+	}
+	var running []job
+	for range 2 {
+		j, err := m.start(ctx, job{ToolName: "tool"}, run)
+		if err != nil {
+			t.Fatalf("unable to start job: %s", err)
+		}
+		running = append(running, j)
+	}
+	if _, err := m.start(ctx, job{ToolName: "tool"}, run); !errors.Is(err, errTooManyJobs) {
+		t.Fatalf("unexpected error above the running jobs limit: got %v, want %v", err, errTooManyJobs)
+	}
+
+	// finished jobs still count towards the stored jobs limit
+	close(release)
+	for _, j := range running {
+		for {
+			got, err := m.get(ctx, j.Id, nil)
+			if err != nil {
+				t.Fatalf("unable to get job: %s", err)
+			}
+			if got.Status != jobRunning {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if _, err := m.start(ctx, job{ToolName: "tool"}, run); err != nil {
+		t.Fatalf("unable to start job: %s", err)
+	}
+	if _, err := m.start(ctx, job{ToolName: "tool"}, run); !errors.Is(err, errTooManyJobs) {
+		t.Fatalf("unexpected error above the stored jobs limit: got %v, want %v", err, errTooManyJobs)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+var _ jobStore = &memoryJobStore{}
+
+// memoryJobStore stores the jobs in memory, they are lost when the server
+// stops.
+type memoryJobStore struct {
+	jobs map[string]job
+}
+
+func newMemoryJobStore() *memoryJobStore {
+	return &memoryJobStore{jobs: make(map[string]job)}
+}
+
+func (s *memoryJobStore) put(_ context.Context, j job) error {
+	s.jobs[j.Id] = j
+	return nil
+}
+
+func (s *memoryJobStore) get(_ context.Context, id string) (job, error) {
+	j, ok := s.jobs[id]
+	if !ok {
+		return job{}, errJobNotFound
+	}
+	return j, nil
+}
+
+func (s *memoryJobStore) list(_ context.Context) ([]job, error) {
+	jobs := slices.Collect(maps.Values(s.jobs))
+	slices.SortFunc(jobs, func(a, b job) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return jobs, nil
+}
+
+func (s *memoryJobStore) count(_ context.Context) (int, error) {
+	return len(s.jobs), nil
+}
+
+func (s *memoryJobStore) deleteFinished(_ context.Context, before time.Time) error {
+	maps.DeleteFunc(s.jobs, func(_ string, j job) bool {
+		return j.finished() && j.UpdatedAt.Before(before)
+	})
+	return nil
+}
+
+func (s *memoryJobStore) close() error {
+	return nil
+}
+
+var _ jobStore = &sqliteJobStore{}
+
+// sqliteJobStore stores the jobs in a SQLite database, so that they survive
+// restarts of the server.
+type sqliteJobStore struct {
+	db *sql.DB
+}
+
+func newSqliteJobStore(ctx context.Context, path string) (*sqliteJobStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open jobs database: %w", err)
+	}
+	// the jobManager serializes the access to the store
+	db.SetMaxOpenConns(1)
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS jobs (
+		id TEXT PRIMARY KEY,
+		tool TEXT NOT NULL,
+		toolset TEXT NOT NULL,
+		status TEXT NOT NULL,
+		result TEXT NOT NULL,
+		error TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("unable to create jobs table: %w", err)
+	}
+	return &sqliteJobStore{db: db}, nil
+}
+
+func (s *sqliteJobStore) put(ctx context.Context, j job) error {
+	owner, err := json.Marshal(j.Owner)
+	if err != nil {
+		return fmt.Errorf("unable to marshal job owner: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO jobs (id, tool, toolset, status, result, error, owner, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET status = excluded.status, result = excluded.result, error = excluded.error, updated_at = excluded.updated_at`,
+		j.Id, j.ToolName, j.ToolsetName, j.Status, j.Result, j.Error, string(owner), j.CreatedAt.UnixNano(), j.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("unable to store job: %w", err)
+	}
+	return nil
+}
+
+const sqliteJobColumns = "id, tool, toolset, status, result, error, owner, created_at, updated_at"
+
+// scanJob scans a row of the sqliteJobColumns.
+func scanJob(row interface{ Scan(...any) error }) (job, error) {
+	var j job
+	var owner string
+	var createdAt, updatedAt int64
+	if err := row.Scan(&j.Id, &j.ToolName, &j.ToolsetName, &j.Status, &j.Result, &j.Error, &owner, &createdAt, &updatedAt); err != nil {
+		return job{}, err
+	}
+	if err := json.Unmarshal([]byte(owner), &j.Owner); err != nil {
+		return job{}, fmt.Errorf("unable to unmarshal job owner: %w", err)
+	}
+	j.CreatedAt = time.Unix(0, createdAt)
+	j.UpdatedAt = time.Unix(0, updatedAt)
+	return j, nil
+}
+
+func (s *sqliteJobStore) get(ctx context.Context, id string) (job, error) {
+	j, err := scanJob(s.db.QueryRowContext(ctx, "SELECT "+sqliteJobColumns+" FROM jobs WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return job{}, errJobNotFound
+	}
+	if err != nil {
+		return job{}, fmt.Errorf("unable to read job: %w", err)
+	}
+	return j, nil
+}
+
+func (s *sqliteJobStore) list(ctx context.Context) ([]job, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+sqliteJobColumns+" FROM jobs ORDER BY created_at")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read jobs: %w", err)
+	}
+	defer rows.Close()
+	jobs := make([]job, 0)
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read jobs: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *sqliteJobStore) count(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM jobs").Scan(&n); err != nil {
+		return 0, fmt.Errorf("unable to count jobs: %w", err)
+	}
+	return n, nil
+}
+
+func (s *sqliteJobStore) deleteFinished(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM jobs WHERE status != ? AND updated_at < ?", jobRunning, before.UnixNano())
+	if err != nil {
+		return fmt.Errorf("unable to delete jobs: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteJobStore) close() error {
+	return s.db.Close()
+}
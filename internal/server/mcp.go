@@ -19,9 +19,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,23 +33,53 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/google/uuid"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/log"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
+	v20250618 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/gorilla/websocket"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 )
 
 type sseSession struct {
+	toolsetName string
+	// header holds the headers of the request that opened the session.
+	header     http.Header
 	writer     http.ResponseWriter
 	flusher    http.Flusher
 	done       chan struct{}
 	eventQueue chan string
 	lastActive time.Time
+	// requests holds the in-flight requests of the session.
+	requests *inFlightRequests
+	// logger also sends the log messages of the session to the client.
+	logger *log.TeeLogger
+}
+
+// send queues a server-initiated message on the session's sse stream. It
+// returns false if the message could not be queued.
+func (s *sseSession) send(message any) bool {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return false
+	}
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", data):
+		return true
+	case <-s.done:
+		return false
+	default:
+		return false
+	}
 }
 
 // sseManager manages and control access to sse sessions
@@ -58,7 +92,9 @@ func (m *sseManager) get(id string) (*sseSession, bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	session, ok := m.sseSessions[id]
-	session.lastActive = time.Now()
+	if ok {
+		session.lastActive = time.Now()
+	}
 	return session, ok
 }
 
@@ -84,6 +120,22 @@ func (m *sseManager) remove(id string) {
 	m.mu.Unlock()
 }
 
+// notifyListChanged queues list changed notifications for the sessions whose
+// toolset changed.
+func (m *sseManager) notifyListChanged(change ResourceChange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, session := range m.sseSessions {
+		for _, notification := range listChangedNotifications(change, session.toolsetName) {
+			eventData, _ := json.Marshal(notification)
+			select {
+			case session.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+			default:
+			}
+		}
+	}
+}
+
 func (m *sseManager) cleanupRoutine(ctx context.Context) {
 	timeout := 10 * time.Minute
 	ticker := time.NewTicker(timeout)
@@ -108,28 +160,314 @@ func (m *sseManager) cleanupRoutine(ctx context.Context) {
 	}
 }
 
+// streamableSession is a session of the streamable HTTP transport that was
+// introduced in v2025-03-26. A session is created by the initialize request
+// and lasts until the client terminates it or it expires.
+type streamableSession struct {
+	id          string
+	protocol    string
+	toolsetName string
+	// eventQueue holds server-initiated messages for the GET stream.
+	eventQueue chan string
+	done       chan struct{}
+	lastActive time.Time
+	// streaming is true while the client holds a GET stream open.
+	streaming bool
+	// requests holds the in-flight requests of the session.
+	requests *inFlightRequests
+	// logger also sends the log messages of the session to the client.
+	logger *log.TeeLogger
+}
+
+// send queues a server-initiated message on the session's GET stream. It
+// returns false if the message could not be queued.
+func (s *streamableSession) send(message any) bool {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return false
+	}
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", data):
+		return true
+	default:
+		return false
+	}
+}
+
+// streamableManager manages and control access to streamable HTTP sessions
+type streamableManager struct {
+	mu       sync.Mutex
+	sessions map[string]*streamableSession
+}
+
+func newStreamableManager(ctx context.Context) *streamableManager {
+	m := &streamableManager{
+		mu:       sync.Mutex{},
+		sessions: make(map[string]*streamableSession),
+	}
+	go m.cleanupRoutine(ctx)
+	return m
+}
+
+// create starts a new session for the negotiated protocol version. The log
+// messages of the session are logged with logger.
+func (m *streamableManager) create(protocol, toolsetName string, logger log.Logger) *streamableSession {
+	session := &streamableSession{
+		id:          uuid.New().String(),
+		protocol:    protocol,
+		toolsetName: toolsetName,
+		eventQueue:  make(chan string, 100),
+		done:        make(chan struct{}),
+		lastActive:  time.Now(),
+		requests:    newInFlightRequests(),
+	}
+	session.logger = newSessionLogger(logger, session.send)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[session.id] = session
+	return session
+}
+
+func (m *streamableManager) get(id string) (*streamableSession, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	if ok {
+		session.lastActive = time.Now()
+	}
+	return session, ok
+}
+
+// remove terminates a session. It returns false if the session does not exist.
+func (m *streamableManager) remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return false
+	}
+	delete(m.sessions, id)
+	close(session.done)
+	return true
+}
+
+// openStream marks the GET stream of a session as open. Only a single stream
+// is allowed per session.
+func (m *streamableManager) openStream(session *streamableSession) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if session.streaming {
+		return false
+	}
+	session.streaming = true
+	return true
+}
+
+func (m *streamableManager) closeStream(session *streamableSession) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session.streaming = false
+	session.lastActive = time.Now()
+}
+
+// notifyListChanged queues list changed notifications for the sessions whose
+// toolset changed.
+func (m *streamableManager) notifyListChanged(change ResourceChange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, session := range m.sessions {
+		for _, notification := range listChangedNotifications(change, session.toolsetName) {
+			session.send(notification)
+		}
+	}
+}
+
+func (m *streamableManager) cleanupRoutine(ctx context.Context) {
+	timeout := 10 * time.Minute
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			func() {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				now := time.Now()
+				for id, sess := range m.sessions {
+					// sessions with an open stream are still in use
+					if !sess.streaming && now.Sub(sess.lastActive) > timeout {
+						delete(m.sessions, id)
+						close(sess.done)
+					}
+				}
+			}()
+		}
+	}
+}
+
+// errRequestCancelled is the cause of the context of the requests that are
+// cancelled by the client.
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// inFlightRequests tracks the requests of a session that are being processed,
+// so that the client can cancel them.
+type inFlightRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+}
+
+func newInFlightRequests() *inFlightRequests {
+	return &inFlightRequests{
+		mu:      sync.Mutex{},
+		cancels: make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// requestKey identifies a request by the JSON encoding of its id, since the
+// same id is decoded as either json.Number or float64.
+func requestKey(id jsonrpc.RequestId) string {
+	key, _ := json.Marshal(id)
+	return string(key)
+}
+
+// start tracks a request until the returned func is called. The returned
+// context is cancelled if the client cancels the request.
+func (r *inFlightRequests) start(ctx context.Context, id jsonrpc.RequestId) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := requestKey(id)
+	r.mu.Lock()
+	r.cancels[key] = cancel
+	r.mu.Unlock()
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.cancels, key)
+		r.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancel cancels an in-flight request. Unknown requests are ignored, since
+// they may have completed already.
+func (r *inFlightRequests) cancel(id jsonrpc.RequestId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cancel, ok := r.cancels[requestKey(id)]; ok {
+		cancel(errRequestCancelled)
+	}
+}
+
+// messageContext holds the session state that a message is processed with.
+// All fields are nil for messages sent without a session.
+type messageContext struct {
+	// requests holds the in-flight requests of the session.
+	requests *inFlightRequests
+	// notify sends a notification to the client while a request is processed.
+	notify func(notification any)
+	// logger also sends the log messages of the session to the client.
+	logger *log.TeeLogger
+}
+
+// cancel cancels an in-flight request of the session.
+func (mc *messageContext) cancel(id jsonrpc.RequestId) {
+	if mc != nil && mc.requests != nil {
+		mc.requests.cancel(id)
+	}
+}
+
+// newSessionLogger returns the logger of a session, which logs messages with
+// logger and sends them to the client as notifications/message with send once
+// the client sets a level.
+func newSessionLogger(logger log.Logger, send func(message any) bool) *log.TeeLogger {
+	return log.NewTeeLogger(logger, func(ctx context.Context, severity, msg string, keysAndValues ...interface{}) {
+		send(mcp.NewLoggingMessageNotification(severity, msg, keysAndValues...))
+	})
+}
+
+// stdioAuthTokenEnv is the environment variable holding the token that stdio
+// sessions present to the auth services, since stdio has no request headers.
+const stdioAuthTokenEnv = "TOOLBOX_AUTH_TOKEN"
+
+// stdioAuthHeader returns the headers that stdio sessions present to the auth
+// services. The token from stdioAuthTokenEnv is sent as a bearer token, and as
+// the token header of every auth service.
+func stdioAuthHeader(s *Server) http.Header {
+	header := make(http.Header)
+	token := os.Getenv(stdioAuthTokenEnv)
+	if token == "" {
+		return header
+	}
+	header.Set("Authorization", "Bearer "+token)
+	for _, a := range s.ResourceMgr.GetAuthServiceMap() {
+		if h := auth.TokenHeader(a); h != "" && !strings.EqualFold(h, "Authorization") {
+			header.Set(h, token)
+		}
+	}
+	return header
+}
+
 type stdioSession struct {
+	// mu guards the protocol and the writes to the writer, since
+	// notifications are written concurrently with responses.
+	mu       sync.Mutex
 	protocol string
 	server   *Server
 	reader   *bufio.Reader
 	writer   io.Writer
+	requests *inFlightRequests
+	logger   *log.TeeLogger
 }
 
 func NewStdioSession(s *Server, stdin io.Reader, stdout io.Writer) *stdioSession {
 	stdioSession := &stdioSession{
-		server: s,
-		reader: bufio.NewReader(stdin),
-		writer: stdout,
+		server:   s,
+		reader:   bufio.NewReader(stdin),
+		writer:   stdout,
+		requests: newInFlightRequests(),
 	}
+	stdioSession.logger = newSessionLogger(s.logger, func(message any) bool {
+		return stdioSession.write(context.Background(), message) == nil
+	})
 	return stdioSession
 }
 
 func (s *stdioSession) Start(ctx context.Context) error {
+	unsubscribe := s.server.ResourceMgr.Subscribe(func(change ResourceChange) {
+		s.notifyListChanged(ctx, change)
+	})
+	defer unsubscribe()
 	return s.readInputStream(ctx)
 }
 
+// notifyListChanged notifies the client if the tools or prompts changed. Stdio
+// sessions always use the default toolset.
+func (s *stdioSession) notifyListChanged(ctx context.Context, change ResourceChange) {
+	s.mu.Lock()
+	initialized := s.protocol != ""
+	s.mu.Unlock()
+	// notifications are only sent after initialization
+	if !initialized {
+		return
+	}
+	for _, notification := range listChangedNotifications(change, "") {
+		if err := s.write(ctx, notification); err != nil {
+			s.server.logger.ErrorContext(ctx, err.Error())
+		}
+	}
+}
+
 // readInputStream reads requests/notifications from MCP clients through stdin
 func (s *stdioSession) readInputStream(ctx context.Context) error {
+	// wait for the requests that are processed concurrently
+	var wg sync.WaitGroup
+	defer wg.Wait()
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -141,32 +479,80 @@ func (s *stdioSession) readInputStream(ctx context.Context) error {
 			}
 			return err
 		}
-		v, res, err := processMcpMessage(ctx, []byte(line), s.server, s.protocol, "")
-		if err != nil {
-			// errors during the processing of message will generate a valid MCP Error response.
-			// server can continue to run.
-			s.server.logger.ErrorContext(ctx, err.Error())
+		message := []byte(line)
+		// requests are processed concurrently, so that the client can cancel
+		// long-running tool calls. The initialize request and notifications
+		// are processed in order.
+		if isConcurrentMessage(message) {
+			s.mu.Lock()
+			protocol := s.protocol
+			s.mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.processMessage(ctx, message, protocol); err != nil {
+					s.server.logger.ErrorContext(ctx, err.Error())
+				}
+			}()
+			continue
 		}
-		if v != "" {
-			s.protocol = v
+		if err := s.processMessage(ctx, message, s.protocol); err != nil {
+			return err
 		}
-		// no responses for notifications
-		if res != nil {
-			if err = s.write(ctx, res); err != nil {
-				return err
+	}
+}
+
+// isConcurrentMessage reports whether a message can be processed concurrently
+// with the following messages of the session.
+func isConcurrentMessage(message []byte) bool {
+	if isBatchMessage(message) {
+		return true
+	}
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(message, &baseMessage); err != nil {
+		return false
+	}
+	return baseMessage.Id != nil && baseMessage.Method != mcputil.INITIALIZE
+}
+
+// processMessage processes a message and writes its response. It only
+// returns an error if the response could not be written.
+func (s *stdioSession) processMessage(ctx context.Context, message []byte, protocol string) error {
+	mc := &messageContext{
+		requests: s.requests,
+		notify: func(notification any) {
+			if err := s.write(ctx, notification); err != nil {
+				s.server.logger.ErrorContext(ctx, err.Error())
 			}
-		}
+		},
+		logger: s.logger,
+	}
+	v, res, err := processMcpMessage(ctx, message, s.server, protocol, "", stdioAuthHeader(s.server), mc)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		// server can continue to run.
+		s.server.logger.ErrorContext(ctx, err.Error())
+	}
+	if v != "" {
+		s.mu.Lock()
+		s.protocol = v
+		s.mu.Unlock()
 	}
+	// no responses for notifications
+	if res != nil {
+		return s.write(ctx, res)
+	}
+	return nil
 }
 
 // readLine process each line within the input stream.
 func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 	readChan := make(chan string, 1)
 	errChan := make(chan error, 1)
+	// the channels are buffered and never closed, since the reader may still
+	// send to them after ctx is cancelled
 	done := make(chan struct{})
 	defer close(done)
-	defer close(readChan)
-	defer close(errChan)
 
 	go func() {
 		select {
@@ -206,10 +592,205 @@ func (s *stdioSession) readLine(ctx context.Context) (string, error) {
 func (s *stdioSession) write(ctx context.Context, response any) error {
 	res, _ := json.Marshal(response)
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	_, err := fmt.Fprintf(s.writer, "%s\n", res)
 	return err
 }
 
+const (
+	// wsPongWait is the time allowed to read the next message or pong from a
+	// websocket client.
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod is the period of the pings that keep a websocket
+	// connection alive. It must be less than wsPongWait.
+	wsPingPeriod = (wsPongWait * 9) / 10
+	// wsWriteWait is the time allowed to write a message to a websocket client.
+	wsWriteWait = 10 * time.Second
+)
+
+var wsUpgrader = websocket.Upgrader{}
+
+// wsSession is a session of the WebSocket transport. A session lasts as long
+// as its connection, and carries JSON-RPC messages in both directions.
+type wsSession struct {
+	// mu guards the protocol and the writes to the connection, since
+	// notifications are written concurrently with responses.
+	mu          sync.Mutex
+	protocol    string
+	server      *Server
+	conn        *websocket.Conn
+	toolsetName string
+	// header holds the headers of the request that opened the session.
+	header   http.Header
+	requests *inFlightRequests
+	logger   *log.TeeLogger
+}
+
+func newWsSession(s *Server, conn *websocket.Conn, toolsetName string, header http.Header) *wsSession {
+	session := &wsSession{
+		server:      s,
+		conn:        conn,
+		toolsetName: toolsetName,
+		header:      header,
+		requests:    newInFlightRequests(),
+	}
+	session.logger = newSessionLogger(s.logger, func(message any) bool {
+		return session.write(message) == nil
+	})
+	return session
+}
+
+// serve processes the messages of the session until the connection is
+// closed. In-flight requests are cancelled when the connection closes.
+func (ws *wsSession) serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer ws.conn.Close()
+
+	unsubscribe := ws.server.ResourceMgr.Subscribe(func(change ResourceChange) {
+		ws.notifyListChanged(ctx, change)
+	})
+	defer unsubscribe()
+	go ws.keepAlive(ctx)
+
+	// wait for the requests that are processed concurrently
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	_ = ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	ws.conn.SetPongHandler(func(string) error {
+		return ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		_, message, err := ws.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return err
+		}
+		_ = ws.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		ws.mu.Lock()
+		protocol := ws.protocol
+		ws.mu.Unlock()
+		// requests are processed concurrently, so that the client can cancel
+		// long-running tool calls. The initialize request and notifications
+		// are processed in order.
+		if isConcurrentMessage(message) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := ws.processMessage(ctx, message, protocol); err != nil {
+					ws.server.logger.DebugContext(ctx, err.Error())
+				}
+			}()
+			continue
+		}
+		if err := ws.processMessage(ctx, message, protocol); err != nil {
+			return err
+		}
+	}
+}
+
+// keepAlive pings the client until ctx is done. The client is disconnected
+// by the read deadline if it stops answering.
+func (ws *wsSession) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notifyListChanged notifies the client if the tools or prompts of its
+// toolset changed.
+func (ws *wsSession) notifyListChanged(ctx context.Context, change ResourceChange) {
+	ws.mu.Lock()
+	initialized := ws.protocol != ""
+	ws.mu.Unlock()
+	// notifications are only sent after initialization
+	if !initialized {
+		return
+	}
+	for _, notification := range listChangedNotifications(change, ws.toolsetName) {
+		if err := ws.write(notification); err != nil {
+			ws.server.logger.DebugContext(ctx, err.Error())
+		}
+	}
+}
+
+// processMessage processes a message and writes its response. It only
+// returns an error if the response could not be written.
+func (ws *wsSession) processMessage(ctx context.Context, message []byte, protocol string) error {
+	mc := &messageContext{
+		requests: ws.requests,
+		notify: func(notification any) {
+			if err := ws.write(notification); err != nil {
+				ws.server.logger.DebugContext(ctx, err.Error())
+			}
+		},
+		logger: ws.logger,
+	}
+	v, res, err := processMcpMessage(ctx, message, ws.server, protocol, ws.toolsetName, ws.header, mc)
+	if err != nil {
+		// errors during the processing of message will generate a valid MCP Error response.
+		ws.server.logger.DebugContext(ctx, err.Error())
+	}
+	if v != "" {
+		ws.mu.Lock()
+		ws.protocol = v
+		ws.mu.Unlock()
+	}
+	// no responses for notifications
+	if res != nil {
+		return ws.write(res)
+	}
+	return nil
+}
+
+// write sends a message to the client as a text message.
+func (ws *wsSession) write(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return ws.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// listChangedNotifications returns the list changed notifications for the
+// clients of a toolset.
+func listChangedNotifications(change ResourceChange, toolsetName string) []jsonrpc.JSONRPCNotification {
+	var notifications []jsonrpc.JSONRPCNotification
+	if slices.Contains(change.Toolsets, toolsetName) {
+		notifications = append(notifications, mcp.NewListChangedNotification(mcputil.TOOLS_LIST_CHANGED))
+	}
+	if slices.Contains(change.Promptsets, toolsetName) {
+		notifications = append(notifications, mcp.NewListChangedNotification(mcputil.PROMPTS_LIST_CHANGED))
+	}
+	return notifications
+}
+
+// notifyListChanged notifies the sse and streamable HTTP sessions whose
+// toolset changed during a dynamic reload.
+func (s *Server) notifyListChanged(change ResourceChange) {
+	s.sseManager.notifyListChanged(change)
+	s.streamableManager.notifyListChanged(change)
+}
+
 // mcpRouter creates a router that represents the routes under /mcp
 func mcpRouter(s *Server) (chi.Router, error) {
 	r := chi.NewRouter()
@@ -219,15 +800,17 @@ func mcpRouter(s *Server) (chi.Router, error) {
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
 	r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
+	r.Get("/ws", func(w http.ResponseWriter, r *http.Request) { wsHandler(s, w, r) })
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
 	r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-	r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+	r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
 
 	r.Route("/{toolsetName}", func(r chi.Router) {
 		r.Get("/sse", func(w http.ResponseWriter, r *http.Request) { sseHandler(s, w, r) })
-		r.Get("/", func(w http.ResponseWriter, r *http.Request) { methodNotAllowed(s, w, r) })
+		r.Get("/ws", func(w http.ResponseWriter, r *http.Request) { wsHandler(s, w, r) })
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { streamHandler(s, w, r) })
 		r.Post("/", func(w http.ResponseWriter, r *http.Request) { httpHandler(s, w, r) })
-		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {})
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deleteHandler(s, w, r) })
 	})
 
 	return r, nil
@@ -275,11 +858,15 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
 	}
 	session := &sseSession{
-		writer:     w,
-		flusher:    flusher,
-		done:       make(chan struct{}),
-		eventQueue: make(chan string, 100),
+		toolsetName: toolsetName,
+		header:      r.Header.Clone(),
+		writer:      w,
+		flusher:     flusher,
+		done:        make(chan struct{}),
+		eventQueue:  make(chan string, 100),
+		requests:    newInFlightRequests(),
 	}
+	session.logger = newSessionLogger(s.logger, session.send)
 	s.sseManager.add(sessionId, session)
 	defer s.sseManager.remove(sessionId)
 
@@ -320,11 +907,232 @@ func sseHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// methodNotAllowed handles all mcp messages.
-func methodNotAllowed(s *Server, w http.ResponseWriter, r *http.Request) {
-	err := fmt.Errorf("toolbox does not support streaming in streamable HTTP transport")
-	s.logger.DebugContext(r.Context(), err.Error())
-	_ = render.Render(w, r, newErrResponse(err, http.StatusMethodNotAllowed))
+// wsHandler upgrades the connection to the WebSocket transport, and serves
+// the session of the connection until it is closed.
+func wsHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/ws")
+	r = r.WithContext(ctx)
+
+	sessionId := uuid.New().String()
+	toolsetName := chi.URLParam(r, "toolsetName")
+	s.logger.DebugContext(ctx, fmt.Sprintf("toolset name: %s", toolsetName))
+	span.SetAttributes(attribute.String("session_id", sessionId))
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		status := "success"
+		if err != nil {
+			status = "error"
+		}
+		s.instrumentation.McpWebSocket.Add(
+			r.Context(),
+			1,
+			metric.WithAttributes(attribute.String("toolbox.toolset.name", toolsetName)),
+			metric.WithAttributes(attribute.String("toolbox.websocket.sessionId", sessionId)),
+			metric.WithAttributes(attribute.String("toolbox.operation.status", status)),
+		)
+	}()
+
+	// the upgrader responds with an HTTP error if the upgrade fails
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		return
+	}
+	session := newWsSession(s, conn, toolsetName, r.Header.Clone())
+	err = session.serve(util.WithLogger(ctx, s.logger))
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		return
+	}
+	s.logger.DebugContext(ctx, "client disconnected")
+}
+
+// streamableSessionFromRequest returns the streamable HTTP session identified
+// by the `Mcp-Session-Id` header, or the HTTP status to respond with if the
+// session is not available. A session is only available at the endpoint of
+// the toolset it was initialized with.
+func streamableSessionFromRequest(s *Server, r *http.Request) (*streamableSession, int, error) {
+	sessionId := r.Header.Get("Mcp-Session-Id")
+	if sessionId == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("missing `Mcp-Session-Id` header")
+	}
+	session, ok := s.streamableManager.get(sessionId)
+	if !ok {
+		return nil, http.StatusNotFound, fmt.Errorf("session %q does not exist or has been terminated", sessionId)
+	}
+	if toolsetName := chi.URLParam(r, "toolsetName"); session.toolsetName != toolsetName {
+		return nil, http.StatusNotFound, fmt.Errorf("session %q does not exist for toolset %q", sessionId, toolsetName)
+	}
+	return session, http.StatusOK, nil
+}
+
+// streamHandler opens the stream of server-initiated messages of a streamable
+// HTTP session.
+func streamHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/mcp/stream")
+	r = r.WithContext(ctx)
+
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	session, status, err := streamableSessionFromRequest(s, r)
+	if err != nil {
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	span.SetAttributes(attribute.String("session_id", session.id))
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err = fmt.Errorf("unable to retrieve flusher for sse")
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return
+	}
+	if !s.streamableManager.openStream(session) {
+		err = fmt.Errorf("a stream is already open for session %q", session.id)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusConflict))
+		return
+	}
+	defer s.streamableManager.closeStream(session)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Mcp-Session-Id", session.id)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	clientClose := r.Context().Done()
+	for {
+		select {
+		// Ensure that only a single responses are written at once
+		case event := <-session.eventQueue:
+			fmt.Fprint(w, event)
+			s.logger.DebugContext(ctx, fmt.Sprintf("sending event: %s", event))
+			flusher.Flush()
+		case <-session.done:
+			s.logger.DebugContext(ctx, "session terminated")
+			return
+		case <-clientClose:
+			s.logger.DebugContext(ctx, "client disconnected")
+			return
+		}
+	}
+}
+
+// deleteHandler terminates a streamable HTTP session.
+func deleteHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	session, status, err := streamableSessionFromRequest(s, r)
+	if err != nil {
+		s.logger.DebugContext(r.Context(), err.Error())
+		_ = render.Render(w, r, newErrResponse(err, status))
+		return
+	}
+	s.streamableManager.remove(session.id)
+	s.logger.DebugContext(r.Context(), fmt.Sprintf("session %s terminated", session.id))
+	w.WriteHeader(http.StatusOK)
+}
+
+// streamHeartbeatInterval is the interval of the comments that keep a
+// streamed response alive while its message is processed.
+const streamHeartbeatInterval = 15 * time.Second
+
+// shouldStreamResponse reports whether the response to a message is sent as
+// an sse stream. Since v2025-03-26, clients that accept `text/event-stream`
+// receive the responses of tool calls, which can be long-running, as a stream.
+func shouldStreamResponse(r *http.Request, body []byte, protocolVersion string) bool {
+	if protocolVersion == "" || protocolVersion == v20241105.PROTOCOL_VERSION {
+		return false
+	}
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		return false
+	}
+	isToolsCall := func(m jsonrpc.BaseMessage) bool {
+		return m.Id != nil && m.Method == v20250326.TOOLS_CALL
+	}
+	// a batch is streamed if it includes a tool call
+	if isBatchMessage(body) {
+		var messages []jsonrpc.BaseMessage
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return false
+		}
+		return slices.ContainsFunc(messages, isToolsCall)
+	}
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(body, &baseMessage); err != nil {
+		return false
+	}
+	return isToolsCall(baseMessage)
+}
+
+// streamMcpMessage processes a message and sends its response as an sse
+// event. The notifications sent while the message is processed are sent as
+// events before the response, and comments keep the connection alive.
+func streamMcpMessage(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, process func(notify func(any)) (any, error)) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	type result struct {
+		res any
+		err error
+	}
+	resChan := make(chan result, 1)
+	notifications := make(chan any, 100)
+	notify := func(notification any) {
+		select {
+		case notifications <- notification:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		res, err := process(notify)
+		resChan <- result{res: res, err: err}
+	}()
+
+	writeEvent := func(message any) {
+		eventData, _ := json.Marshal(message)
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", eventData)
+		flusher.Flush()
+	}
+	ticker := time.NewTicker(streamHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case notification := <-notifications:
+			writeEvent(notification)
+		case r := <-resChan:
+			for len(notifications) > 0 {
+				writeEvent(<-notifications)
+			}
+			// requests cancelled by the client have no response
+			if r.res != nil {
+				writeEvent(r.res)
+			}
+			return r.err
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 // httpHandler handles all mcp messages.
@@ -349,14 +1157,6 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// check if client have `Mcp-Session-Id` header
-	// if `Mcp-Session-Id` header is set, we are using v2025-03-26 since
-	// previous version doesn't use this header.
-	headerSessionId := r.Header.Get("Mcp-Session-Id")
-	if headerSessionId != "" {
-		protocolVersion = v20250326.PROTOCOL_VERSION
-	}
-
 	toolsetName := chi.URLParam(r, "toolsetName")
 	s.logger.DebugContext(ctx, fmt.Sprintf("toolset name: %s", toolsetName))
 	span.SetAttributes(attribute.String("toolset_name", toolsetName))
@@ -380,6 +1180,42 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		)
 	}()
 
+	// check if client have `Mcp-Session-Id` header
+	// if `Mcp-Session-Id` header is set, the client uses the streamable HTTP
+	// transport of v2025-03-26 or later, and the session holds the negotiated
+	// protocol version.
+	var httpSession *streamableSession
+	if r.Header.Get("Mcp-Session-Id") != "" {
+		var status int
+		httpSession, status, err = streamableSessionFromRequest(s, r)
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, status))
+			return
+		}
+		sessionId = httpSession.id
+		protocolVersion = httpSession.protocol
+	}
+
+	// since v2025-06-18, clients send the negotiated protocol version in the
+	// `MCP-Protocol-Version` header, which must match the version of the
+	// session if there is one.
+	if headerVersion := r.Header.Get("MCP-Protocol-Version"); headerVersion != "" {
+		if !slices.Contains(mcp.SUPPORTED_PROTOCOL_VERSIONS, headerVersion) {
+			err = fmt.Errorf("unsupported protocol version: %s", headerVersion)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+		if httpSession != nil && headerVersion != httpSession.protocol {
+			err = fmt.Errorf("protocol version %s does not match the protocol version %s of session %q", headerVersion, httpSession.protocol, httpSession.id)
+			s.logger.DebugContext(ctx, err.Error())
+			_ = render.Render(w, r, newErrResponse(err, http.StatusBadRequest))
+			return
+		}
+		protocolVersion = headerVersion
+	}
+
 	// Read and returns a body from io.Reader
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -387,13 +1223,55 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		id := uuid.New().String()
 		s.logger.DebugContext(ctx, err.Error())
 		render.JSON(w, r, jsonrpc.NewError(id, jsonrpc.PARSE_ERROR, err.Error(), nil))
+		return
+	}
+
+	// auth services verify the headers of the request, or the headers of the
+	// sse session if the request does not include them
+	header := r.Header.Clone()
+	if session != nil {
+		for k, v := range session.header {
+			if _, ok := header[k]; !ok {
+				header[k] = v
+			}
+		}
+	}
+
+	// requests of a session can be cancelled by the client, and notifications
+	// and log messages are sent on the stream of the session
+	mc := &messageContext{}
+	switch {
+	case session != nil:
+		mc.requests = session.requests
+		mc.notify = func(notification any) { session.send(notification) }
+		mc.logger = session.logger
+	case httpSession != nil:
+		mc.requests = httpSession.requests
+		mc.notify = func(notification any) { httpSession.send(notification) }
+		mc.logger = httpSession.logger
+	}
+
+	// stream the response if the client accepts it
+	if flusher, ok := w.(http.Flusher); ok && shouldStreamResponse(r, body, protocolVersion) {
+		if httpSession != nil {
+			w.Header().Set("Mcp-Session-Id", sessionId)
+		}
+		err = streamMcpMessage(ctx, w, flusher, func(notify func(any)) (any, error) {
+			// notifications are sent on the stream of the response
+			streamMc := &messageContext{requests: mc.requests, notify: notify, logger: mc.logger}
+			_, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, header, streamMc)
+			return res, err
+		})
+		if err != nil {
+			s.logger.DebugContext(ctx, err.Error())
+		}
+		return
 	}
 
-	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName)
+	v, res, err := processMcpMessage(ctx, body, s, protocolVersion, toolsetName, header, mc)
 	// notifications will return empty string
 	if res == nil {
-		// Notifications do not expect a response
-		// Toolbox doesn't do anything with notifications yet
+		// Notifications and cancelled requests do not expect a response
 		w.WriteHeader(http.StatusAccepted)
 		return
 	}
@@ -401,9 +1279,13 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 		s.logger.DebugContext(ctx, err.Error())
 	}
 
-	// for v20250326, add the `Mcp-Session-Id` header
-	if v == v20250326.PROTOCOL_VERSION {
-		sessionId = uuid.New().String()
+	// since v20250326, the initialize request starts a session that is
+	// identified by the `Mcp-Session-Id` header
+	if v == v20250326.PROTOCOL_VERSION || v == v20250618.PROTOCOL_VERSION {
+		httpSession = s.streamableManager.create(v, toolsetName, s.logger)
+		sessionId = httpSession.id
+	}
+	if httpSession != nil {
 		w.Header().Set("Mcp-Session-Id", sessionId)
 	}
 
@@ -424,31 +1306,40 @@ func httpHandler(s *Server, w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, res)
 }
 
-// processMcpMessage process the messages received from clients
-func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string) (string, any, error) {
+// processMcpMessage process the messages received from clients. mc holds the
+// session state of the message, and may be nil.
+func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header, mc *messageContext) (string, any, error) {
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
 		return "", jsonrpc.NewError("", jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
 	}
+	// the log messages of a session are also sent to its client
+	if mc != nil && mc.logger != nil {
+		logger = mc.logger
+		ctx = util.WithLogger(ctx, logger)
+	}
 
 	if protocolVersion == "" {
 		protocolVersion = v20241105.PROTOCOL_VERSION
 	}
 
+	// check if user is sending a batch request, which the 2025-06-18
+	// specification no longer supports
+	if isBatchMessage(body) {
+		if protocolVersion == v20250618.PROTOCOL_VERSION {
+			id := uuid.New().String()
+			err = fmt.Errorf("batch requests are not supported by protocol version %s", protocolVersion)
+			return "", jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+		}
+		res, err := processMcpBatch(ctx, body, s, protocolVersion, toolsetName, header, mc)
+		return "", res, err
+	}
+
 	// Generic baseMessage could either be a JSONRPCNotification or JSONRPCRequest
 	var baseMessage jsonrpc.BaseMessage
 	if err = util.DecodeJSON(bytes.NewBuffer(body), &baseMessage); err != nil {
 		// Generate a new uuid if unable to decode
 		id := uuid.New().String()
-
-		// check if user is sending a batch request
-		var a []any
-		unmarshalErr := json.Unmarshal(body, &a)
-		if unmarshalErr == nil {
-			err = fmt.Errorf("not supporting batch requests")
-			return "", jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
-		}
-
 		return "", jsonrpc.NewError(id, jsonrpc.PARSE_ERROR, err.Error(), nil), err
 	}
 
@@ -467,24 +1358,158 @@ func processMcpMessage(ctx context.Context, body []byte, s *Server, protocolVers
 
 	// Check if message is a notification
 	if baseMessage.Id == nil {
-		err := mcp.NotificationHandler(ctx, body)
+		err := mcp.NotificationHandler(ctx, body, mc.cancel)
 		return "", nil, err
 	}
 
 	switch baseMessage.Method {
 	case mcputil.INITIALIZE:
-		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version)
+		toolset, _ := s.ResourceMgr.GetToolset(toolsetName)
+		promptset, _ := s.ResourceMgr.GetPromptset(toolsetName)
+		res, v, err := mcp.InitializeResponse(ctx, baseMessage.Id, body, s.version, toolset.Instructions, promptset, toolsetSources(toolset, s.ResourceMgr.GetSourcesMap()))
 		if err != nil {
 			return "", res, err
 		}
 		return v, res, err
+	case mcputil.LOGGING_SET_LEVEL:
+		var setLevel func(string) error
+		if mc != nil && mc.logger != nil {
+			setLevel = mc.logger.SetLevel
+		}
+		res, err := mcp.SetLevelHandler(baseMessage.Id, body, setLevel)
+		return "", res, err
 	default:
 		toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
 		if !ok {
 			err = fmt.Errorf("toolset does not exist")
 			return "", jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 		}
-		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), body)
+		promptset, _ := s.ResourceMgr.GetPromptset(toolsetName)
+		// requests of a session can be cancelled by the client
+		if mc != nil && mc.requests != nil {
+			var done func()
+			ctx, done = mc.requests.start(ctx, baseMessage.Id)
+			defer done()
+		}
+		// tool lists, tool calls and completions are authenticated with the
+		// claims verified from the headers, and tool calls can report their
+		// progress
+		sourcesMap := toolsetSources(toolset, s.ResourceMgr.GetSourcesMap())
+		switch baseMessage.Method {
+		case v20250618.TOOLS_CALL:
+			ctx = util.WithClaims(ctx, claimsFromHeader(ctx, s, header))
+			ctx = withProgressReporter(ctx, body, protocolVersion, mc)
+		case v20250618.TOOLS_LIST, v20250618.COMPLETION_COMPLETE:
+			ctx = util.WithClaims(ctx, claimsFromHeader(ctx, s, header))
+		case v20250618.RESOURCES_LIST, v20250618.RESOURCES_READ:
+			// the resources of the toolset are only published to the
+			// requests that its auth policies allow
+			if !toolset.AuthPolicies.Allows(claimsFromHeader(ctx, s, header)) {
+				sourcesMap = nil
+			}
+		}
+		res, err := mcp.ProcessMethod(ctx, protocolVersion, baseMessage.Id, baseMessage.Method, toolset, s.ResourceMgr.GetToolsMap(), promptset, sourcesMap, s.toolsPageSize, body)
+		// requests cancelled by the client have no response
+		if errors.Is(context.Cause(ctx), errRequestCancelled) {
+			logger.DebugContext(ctx, fmt.Sprintf("request %v cancelled", baseMessage.Id))
+			return "", nil, nil
+		}
 		return "", res, err
 	}
 }
+
+// toolsetSources returns the sources whose resources the toolset publishes.
+func toolsetSources(toolset tools.Toolset, sourcesMap map[string]sources.Source) map[string]sources.Source {
+	m := make(map[string]sources.Source, len(toolset.SourceNames))
+	for _, name := range toolset.SourceNames {
+		if s, ok := sourcesMap[name]; ok {
+			m[name] = s
+		}
+	}
+	return m
+}
+
+// withProgressReporter adds a progress reporter to the context of a request
+// if the client requested progress notifications with a progress token.
+func withProgressReporter(ctx context.Context, body []byte, protocolVersion string, mc *messageContext) context.Context {
+	if mc == nil || mc.notify == nil {
+		return ctx
+	}
+	var req jsonrpc.Request
+	if err := json.Unmarshal(body, &req); err != nil || req.Params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	token := req.Params.Meta.ProgressToken
+	return util.WithProgressReporter(ctx, func(progress, total float64, message string) {
+		mc.notify(mcp.NewProgressNotification(protocolVersion, token, progress, total, message))
+	})
+}
+
+// isBatchMessage reports whether the body is a JSON-RPC batch, i.e. an array
+// of requests and notifications.
+func isBatchMessage(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// processMcpBatch processes the messages of a batch request, up to
+// `s.batchConcurrency` at once, and returns the responses to its requests in
+// the order of the batch. Since notifications have no response, nil is
+// returned for a batch of notifications.
+func processMcpBatch(ctx context.Context, body []byte, s *Server, protocolVersion string, toolsetName string, header http.Header, mc *messageContext) (any, error) {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(body, &messages); err != nil {
+		// Generate a new uuid if unable to decode
+		id := uuid.New().String()
+		return jsonrpc.NewError(id, jsonrpc.PARSE_ERROR, err.Error(), nil), err
+	}
+	if len(messages) == 0 {
+		id := uuid.New().String()
+		err := fmt.Errorf("batch request is empty")
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	responses := make([]any, len(messages))
+	errs := make([]error, len(messages))
+	sem := make(chan struct{}, max(s.batchConcurrency, 1))
+	var wg sync.WaitGroup
+	for i, message := range messages {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			responses[i], errs[i] = processBatchMessage(ctx, message, s, protocolVersion, toolsetName, header, mc)
+		}()
+	}
+	wg.Wait()
+
+	// notifications do not expect a response
+	res := make([]any, 0, len(responses))
+	for _, r := range responses {
+		if r != nil {
+			res = append(res, r)
+		}
+	}
+	if len(res) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return res, errors.Join(errs...)
+}
+
+// processBatchMessage processes a single message of a batch request.
+func processBatchMessage(ctx context.Context, message []byte, s *Server, protocolVersion string, toolsetName string, header http.Header, mc *messageContext) (any, error) {
+	if isBatchMessage(message) {
+		id := uuid.New().String()
+		err := fmt.Errorf("nested batch requests are not supported")
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	// the initialize request negotiates the session, and must not be batched
+	var baseMessage jsonrpc.BaseMessage
+	if err := json.Unmarshal(message, &baseMessage); err == nil && baseMessage.Method == mcputil.INITIALIZE {
+		err = fmt.Errorf("initialize request must not be part of a batch")
+		return jsonrpc.NewError(baseMessage.Id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	_, res, err := processMcpMessage(ctx, message, s, protocolVersion, toolsetName, header, mc)
+	return res, err
+}
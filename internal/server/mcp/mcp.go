@@ -20,24 +20,28 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	mcputil "github.com/googleapis/genai-toolbox/internal/server/mcp/util"
 	v20241105 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20241105"
 	v20250326 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250326"
+	v20250618 "github.com/googleapis/genai-toolbox/internal/server/mcp/v20250618"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
 
 // LATEST_PROTOCOL_VERSION is the latest version of the MCP protocol supported.
 // Update the version used in InitializeResponse when this value is updated.
-const LATEST_PROTOCOL_VERSION = v20250326.PROTOCOL_VERSION
+const LATEST_PROTOCOL_VERSION = v20250618.PROTOCOL_VERSION
 
 // SUPPORTED_PROTOCOL_VERSIONS is the MCP protocol versions that are supported.
-var SUPPORTED_PROTOCOL_VERSIONS = []string{v20241105.PROTOCOL_VERSION, v20250326.PROTOCOL_VERSION}
+var SUPPORTED_PROTOCOL_VERSIONS = []string{v20241105.PROTOCOL_VERSION, v20250326.PROTOCOL_VERSION, v20250618.PROTOCOL_VERSION}
 
 // InitializeResponse runs capability negotiation and protocol version agreement.
-// This is the Initialization phase of the lifecycle for MCP client-server connections.
-// Always start with the latest protocol version supported.
-func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string) (any, string, error) {
+// It returns the capabilities of the server and the instructions of the
+// toolset, with the agreed protocol version.
+func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte, toolboxVersion string, instructions string, promptset prompts.Promptset, sourcesMap map[string]sources.Source) (any, string, error) {
 	var req mcputil.InitializeRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp initialize request: %w", err)
@@ -52,18 +56,31 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 		protocolVersion = LATEST_PROTOCOL_VERSION
 	}
 
-	toolsListChanged := false
+	// clients are notified when the tools change during a dynamic reload
+	toolsListChanged := true
 	result := mcputil.InitializeResult{
 		ProtocolVersion: protocolVersion,
 		Capabilities: mcputil.ServerCapabilities{
 			Tools: &mcputil.ListChanged{
 				ListChanged: &toolsListChanged,
 			},
+			Resources: resourcesCapabilities(sourcesMap),
+			Prompts:   promptsCapabilities(promptset),
+			// the log messages of the session are sent to the client once it
+			// sets a level with logging/setLevel
+			Logging: &struct{}{},
 		},
 		ServerInfo: mcputil.Implementation{
 			Name:    mcputil.SERVER_NAME,
 			Version: toolboxVersion,
 		},
+		Instructions: instructions,
+	}
+	// arguments of tools and prompts are completed with completion/complete.
+	// The completions capability was introduced in v2025-03-26, although
+	// completion/complete requests are also answered for v2024-11-05
+	if protocolVersion != v20241105.PROTOCOL_VERSION {
+		result.Capabilities.Completions = &struct{}{}
 	}
 	res := jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -74,24 +91,177 @@ func InitializeResponse(ctx context.Context, id jsonrpc.RequestId, body []byte,
 	return res, protocolVersion, nil
 }
 
+// resourcesCapabilities returns the resources capabilities of the server, or
+// nil if none of the sources implement sources.ResourceProvider.
+func resourcesCapabilities(sourcesMap map[string]sources.Source) *mcputil.ResourcesCapabilities {
+	for _, s := range sourcesMap {
+		if _, ok := s.(sources.ResourceProvider); ok {
+			subscribe, listChanged := false, false
+			return &mcputil.ResourcesCapabilities{
+				Subscribe:   &subscribe,
+				ListChanged: &listChanged,
+			}
+		}
+	}
+	return nil
+}
+
+// promptsCapabilities returns the prompts capabilities of the server, or nil
+// if the promptset has no prompts. Clients are notified when the prompts change
+// during a dynamic reload.
+func promptsCapabilities(promptset prompts.Promptset) *mcputil.ListChanged {
+	if len(promptset.Prompts) == 0 {
+		return nil
+	}
+	listChanged := true
+	return &mcputil.ListChanged{ListChanged: &listChanged}
+}
+
+// NewListChangedNotification returns the notification that informs clients
+// that a list has changed, e.g. mcputil.TOOLS_LIST_CHANGED.
+func NewListChangedNotification(method string) jsonrpc.JSONRPCNotification {
+	return jsonrpc.JSONRPCNotification{
+		Jsonrpc:      jsonrpc.JSONRPC_VERSION,
+		Notification: jsonrpc.Notification{Method: method},
+	}
+}
+
+// NewProgressNotification returns the notification that informs clients of
+// the progress of the request identified by token.
+func NewProgressNotification(mcpVersion string, token jsonrpc.ProgressToken, progress, total float64, message string) mcputil.ProgressNotification {
+	// progress messages were introduced in v2025-03-26
+	if mcpVersion == v20241105.PROTOCOL_VERSION {
+		message = ""
+	}
+	return mcputil.ProgressNotification{
+		JSONRPCNotification: jsonrpc.JSONRPCNotification{
+			Jsonrpc:      jsonrpc.JSONRPC_VERSION,
+			Notification: jsonrpc.Notification{Method: mcputil.PROGRESS},
+		},
+		Params: mcputil.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      progress,
+			Total:         total,
+			Message:       message,
+		},
+	}
+}
+
+// NewLoggingMessageNotification returns the notification that sends a log
+// message of the given Toolbox severity to clients. The message is sent as is,
+// or with its key-value pairs as an object if it has any.
+func NewLoggingMessageNotification(severity, msg string, keysAndValues ...interface{}) mcputil.LoggingMessageNotification {
+	var data any = msg
+	if len(keysAndValues) > 0 {
+		fields := map[string]any{"message": msg}
+		for i := 0; i+1 < len(keysAndValues); i += 2 {
+			fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+		}
+		data = fields
+	}
+	return mcputil.LoggingMessageNotification{
+		JSONRPCNotification: jsonrpc.JSONRPCNotification{
+			Jsonrpc:      jsonrpc.JSONRPC_VERSION,
+			Notification: jsonrpc.Notification{Method: mcputil.MESSAGE},
+		},
+		Params: mcputil.LoggingMessageNotificationParams{
+			Level:  severityToLoggingLevel(severity),
+			Logger: mcputil.SERVER_NAME,
+			Data:   data,
+		},
+	}
+}
+
+// severityToLoggingLevel returns the MCP logging level of a Toolbox severity.
+func severityToLoggingLevel(severity string) mcputil.LoggingLevel {
+	switch severity {
+	case log.Debug:
+		return mcputil.LoggingLevelDebug
+	case log.Info:
+		return mcputil.LoggingLevelInfo
+	case log.Warn:
+		return mcputil.LoggingLevelWarning
+	default:
+		return mcputil.LoggingLevelError
+	}
+}
+
+// loggingLevelToSeverity returns the Toolbox severity of the messages that are
+// sent for an MCP logging level. Toolbox has fewer severities than MCP, so
+// notice is handled as warning, and the levels above error as error.
+func loggingLevelToSeverity(level mcputil.LoggingLevel) (string, error) {
+	switch level {
+	case mcputil.LoggingLevelDebug:
+		return log.Debug, nil
+	case mcputil.LoggingLevelInfo:
+		return log.Info, nil
+	case mcputil.LoggingLevelNotice, mcputil.LoggingLevelWarning:
+		return log.Warn, nil
+	case mcputil.LoggingLevelError, mcputil.LoggingLevelCritical, mcputil.LoggingLevelAlert, mcputil.LoggingLevelEmergency:
+		return log.Error, nil
+	default:
+		return "", fmt.Errorf("invalid logging level: %q", level)
+	}
+}
+
+// SetLevelHandler sets the level of the log messages that are sent to the
+// client with setLevel, which is nil if the client has no session.
+func SetLevelHandler(id jsonrpc.RequestId, body []byte, setLevel func(severity string) error) (any, error) {
+	if setLevel == nil {
+		err := fmt.Errorf("logging requires a session")
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	var req mcputil.SetLevelRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp logging/setLevel request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	severity, err := loggingLevelToSeverity(req.Params.Level)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	if err := setLevel(severity); err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  struct{}{},
+	}, nil
+}
+
 // NotificationHandler process notifications request. It MUST NOT send a response.
-// Currently Toolbox does not process any notifications.
-func NotificationHandler(ctx context.Context, body []byte) error {
+// Cancelled notifications cancel the in-flight request with cancel, if set.
+// Toolbox does not process any other notifications.
+func NotificationHandler(ctx context.Context, body []byte, cancel func(jsonrpc.RequestId)) error {
 	var notification jsonrpc.JSONRPCNotification
 	if err := json.Unmarshal(body, &notification); err != nil {
 		return fmt.Errorf("invalid notification request: %w", err)
 	}
+	switch notification.Method {
+	case mcputil.CANCELLED:
+		var req mcputil.CancelledNotification
+		if err := json.Unmarshal(body, &req); err != nil {
+			return fmt.Errorf("invalid cancelled notification: %w", err)
+		}
+		if cancel != nil {
+			cancel(req.Params.RequestId)
+		}
+	}
 	return nil
 }
 
 // ProcessMethod returns a response for the request.
 // This is the Operation phase of the lifecycle for MCP client-server connections.
-func ProcessMethod(ctx context.Context, mcpVersion string, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, body []byte) (any, error) {
+// Tools are listed in pages of pageSize tools, or all at once if pageSize is 0.
+func ProcessMethod(ctx context.Context, mcpVersion string, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, promptset prompts.Promptset, sourcesMap map[string]sources.Source, pageSize int, body []byte) (any, error) {
 	switch mcpVersion {
+	case v20250618.PROTOCOL_VERSION:
+		return v20250618.ProcessMethod(ctx, id, method, toolset, tools, promptset, sourcesMap, pageSize, body)
 	case v20250326.PROTOCOL_VERSION:
-		return v20250326.ProcessMethod(ctx, id, method, toolset, tools, body)
+		return v20250326.ProcessMethod(ctx, id, method, toolset, tools, promptset, sourcesMap, pageSize, body)
 	case v20241105.PROTOCOL_VERSION:
-		return v20241105.ProcessMethod(ctx, id, method, toolset, tools, body)
+		return v20241105.ProcessMethod(ctx, id, method, toolset, tools, promptset, sourcesMap, pageSize, body)
 	default:
 		err := fmt.Errorf("invalid protocol version: %s", mcpVersion)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
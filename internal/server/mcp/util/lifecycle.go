@@ -21,6 +21,9 @@ const (
 	SERVER_NAME = "Toolbox"
 	// methods that are supported
 	INITIALIZE = "initialize"
+	// notifications that are sent by the server
+	TOOLS_LIST_CHANGED   = "notifications/tools/list_changed"
+	PROMPTS_LIST_CHANGED = "notifications/prompts/list_changed"
 )
 
 /* Initialization */
@@ -86,7 +89,22 @@ type ClientCapabilities struct {
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
-	Tools *ListChanged `json:"tools,omitempty"`
+	Tools     *ListChanged           `json:"tools,omitempty"`
+	Resources *ResourcesCapabilities `json:"resources,omitempty"`
+	Prompts   *ListChanged           `json:"prompts,omitempty"`
+	// Present if the server supports sending log messages to the client.
+	Logging *struct{} `json:"logging,omitempty"`
+	// Present if the server supports argument autocompletion suggestions.
+	// Only advertised since v2025-03-26.
+	Completions *struct{} `json:"completions,omitempty"`
+}
+
+// ResourcesCapabilities represents the resources features that the server supports.
+type ResourcesCapabilities struct {
+	// Whether this server supports subscribing to resource updates.
+	Subscribe *bool `json:"subscribe,omitempty"`
+	// Whether this server supports notifications for changes to the resource list.
+	ListChanged *bool `json:"listChanged,omitempty"`
 }
 
 // Implementation describes the name and version of an MCP implementation.
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+
+const (
+	// methods that are supported
+	LOGGING_SET_LEVEL = "logging/setLevel"
+	// notifications that are sent by the client
+	CANCELLED = "notifications/cancelled"
+	// notifications that are sent by the server
+	PROGRESS = "notifications/progress"
+	MESSAGE  = "notifications/message"
+)
+
+/* Cancellation */
+
+// CancelledNotificationParams identifies the request that is cancelled.
+type CancelledNotificationParams struct {
+	// The ID of the request to cancel.
+	RequestId jsonrpc.RequestId `json:"requestId"`
+	// An optional string describing the reason for the cancellation.
+	Reason string `json:"reason,omitempty"`
+}
+
+// CancelledNotification can be sent by either side to indicate that it is
+// cancelling a previously-issued request.
+type CancelledNotification struct {
+	jsonrpc.Notification
+	Params CancelledNotificationParams `json:"params"`
+}
+
+/* Progress */
+
+// ProgressNotificationParams describes the progress of a request.
+type ProgressNotificationParams struct {
+	// The progress token which was given in the initial request.
+	ProgressToken jsonrpc.ProgressToken `json:"progressToken"`
+	// The progress thus far. This should increase every time progress is made,
+	// even if the total is unknown.
+	Progress float64 `json:"progress"`
+	// Total number of items to process (or total progress required), if known.
+	Total float64 `json:"total,omitempty"`
+	// An optional message describing the current progress. Only supported
+	// since v2025-03-26.
+	Message string `json:"message,omitempty"`
+}
+
+// ProgressNotification is an out-of-band notification used to inform the
+// receiver of a progress update for a long-running request.
+type ProgressNotification struct {
+	jsonrpc.JSONRPCNotification
+	Params ProgressNotificationParams `json:"params"`
+}
+
+/* Logging */
+
+// LoggingLevel is the severity of a log message. These map to syslog message
+// severities, as specified in RFC-5424.
+type LoggingLevel string
+
+const (
+	LoggingLevelDebug     LoggingLevel = "debug"
+	LoggingLevelInfo      LoggingLevel = "info"
+	LoggingLevelNotice    LoggingLevel = "notice"
+	LoggingLevelWarning   LoggingLevel = "warning"
+	LoggingLevelError     LoggingLevel = "error"
+	LoggingLevelCritical  LoggingLevel = "critical"
+	LoggingLevelAlert     LoggingLevel = "alert"
+	LoggingLevelEmergency LoggingLevel = "emergency"
+)
+
+// SetLevelRequest is sent from the client to the server to enable or adjust
+// logging.
+type SetLevelRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The level of logging that the client wants to receive from the
+		// server. The server should send all logs at this level and higher
+		// (i.e., more severe) to the client as notifications/message.
+		Level LoggingLevel `json:"level"`
+	} `json:"params"`
+}
+
+// LoggingMessageNotificationParams holds a log message.
+type LoggingMessageNotificationParams struct {
+	// The severity of this log message.
+	Level LoggingLevel `json:"level"`
+	// An optional name of the logger issuing this message.
+	Logger string `json:"logger,omitempty"`
+	// The data to be logged, such as a string message or an object.
+	Data any `json:"data"`
+}
+
+// LoggingMessageNotification is a notification of a log message passed from
+// server to client.
+type LoggingMessageNotification struct {
+	jsonrpc.JSONRPCNotification
+	Params LoggingMessageNotificationParams `json:"params"`
+}
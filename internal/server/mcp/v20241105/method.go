@@ -18,35 +18,59 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/util"
 )
 
 // ProcessMethod returns a response for the request.
-func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, body []byte) (any, error) {
+func ProcessMethod(ctx context.Context, id jsonrpc.RequestId, method string, toolset tools.Toolset, tools map[string]tools.Tool, promptset prompts.Promptset, sourcesMap map[string]sources.Source, pageSize int, body []byte) (any, error) {
 	switch method {
 	case TOOLS_LIST:
-		return toolsListHandler(id, toolset, body)
+		return toolsListHandler(ctx, id, toolset, pageSize, body)
 	case TOOLS_CALL:
-		return toolsCallHandler(ctx, id, tools, body)
+		return toolsCallHandler(ctx, id, toolset, tools, body)
+	case RESOURCES_LIST:
+		return resourcesListHandler(ctx, id, sourcesMap, body)
+	case RESOURCES_READ:
+		return resourcesReadHandler(ctx, id, sourcesMap, body)
+	case PROMPTS_LIST:
+		return promptsListHandler(id, promptset, body)
+	case PROMPTS_GET:
+		return promptsGetHandler(ctx, id, promptset, body)
+	case COMPLETION_COMPLETE:
+		return completionCompleteHandler(ctx, id, toolset, tools, promptset, body)
 	default:
 		err := fmt.Errorf("invalid method %s", method)
 		return jsonrpc.NewError(id, jsonrpc.METHOD_NOT_FOUND, err.Error(), nil), err
 	}
 }
 
-func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte) (any, error) {
+// toolsListHandler generate a response for tools list. The tools are listed
+// in pages of pageSize tools, or all at once if pageSize is 0. The tools that
+// the auth policies do not allow for the claims of the request are left out.
+func toolsListHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, pageSize int, body []byte) (any, error) {
 	var req ListToolsRequest
 	if err := json.Unmarshal(body, &req); err != nil {
 		err = fmt.Errorf("invalid mcp tools list request: %w", err)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
 	}
 
+	manifests := toolset.AllowedMcpManifest(util.ClaimsFromContext(ctx))
+	start, end, next, err := util.Paginate(len(manifests), string(req.Params.Cursor), pageSize)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
 	result := ListToolsResult{
-		Tools: toolset.McpManifest,
+		PaginatedResult: PaginatedResult{NextCursor: Cursor(next)},
+		Tools:           toolsManifest(manifests[start:end]),
 	}
 	return jsonrpc.JSONRPCResponse{
 		Jsonrpc: jsonrpc.JSONRPC_VERSION,
@@ -55,8 +79,23 @@ func toolsListHandler(id jsonrpc.RequestId, toolset tools.Toolset, body []byte)
 	}, nil
 }
 
-// toolsCallHandler generate a response for tools call.
-func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[string]tools.Tool, body []byte) (any, error) {
+// toolsManifest removes the fields that were introduced in later versions of
+// the protocol from the tool manifests.
+func toolsManifest(manifests []tools.McpManifest) []tools.McpManifest {
+	if manifests == nil {
+		return nil
+	}
+	out := make([]tools.McpManifest, 0, len(manifests))
+	for _, m := range manifests {
+		m.Title, m.OutputSchema, m.Annotations = "", nil, nil
+		out = append(out, m)
+	}
+	return out
+}
+
+// toolsCallHandler generate a response for tools call. Only the tools of the
+// toolset can be called.
+func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, tools map[string]tools.Tool, body []byte) (any, error) {
 	// retrieve logger from context
 	logger, err := util.LoggerFromContext(ctx)
 	if err != nil {
@@ -77,6 +116,10 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 		err = fmt.Errorf("invalid tool name: tool with name %q does not exist", toolName)
 		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
 	}
+	if !toolset.Contains(toolName) {
+		err = fmt.Errorf("invalid tool name: tool with name %q is not part of toolset %q", toolName, toolset.Name)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), map[string]any{"tool": toolName, "toolset": toolset.Name}), err
+	}
 
 	// marshal arguments and decode it using decodeJSON instead to prevent loss between floats/int.
 	aMarshal, err := json.Marshal(toolArgument)
@@ -92,8 +135,17 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 	}
 
 	// claimsFromAuth maps the name of the authservice to the claims retrieved from it.
-	// Since MCP doesn't support auth, an empty map will be use every time.
-	claimsFromAuth := make(map[string]map[string]any)
+	// The claims are verified by the transport from the request headers.
+	claimsFromAuth := util.ClaimsFromContext(ctx)
+
+	if !tool.Authorized(slices.Collect(maps.Keys(claimsFromAuth))) {
+		err = fmt.Errorf("unauthorized Tool call: please make sure you specify the correct auth headers")
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+	if !toolset.Allows(toolName, claimsFromAuth) {
+		err = fmt.Errorf("tool %q is not allowed by the auth policies for the verified claims", toolName)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
 
 	params, err := tool.ParseParams(data, claimsFromAuth)
 	if err != nil {
@@ -102,11 +154,6 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 	}
 	logger.DebugContext(ctx, fmt.Sprintf("invocation params: %s", params))
 
-	if !tool.Authorized([]string{}) {
-		err = fmt.Errorf("unauthorized Tool call: `authRequired` is set for the target Tool")
-		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
-	}
-
 	// run tool invocation and generate response.
 	results, err := tool.Invoke(ctx, params)
 	if err != nil {
@@ -139,3 +186,240 @@ func toolsCallHandler(ctx context.Context, id jsonrpc.RequestId, tools map[strin
 		Result:  CallToolResult{Content: content},
 	}, nil
 }
+
+// resourcesListHandler generate a response for resources list. Resources are
+// collected from every source of sourcesMap, the sources of the toolset, that
+// implements sources.ResourceProvider.
+func resourcesListHandler(ctx context.Context, id jsonrpc.RequestId, sourcesMap map[string]sources.Source, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	var req ListResourcesRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	resources := make([]Resource, 0)
+	for _, name := range slices.Sorted(maps.Keys(sourcesMap)) {
+		provider, ok := sourcesMap[name].(sources.ResourceProvider)
+		if !ok {
+			continue
+		}
+		rs, err := provider.ListResources(ctx)
+		if err != nil {
+			// a single unavailable source should not hide the resources of the others
+			logger.WarnContext(ctx, fmt.Sprintf("unable to list resources of source %q: %s", name, err))
+			continue
+		}
+		for _, r := range rs {
+			resources = append(resources, Resource{
+				URI:         r.URI,
+				Name:        r.Name,
+				Description: r.Description,
+				MimeType:    r.MimeType,
+			})
+		}
+	}
+
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ListResourcesResult{Resources: resources},
+	}, nil
+}
+
+// resourcesReadHandler generate a response for resources read.
+func resourcesReadHandler(ctx context.Context, id jsonrpc.RequestId, sourcesMap map[string]sources.Source, body []byte) (any, error) {
+	var req ReadResourceRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp resources read request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	uri := req.Params.URI
+	sourceName, _, _, err := sources.ParseResourceURI(uri)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	provider, ok := sourcesMap[sourceName].(sources.ResourceProvider)
+	if !ok {
+		err = fmt.Errorf("resource not found: source %q does not provide resources", sourceName)
+		return jsonrpc.NewError(id, RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": uri}), err
+	}
+
+	contents, err := provider.ReadResource(ctx, uri)
+	if err != nil {
+		err = fmt.Errorf("unable to read resource: %w", err)
+		return jsonrpc.NewError(id, RESOURCE_NOT_FOUND, err.Error(), map[string]any{"uri": uri}), err
+	}
+
+	text := TextResourceContents{
+		ResourceContents: ResourceContents{URI: contents.URI, MimeType: contents.MimeType},
+		Text:             contents.Text,
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ReadResourceResult{Contents: []TextResourceContents{text}},
+	}, nil
+}
+
+func promptsListHandler(id jsonrpc.RequestId, promptset prompts.Promptset, body []byte) (any, error) {
+	var req ListPromptsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp prompts list request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	manifests := promptset.McpManifest
+	if manifests == nil {
+		manifests = make([]prompts.McpManifest, 0)
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  ListPromptsResult{Prompts: manifests},
+	}, nil
+}
+
+// promptsGetHandler generate a response for prompts get. Only the prompts of
+// the promptset can be retrieved.
+func promptsGetHandler(ctx context.Context, id jsonrpc.RequestId, promptset prompts.Promptset, body []byte) (any, error) {
+	// retrieve logger from context
+	logger, err := util.LoggerFromContext(ctx)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	var req GetPromptRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp prompts get request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	promptName := req.Params.Name
+	logger.DebugContext(ctx, fmt.Sprintf("prompt name: %s", promptName))
+	prompt, ok := promptset.Prompts[promptName]
+	if !ok {
+		err = fmt.Errorf("invalid prompt name: prompt with name %q does not exist", promptName)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	args, err := prompt.ParseArgs(req.Params.Arguments)
+	if err != nil {
+		err = fmt.Errorf("provided arguments were invalid: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	messages, err := prompt.Render(args)
+	if err != nil {
+		return jsonrpc.NewError(id, jsonrpc.INTERNAL_ERROR, err.Error(), nil), err
+	}
+
+	result := GetPromptResult{
+		Description: prompt.McpManifest().Description,
+		Messages:    make([]PromptMessage, 0, len(messages)),
+	}
+	for _, m := range messages {
+		result.Messages = append(result.Messages, PromptMessage{
+			Role:    Role(m.Role),
+			Content: TextContent{Type: "text", Text: m.Content},
+		})
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  result,
+	}, nil
+}
+
+// completionCompleteHandler generate a response for completion complete. The
+// arguments of the prompts of the promptset and of the tools of the toolset
+// are completed, resources have no arguments.
+func completionCompleteHandler(ctx context.Context, id jsonrpc.RequestId, toolset tools.Toolset, toolsMap map[string]tools.Tool, promptset prompts.Promptset, body []byte) (any, error) {
+	var req CompleteRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		err = fmt.Errorf("invalid mcp completion complete request: %w", err)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_REQUEST, err.Error(), nil), err
+	}
+
+	ref, argument := req.Params.Ref, req.Params.Argument
+	// c is the tool or prompt whose argument is completed
+	var c any
+	var completion *tools.Completion
+	found := false
+	switch ref.Type {
+	case REF_TOOL:
+		tool, ok := toolsMap[ref.Name]
+		if !ok {
+			err := fmt.Errorf("invalid tool name: tool with name %q does not exist", ref.Name)
+			return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+		}
+		if err := toolset.Authorize(ref.Name, tool, util.ClaimsFromContext(ctx)); err != nil {
+			// tools outside of the toolset are invalid references, as they
+			// are not found by the API
+			code := jsonrpc.INVALID_REQUEST
+			if errors.Is(err, tools.ErrToolNotIncluded) {
+				code = jsonrpc.INVALID_PARAMS
+			}
+			return jsonrpc.NewError(id, code, err.Error(), nil), err
+		}
+		c = tool
+		for _, p := range tool.Manifest().Parameters {
+			if p.Name == argument.Name {
+				completion, found = p.Completion, true
+			}
+		}
+	case REF_PROMPT:
+		prompt, ok := promptset.Prompts[ref.Name]
+		if !ok {
+			err := fmt.Errorf("invalid prompt name: prompt with name %q does not exist", ref.Name)
+			return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+		}
+		c = prompt
+		for _, a := range prompt.McpManifest().Arguments {
+			if a.Name == argument.Name {
+				completion, found = a.Completion, true
+			}
+		}
+	case REF_RESOURCE:
+		found = true
+	default:
+		err := fmt.Errorf("invalid reference type: %q", ref.Type)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+	if !found {
+		err := fmt.Errorf("invalid argument name: %q", argument.Name)
+		return jsonrpc.NewError(id, jsonrpc.INVALID_PARAMS, err.Error(), nil), err
+	}
+
+	values := []string{}
+	if c != nil {
+		var err error
+		values, err = tools.CompleteArgument(ctx, c, completion, toolset, toolsMap, argument.Name, argument.Value, nil)
+		if err != nil {
+			// the completion source tool can not be used by the request
+			code := jsonrpc.INTERNAL_ERROR
+			if errors.Is(err, tools.ErrToolNotIncluded) || errors.Is(err, tools.ErrToolUnauthorized) || errors.Is(err, tools.ErrToolNotAllowed) {
+				code = jsonrpc.INVALID_REQUEST
+			}
+			return jsonrpc.NewError(id, code, err.Error(), nil), err
+		}
+		if values == nil {
+			values = []string{}
+		}
+	}
+	result := Completion{Values: values}
+	if len(values) > MAX_COMPLETION_VALUES {
+		result = Completion{Values: values[:MAX_COMPLETION_VALUES], Total: len(values), HasMore: true}
+	}
+	return jsonrpc.JSONRPCResponse{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Result:  CompleteResult{Completion: result},
+	}, nil
+}
@@ -15,6 +15,7 @@
 package v20241105
 
 import (
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
 	"github.com/googleapis/genai-toolbox/internal/tools"
 )
@@ -27,10 +28,18 @@ const PROTOCOL_VERSION = "2024-11-05"
 
 // methods that are supported.
 const (
-	TOOLS_LIST = "tools/list"
-	TOOLS_CALL = "tools/call"
+	TOOLS_LIST          = "tools/list"
+	TOOLS_CALL          = "tools/call"
+	RESOURCES_LIST      = "resources/list"
+	RESOURCES_READ      = "resources/read"
+	PROMPTS_LIST        = "prompts/list"
+	PROMPTS_GET         = "prompts/get"
+	COMPLETION_COMPLETE = "completion/complete"
 )
 
+// RESOURCE_NOT_FOUND is the error code returned when a requested resource does not exist.
+const RESOURCE_NOT_FOUND = -32002
+
 /* Empty result */
 
 // EmptyResult represents a response that indicates success but carries no data.
@@ -79,6 +88,104 @@ type CallToolRequest struct {
 	} `json:"params,omitempty"`
 }
 
+/* Resources */
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []Resource `json:"resources"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The URI of the resource to read. The URI can use any protocol; it is
+		// up to the server how to interpret it.
+		URI string `json:"uri"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	jsonrpc.Result
+	Contents []TextResourceContents `json:"contents"`
+}
+
+// A known resource that the server is capable of reading.
+type Resource struct {
+	Annotated
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// The contents of a specific resource or sub-resource.
+type ResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// TextResourceContents represents the text contents of a resource.
+type TextResourceContents struct {
+	ResourceContents
+	// The text of the item. This must only be set if the item can actually be
+	// represented as text (not binary data).
+	Text string `json:"text"`
+}
+
+/* Prompts */
+
+// Sent from the client to request a list of prompts and prompt templates the server has.
+type ListPromptsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	PaginatedResult
+	Prompts []prompts.McpManifest `json:"prompts"`
+}
+
+// Used by the client to get a prompt provided by the server.
+type GetPromptRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The name of the prompt or prompt template.
+		Name string `json:"name"`
+		// Arguments to use for templating the prompt.
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a prompts/get request from the client.
+type GetPromptResult struct {
+	jsonrpc.Result
+	// An optional description for the prompt.
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// Describes a message returned as part of a prompt.
+type PromptMessage struct {
+	Role Role `json:"role"`
+	// Could be either a TextContent, ImageContent, or EmbeddedResource
+	// For Toolbox, we will only be sending TextContent
+	Content TextContent `json:"content"`
+}
+
 // The sender or recipient of messages and data in a conversation.
 type Role string
 
@@ -87,6 +194,63 @@ const (
 	RoleAssistant Role = "assistant"
 )
 
+/* Completion */
+
+// Types of the references to the object whose argument is completed. The
+// tool reference is not part of the MCP specification, it is a Toolbox
+// extension that completes tool parameters.
+const (
+	REF_PROMPT   = "ref/prompt"
+	REF_RESOURCE = "ref/resource"
+	REF_TOOL     = "ref/tool"
+)
+
+// MAX_COMPLETION_VALUES is the maximum number of values of a completion.
+const MAX_COMPLETION_VALUES = 100
+
+// A request from the client to the server, to ask for completion options.
+type CompleteRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The prompt, resource or tool whose argument is completed.
+		Ref CompleteReference `json:"ref"`
+		// The argument's information.
+		Argument struct {
+			// The name of the argument.
+			Name string `json:"name"`
+			// The value of the argument to use for completion matching.
+			Value string `json:"value"`
+		} `json:"argument"`
+	} `json:"params"`
+}
+
+// A reference to a prompt, resource or tool.
+type CompleteReference struct {
+	Type string `json:"type"`
+	// The name of the prompt or tool.
+	Name string `json:"name,omitempty"`
+	// The URI or URI template of the resource.
+	URI string `json:"uri,omitempty"`
+}
+
+// The server's response to a completion/complete request.
+type CompleteResult struct {
+	jsonrpc.Result
+	Completion Completion `json:"completion"`
+}
+
+// Completion holds the values suggested for an argument.
+type Completion struct {
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+	// The total number of completion options available. This can exceed the
+	// number of values actually sent in the response.
+	Total int `json:"total,omitempty"`
+	// Indicates whether there are additional completion options beyond those
+	// provided in the current response, even if the exact total is unknown.
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
 // Base for objects that include optional annotations for the client.
 // The client can use annotations to inform how objects are used or displayed
 type Annotated struct {
@@ -0,0 +1,309 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v20250618
+
+import (
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// SERVER_NAME is the server name used in Implementation.
+const SERVER_NAME = "Toolbox"
+
+// PROTOCOL_VERSION is the version of the MCP protocol in this package.
+const PROTOCOL_VERSION = "2025-06-18"
+
+// methods that are supported.
+const (
+	TOOLS_LIST          = "tools/list"
+	TOOLS_CALL          = "tools/call"
+	RESOURCES_LIST      = "resources/list"
+	RESOURCES_READ      = "resources/read"
+	PROMPTS_LIST        = "prompts/list"
+	PROMPTS_GET         = "prompts/get"
+	COMPLETION_COMPLETE = "completion/complete"
+)
+
+// RESOURCE_NOT_FOUND is the error code returned when a requested resource does not exist.
+const RESOURCE_NOT_FOUND = -32002
+
+/* Empty result */
+
+// EmptyResult represents a response that indicates success but carries no data.
+type EmptyResult jsonrpc.Result
+
+/* Pagination */
+
+// Cursor is an opaque token used to represent a cursor for pagination.
+type Cursor string
+
+type PaginatedRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// An opaque token representing the current pagination position.
+		// If provided, the server should return results starting after this cursor.
+		Cursor Cursor `json:"cursor,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+type PaginatedResult struct {
+	jsonrpc.Result
+	// An opaque token representing the pagination position after the last returned result.
+	// If present, there may be more results available.
+	NextCursor Cursor `json:"nextCursor,omitempty"`
+}
+
+/* Tools */
+
+// Sent from the client to request a list of tools the server has.
+type ListToolsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a tools/list request from the client.
+type ListToolsResult struct {
+	PaginatedResult
+	Tools []tools.McpManifest `json:"tools"`
+}
+
+// Used by the client to invoke a tool provided by the server.
+type CallToolRequest struct {
+	jsonrpc.Request
+	Params struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+/* Resources */
+
+// Sent from the client to request a list of resources the server has.
+type ListResourcesRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a resources/list request from the client.
+type ListResourcesResult struct {
+	PaginatedResult
+	Resources []Resource `json:"resources"`
+}
+
+// Sent from the client to the server, to read a specific resource URI.
+type ReadResourceRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The URI of the resource to read. The URI can use any protocol; it is
+		// up to the server how to interpret it.
+		URI string `json:"uri"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a resources/read request from the client.
+type ReadResourceResult struct {
+	jsonrpc.Result
+	Contents []TextResourceContents `json:"contents"`
+}
+
+// A known resource that the server is capable of reading.
+type Resource struct {
+	Annotated
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// A human-readable name for this resource.
+	Name string `json:"name"`
+	// A description of what this resource represents.
+	Description string `json:"description,omitempty"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// The contents of a specific resource or sub-resource.
+type ResourceContents struct {
+	// The URI of this resource.
+	URI string `json:"uri"`
+	// The MIME type of this resource, if known.
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// TextResourceContents represents the text contents of a resource.
+type TextResourceContents struct {
+	ResourceContents
+	// The text of the item. This must only be set if the item can actually be
+	// represented as text (not binary data).
+	Text string `json:"text"`
+}
+
+/* Prompts */
+
+// Sent from the client to request a list of prompts and prompt templates the server has.
+type ListPromptsRequest struct {
+	PaginatedRequest
+}
+
+// The server's response to a prompts/list request from the client.
+type ListPromptsResult struct {
+	PaginatedResult
+	Prompts []prompts.McpManifest `json:"prompts"`
+}
+
+// Used by the client to get a prompt provided by the server.
+type GetPromptRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The name of the prompt or prompt template.
+		Name string `json:"name"`
+		// Arguments to use for templating the prompt.
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"params,omitempty"`
+}
+
+// The server's response to a prompts/get request from the client.
+type GetPromptResult struct {
+	jsonrpc.Result
+	// An optional description for the prompt.
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// Describes a message returned as part of a prompt.
+type PromptMessage struct {
+	Role Role `json:"role"`
+	// Could be either a TextContent, ImageContent, or EmbeddedResource
+	// For Toolbox, we will only be sending TextContent
+	Content TextContent `json:"content"`
+}
+
+// The sender or recipient of messages and data in a conversation.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+/* Completion */
+
+// Types of the references to the object whose argument is completed. The
+// tool reference is not part of the MCP specification, it is a Toolbox
+// extension that completes tool parameters.
+const (
+	REF_PROMPT   = "ref/prompt"
+	REF_RESOURCE = "ref/resource"
+	REF_TOOL     = "ref/tool"
+)
+
+// MAX_COMPLETION_VALUES is the maximum number of values of a completion.
+const MAX_COMPLETION_VALUES = 100
+
+// A request from the client to the server, to ask for completion options.
+type CompleteRequest struct {
+	jsonrpc.Request
+	Params struct {
+		// The prompt, resource or tool whose argument is completed.
+		Ref CompleteReference `json:"ref"`
+		// The argument's information.
+		Argument struct {
+			// The name of the argument.
+			Name string `json:"name"`
+			// The value of the argument to use for completion matching.
+			Value string `json:"value"`
+		} `json:"argument"`
+		// Additional, optional context for completions.
+		Context struct {
+			// Previously-resolved variables in a URI template or prompt.
+			Arguments map[string]string `json:"arguments,omitempty"`
+		} `json:"context,omitempty"`
+	} `json:"params"`
+}
+
+// A reference to a prompt, resource or tool.
+type CompleteReference struct {
+	Type string `json:"type"`
+	// The name of the prompt or tool.
+	Name string `json:"name,omitempty"`
+	// The URI or URI template of the resource.
+	URI string `json:"uri,omitempty"`
+}
+
+// The server's response to a completion/complete request.
+type CompleteResult struct {
+	jsonrpc.Result
+	Completion Completion `json:"completion"`
+}
+
+// Completion holds the values suggested for an argument.
+type Completion struct {
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+	// The total number of completion options available. This can exceed the
+	// number of values actually sent in the response.
+	Total int `json:"total,omitempty"`
+	// Indicates whether there are additional completion options beyond those
+	// provided in the current response, even if the exact total is unknown.
+	HasMore bool `json:"hasMore,omitempty"`
+}
+
+// Base for objects that include optional annotations for the client.
+// The client can use annotations to inform how objects are used or displayed
+type Annotated struct {
+	Annotations *struct {
+		// Describes who the intended customer of this object or data is.
+		// It can include multiple entries to indicate content useful for multiple
+		// audiences (e.g., `["user", "assistant"]`).
+		Audience []Role `json:"audience,omitempty"`
+		// Describes how important this data is for operating the server.
+		//
+		// A value of 1 means "most important," and indicates that the data is
+		// effectively required, while 0 means "least important," and indicates that
+		// the data is entirely optional.
+		//
+		// @TJS-type number
+		// @minimum 0
+		// @maximum 1
+		Priority float64 `json:"priority,omitempty"`
+	} `json:"annotations,omitempty"`
+}
+
+// TextContent represents text provided to or from an LLM.
+type TextContent struct {
+	Annotated
+	Type string `json:"type"`
+	// The text content of the message.
+	Text string `json:"text"`
+}
+
+// The server's response to a tool call.
+//
+// Any errors that originate from the tool SHOULD be reported inside the result
+// object, with `isError` set to true, _not_ as an MCP protocol-level error
+// response. Otherwise, the LLM would not be able to see that an error occurred
+// and self-correct.
+//
+// However, any errors in _finding_ the tool, an error indicating that the
+// server does not support tool calls, or any other exceptional conditions,
+// should be reported as an MCP error response.
+type CallToolResult struct {
+	jsonrpc.Result
+	// Could be either a TextContent, ImageContent, or EmbeddedResources
+	// For Toolbox, we will only be sending TextContent
+	Content []TextContent `json:"content"`
+	// An optional JSON object that represents the structured result of the
+	// tool call, it conforms to the outputSchema of the tool.
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
+	// Whether the tool call ended in an error.
+	// If not set, this is assumed to be false (the call was successful).
+	IsError bool `json:"isError,omitempty"`
+}
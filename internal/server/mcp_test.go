@@ -20,22 +20,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
+	"github.com/googleapis/genai-toolbox/internal/prompts/custom"
 	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"github.com/gorilla/websocket"
 )
 
 const jsonrpcVersion = "2.0"
 const protocolVersion20241105 = "2024-11-05"
 const protocolVersion20250326 = "2025-03-26"
+const protocolVersion20250618 = "2025-06-18"
 const serverName = "Toolbox"
 
 var tool1InputSchema = map[string]any{
@@ -182,7 +191,9 @@ func TestMcpEndpointWithoutInitialized(t *testing.T) {
 	}
 }
 
-func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion string, initializeWant map[string]any, idHeader bool) string {
+// runInitializeLifecycle initializes a session at the endpoint of path, and
+// returns the ID of the session if idHeader is set.
+func runInitializeLifecycle(t *testing.T, ts *httptest.Server, path, protocolVersion string, initializeWant map[string]any, idHeader bool) string {
 	initializeRequestBody := map[string]any{
 		"jsonrpc": jsonrpcVersion,
 		"id":      "mcp-initialize",
@@ -196,7 +207,7 @@ func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion s
 		t.Fatalf("unexpected error during marshaling of body")
 	}
 
-	resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+	resp, body, err := runRequest(ts, http.MethodPost, path, bytes.NewBuffer(reqMarshal), nil)
 	if err != nil {
 		t.Fatalf("unexpected error during request: %s", err)
 	}
@@ -232,7 +243,7 @@ func runInitializeLifecycle(t *testing.T, ts *httptest.Server, protocolVersion s
 		t.Fatalf("unexpected error during marshaling of notifications body")
 	}
 
-	_, _, err = runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(notiMarshal), header)
+	_, _, err = runRequest(ts, http.MethodPost, path, bytes.NewBuffer(notiMarshal), header)
 	if err != nil {
 		t.Fatalf("unexpected error during request: %s", err)
 	}
@@ -263,7 +274,8 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2024-11-05",
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"logging": map[string]any{},
+						"tools":   map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -279,7 +291,27 @@ func TestMcpEndpoint(t *testing.T) {
 				"result": map[string]any{
 					"protocolVersion": "2025-03-26",
 					"capabilities": map[string]any{
-						"tools": map[string]any{"listChanged": false},
+						"completions": map[string]any{},
+						"logging":     map[string]any{},
+						"tools":       map[string]any{"listChanged": true},
+					},
+					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			},
+		},
+		{
+			name:     "verson 2025-06-18",
+			protocol: protocolVersion20250618,
+			idHeader: true,
+			initWant: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": "2025-06-18",
+					"capabilities": map[string]any{
+						"completions": map[string]any{},
+						"logging":     map[string]any{},
+						"tools":       map[string]any{"listChanged": true},
 					},
 					"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
 				},
@@ -288,11 +320,10 @@ func TestMcpEndpoint(t *testing.T) {
 	}
 	for _, vtc := range versTestCases {
 		t.Run(vtc.name, func(t *testing.T) {
-			sessionId := runInitializeLifecycle(t, ts, vtc.protocol, vtc.initWant, vtc.idHeader)
-
-			header := map[string]string{}
-			if sessionId != "" {
-				header["Mcp-Session-Id"] = sessionId
+			// batch requests are rejected since 2025-06-18
+			emptyBatchMessage := "batch request is empty"
+			if vtc.protocol == protocolVersion20250618 {
+				emptyBatchMessage = "batch requests are not supported by protocol version 2025-06-18"
 			}
 
 			testCases := []struct {
@@ -387,6 +418,31 @@ func TestMcpEndpoint(t *testing.T) {
 						},
 					},
 				},
+				{
+					name:  "call tool not part of toolset",
+					url:   "/tool1_only",
+					isErr: true,
+					body: jsonrpc.JSONRPCRequest{
+						Jsonrpc: jsonrpcVersion,
+						Id:      "tools-call-outside-toolset",
+						Request: jsonrpc.Request{
+							Method: "tools/call",
+						},
+						Params: map[string]any{
+							"name":      "some_params",
+							"arguments": map[string]any{"param1": 1, "param2": 2},
+						},
+					},
+					want: map[string]any{
+						"jsonrpc": "2.0",
+						"id":      "tools-call-outside-toolset",
+						"error": map[string]any{
+							"code":    -32602.0,
+							"message": `invalid tool name: tool with name "some_params" is not part of toolset "tool1_only"`,
+							"data":    map[string]any{"tool": "some_params", "toolset": "tool1_only"},
+						},
+					},
+				},
 				{
 					name:  "missing method",
 					url:   "/",
@@ -446,34 +502,35 @@ func TestMcpEndpoint(t *testing.T) {
 					},
 				},
 				{
-					name:  "batch requests",
+					name:  "empty batch request",
 					url:   "/",
 					isErr: true,
-					body: []any{
-						jsonrpc.JSONRPCRequest{
-							Jsonrpc: "1.0",
-							Id:      "batch-requests1",
-							Request: jsonrpc.Request{
-								Method: "foo",
-							},
-						},
-						jsonrpc.JSONRPCRequest{
-							Jsonrpc: jsonrpcVersion,
-							Id:      "batch-requests2",
-							Request: jsonrpc.Request{
-								Method: "tools/list",
-							},
-						},
-					},
+					body:  []any{},
 					want: map[string]any{
 						"jsonrpc": "2.0",
 						"error": map[string]any{
 							"code":    -32600.0,
-							"message": "not supporting batch requests",
+							"message": emptyBatchMessage,
 						},
 					},
 				},
 			}
+			// a session is only available at the endpoint of the toolset it
+			// was initialized with
+			headers := map[string]map[string]string{}
+			for _, tc := range testCases {
+				if _, ok := headers[tc.url]; ok {
+					continue
+				}
+				header := map[string]string{}
+				if sessionId := runInitializeLifecycle(t, ts, tc.url, vtc.protocol, vtc.initWant, vtc.idHeader); sessionId != "" {
+					header["Mcp-Session-Id"] = sessionId
+				}
+				if vtc.protocol == protocolVersion20250618 {
+					header["MCP-Protocol-Version"] = vtc.protocol
+				}
+				headers[tc.url] = header
+			}
 			for _, tc := range testCases {
 				t.Run(tc.name, func(t *testing.T) {
 					reqMarshal, err := json.Marshal(tc.body)
@@ -481,7 +538,8 @@ func TestMcpEndpoint(t *testing.T) {
 						t.Fatalf("unexpected error during marshaling of body")
 					}
 
-					if vtc.protocol == protocolVersion20250326 && len(header) == 0 {
+					header := headers[tc.url]
+					if vtc.protocol != protocolVersion20241105 && len(header) == 0 {
 						t.Fatalf("header is missing")
 					}
 
@@ -521,12 +579,41 @@ func TestDeleteEndpoint(t *testing.T) {
 	ts := runServer(r, false)
 	defer ts.Close()
 
-	resp, _, err := runRequest(ts, http.MethodDelete, "/", nil, nil)
-	if resp.Status != "200 OK" {
-		t.Fatalf("unexpected status: %s", resp.Status)
+	testCases := []struct {
+		name   string
+		header map[string]string
+		status string
+		want   string
+	}{
+		{
+			name:   "missing session",
+			status: "400 Bad Request",
+			want:   "missing `Mcp-Session-Id` header",
+		},
+		{
+			name:   "unknown session",
+			header: map[string]string{"Mcp-Session-Id": "unknown"},
+			status: "404 Not Found",
+			want:   `session "unknown" does not exist or has been terminated`,
+		},
 	}
-	if err != nil {
-		t.Fatalf("unexpected error during request: %s", err)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodDelete, "/", nil, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.Status != tc.status {
+				t.Fatalf("unexpected status: %s", resp.Status)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if got["error"] != tc.want {
+				t.Fatalf("unexpected error message: %s", got["error"])
+			}
+		})
 	}
 }
 
@@ -537,21 +624,206 @@ func TestGetEndpoint(t *testing.T) {
 	ts := runServer(r, false)
 	defer ts.Close()
 
-	resp, body, err := runRequest(ts, http.MethodGet, "/", nil, nil)
-	if resp.Status != "405 Method Not Allowed" {
+	testCases := []struct {
+		name   string
+		header map[string]string
+		status string
+		want   string
+	}{
+		{
+			name:   "missing session",
+			status: "400 Bad Request",
+			want:   "missing `Mcp-Session-Id` header",
+		},
+		{
+			name:   "unknown session",
+			header: map[string]string{"Mcp-Session-Id": "unknown"},
+			status: "404 Not Found",
+			want:   `session "unknown" does not exist or has been terminated`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodGet, "/", nil, tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.Status != tc.status {
+				t.Fatalf("unexpected status: %s", resp.Status)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if got["error"] != tc.want {
+				t.Fatalf("unexpected error message: %s", got["error"])
+			}
+		})
+	}
+}
+
+func TestStreamableHttpSession(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2025-03-26",
+			"capabilities": map[string]any{
+				"completions": map[string]any{},
+				"logging":     map[string]any{},
+				"tools":       map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	sessionId := runInitializeLifecycle(t, ts, "/", protocolVersion20250326, initWant, true)
+	header := map[string]string{"Mcp-Session-Id": sessionId}
+
+	// open the stream of server-initiated messages
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Mcp-Session-Id", sessionId)
+	req.Header.Set("Accept", "text/event-stream")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer stream.Body.Close()
+	if stream.Status != "200 OK" {
+		t.Fatalf("unexpected status: %s", stream.Status)
+	}
+	if contentType := stream.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content-type header: want %s, got %s", "text/event-stream", contentType)
+	}
+
+	// only a single stream is allowed per session
+	resp, _, err := runRequest(ts, http.MethodGet, "/", nil, header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.Status != "409 Conflict" {
 		t.Fatalf("unexpected status: %s", resp.Status)
 	}
-	var got map[string]any
-	if err := json.Unmarshal(body, &got); err != nil {
-		t.Fatalf("unexpected error unmarshalling body: %s", err)
+
+	// server-initiated messages are sent on the stream
+	session, ok := server.streamableManager.get(sessionId)
+	if !ok {
+		t.Fatalf("session %q does not exist", sessionId)
+	}
+	if !session.send(map[string]any{"jsonrpc": "2.0", "method": "notifications/tools/list_changed"}) {
+		t.Fatalf("unable to send message")
+	}
+	reader := bufio.NewReader(stream.Body)
+	wantEvent := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/tools/list_changed\"}\n"
+	if got := readSseEvent(t, reader); got != wantEvent {
+		t.Fatalf("unexpected event: got %q, want %q", got, wantEvent)
+	}
+
+	// tool calls are streamed to clients that accept it
+	reqMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params":  map[string]any{"name": "no_params"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), map[string]string{
+		"Mcp-Session-Id": sessionId,
+		"Accept":         "application/json, text/event-stream",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content-type header: want %s, got %s", "text/event-stream", contentType)
+	}
+	wantBody := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"tools-call\",\"result\":{\"content\":[{\"type\":\"text\",\"text\":\"\\\"no_params\\\"\"}]}}\n\n"
+	if string(body) != wantBody {
+		t.Fatalf("unexpected response: got %q, want %q", body, wantBody)
+	}
+
+	// the session can only be used with the protocol version it negotiated
+	resp, body, err = runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), map[string]string{
+		"Mcp-Session-Id":       sessionId,
+		"MCP-Protocol-Version": protocolVersion20250618,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.Status != "400 Bad Request" {
+		t.Fatalf("unexpected status: %s: %s", resp.Status, body)
+	}
+
+	// the session can only be used with the toolset it was initialized with
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		resp, body, err = runRequest(ts, method, "/tool1_only", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.Status != "404 Not Found" {
+			t.Fatalf("unexpected status of %s request: %s: %s", method, resp.Status, body)
+		}
+	}
+
+	// terminate the session
+	resp, _, err = runRequest(ts, http.MethodDelete, "/", nil, header)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.Status != "200 OK" {
+		t.Fatalf("unexpected status: %s", resp.Status)
 	}
-	want := "toolbox does not support streaming in streamable HTTP transport"
-	if got["error"] != want {
-		t.Fatalf("unexpected error message: %s", got["error"])
+	// the stream is closed when the session is terminated
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Fatalf("unexpected error reading stream: %s", err)
 	}
+
+	// a terminated session can no longer be used
+	reqMarshal, err = json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-list",
+		"method":  "tools/list",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, _, err = runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
 	if err != nil {
 		t.Fatalf("unexpected error during request: %s", err)
 	}
+	if resp.Status != "404 Not Found" {
+		t.Fatalf("unexpected status: %s", resp.Status)
+	}
+}
+
+// readSseEvent reads a single event from a sse stream
+func readSseEvent(t *testing.T, reader *bufio.Reader) string {
+	var event strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read event: %s", err)
+		}
+		if line == "\n" {
+			return event.String()
+		}
+		event.WriteString(line)
+	}
 }
 
 func TestSseEndpoint(t *testing.T) {
@@ -665,6 +937,115 @@ func runSseRequest(ts *httptest.Server, path string, proto string) (*http.Respon
 	return resp, nil
 }
 
+func TestMcpListChangedNotifications(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	var gotChange ResourceChange
+	unsubscribe := server.ResourceMgr.Subscribe(func(change ResourceChange) { gotChange = change })
+	defer unsubscribe()
+
+	// streamable HTTP session of the default toolset with an open stream
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2025-03-26",
+			"capabilities": map[string]any{
+				"completions": map[string]any{},
+				"logging":     map[string]any{},
+				"tools":       map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	sessionId := runInitializeLifecycle(t, ts, "/", protocolVersion20250326, initWant, true)
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+	req.Header.Set("Mcp-Session-Id", sessionId)
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unable to send request: %s", err)
+	}
+	defer stream.Body.Close()
+	streamReader := bufio.NewReader(stream.Body)
+
+	// streamable HTTP session of an unchanged toolset
+	unchanged := server.streamableManager.create(protocolVersion20250326, "tool1_only", server.logger)
+
+	// sse session of the tool2_only toolset
+	sse, err := runSseRequest(ts, "/tool2_only/sse", "")
+	if err != nil {
+		t.Fatalf("unable to run sse request: %s", err)
+	}
+	defer sse.Body.Close()
+	sseReader := bufio.NewReader(sse.Body)
+	if got := readSseEvent(t, sseReader); !strings.HasPrefix(got, "event: endpoint\n") {
+		t.Fatalf("unexpected event: %s", got)
+	}
+
+	// stdio sessions are only notified after initialization
+	var stdout, uninitializedStdout bytes.Buffer
+	stdio := NewStdioSession(server, strings.NewReader(""), &stdout)
+	stdio.protocol = protocolVersion20250326
+	uninitializedStdio := NewStdioSession(server, strings.NewReader(""), &uninitializedStdout)
+
+	// reload with changes to the default and tool2_only toolsets
+	newToolsets := make(map[string]tools.Toolset)
+	for name, l := range map[string][]string{
+		"":           {tool1.Name, tool2.Name},
+		"tool1_only": {tool1.Name},
+		"tool2_only": {tool3.Name},
+	} {
+		tc := tools.ToolsetConfig{Name: name, ToolNames: l}
+		m, err := tc.Initialize(fakeVersionString, toolsMap)
+		if err != nil {
+			t.Fatalf("unable to initialize toolset %q: %s", name, err)
+		}
+		newToolsets[name] = m
+	}
+	server.ResourceMgr.SetResources(nil, nil, toolsMap, newToolsets, nil, nil)
+
+	wantChange := ResourceChange{Toolsets: []string{"", "tool2_only"}}
+	if !reflect.DeepEqual(gotChange, wantChange) {
+		t.Fatalf("unexpected change: got %+v, want %+v", gotChange, wantChange)
+	}
+
+	wantNotification := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed","params":{}}`
+	wantEvent := fmt.Sprintf("event: message\ndata: %s\n", wantNotification)
+	if got := readSseEvent(t, streamReader); got != wantEvent {
+		t.Fatalf("unexpected streamable HTTP event: got %q, want %q", got, wantEvent)
+	}
+	if got := readSseEvent(t, sseReader); got != wantEvent {
+		t.Fatalf("unexpected sse event: got %q, want %q", got, wantEvent)
+	}
+	if len(unchanged.eventQueue) != 0 {
+		t.Fatalf("unexpected notification for unchanged toolset")
+	}
+
+	stdio.notifyListChanged(ctx, gotChange)
+	if got := stdout.String(); got != wantNotification+"\n" {
+		t.Fatalf("unexpected stdio notification: got %q, want %q", got, wantNotification+"\n")
+	}
+	uninitializedStdio.notifyListChanged(ctx, gotChange)
+	if got := uninitializedStdout.String(); got != "" {
+		t.Fatalf("unexpected stdio notification before initialization: %q", got)
+	}
+}
+
 func TestStdioSession(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -699,15 +1080,17 @@ func TestStdioSession(t *testing.T) {
 	}
 
 	sseManager := newSseManager(ctx)
+	streamableManager := newStreamableManager(ctx)
 
-	resourceManager := NewResourceManager(nil, nil, toolsMap, toolsets)
+	resourceManager := NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil)
 
 	server := &Server{
-		version:         fakeVersionString,
-		logger:          testLogger,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:           fakeVersionString,
+		logger:            testLogger,
+		instrumentation:   instrumentation,
+		sseManager:        sseManager,
+		streamableManager: streamableManager,
+		ResourceMgr:       resourceManager,
 	}
 
 	in := bufio.NewReader(pr)
@@ -744,3 +1127,1570 @@ func TestStdioSession(t *testing.T) {
 		t.Fatalf("unexpected read: got %s, want %s", read, want)
 	}
 }
+
+func TestMcpAuth(t *testing.T) {
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	authRequiredTool := MockTool{
+		Name:         "auth_required",
+		Params:       []tools.Parameter{},
+		AuthRequired: []string{authService.Name},
+	}
+	authParamTool := MockTool{
+		Name: "auth_param",
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAuth("user", "the user", []tools.ParamAuthService{{Name: authService.Name, Field: "sub"}}),
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{authRequiredTool, authParamTool})
+	authServices := map[string]auth.AuthService{authService.Name: authService}
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name   string
+		tool   string
+		header map[string]string
+		want   map[string]any
+	}{
+		{
+			name: "authRequired without token",
+			tool: authRequiredTool.Name,
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call",
+				"error": map[string]any{
+					"code":    -32600.0,
+					"message": "unauthorized Tool call: please make sure you specify the correct auth headers",
+				},
+			},
+		},
+		{
+			name:   "authRequired with invalid token",
+			tool:   authRequiredTool.Name,
+			header: map[string]string{"my-auth_token": "invalid-token"},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call",
+				"error": map[string]any{
+					"code":    -32600.0,
+					"message": "unauthorized Tool call: please make sure you specify the correct auth headers",
+				},
+			},
+		},
+		{
+			name:   "authRequired with valid token",
+			tool:   authRequiredTool.Name,
+			header: map[string]string{"my-auth_token": "valid-token"},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call",
+				"result": map[string]any{
+					"content": []any{map[string]any{"type": "text", "text": `"auth_required"`}},
+				},
+			},
+		},
+		{
+			name: "auth param without token",
+			tool: authParamTool.Name,
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `provided parameters were invalid: error parsing authenticated parameter "user": missing or invalid authentication header`,
+				},
+			},
+		},
+		{
+			name:   "auth param with valid token",
+			tool:   authParamTool.Name,
+			header: map[string]string{"my-auth_token": "valid-token"},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "tools-call",
+				"result": map[string]any{
+					"content": []any{map[string]any{"type": "text", "text": `"auth_param"`}},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "tools-call",
+				"method":  "tools/call",
+				"params":  map[string]any{"name": tc.tool, "arguments": map[string]any{}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+			_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	// stdio sessions present the token from the environment
+	t.Setenv(stdioAuthTokenEnv, "valid-token")
+	header := stdioAuthHeader(server)
+	if got := header.Get("my-auth_token"); got != "valid-token" {
+		t.Fatalf("unexpected stdio auth token header: got %q, want %q", got, "valid-token")
+	}
+	if got := header.Get("Authorization"); got != "Bearer valid-token" {
+		t.Fatalf("unexpected stdio authorization header: got %q, want %q", got, "Bearer valid-token")
+	}
+}
+
+func TestMcpBatchProtocolVersion(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	body := `[{"jsonrpc": "2.0", "id": "list", "method": "tools/list"}]`
+
+	// batches are accepted by the protocol versions before 2025-06-18
+	resp, got, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(body), map[string]string{"MCP-Protocol-Version": protocolVersion20250326})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var responses []map[string]any
+	if err := json.Unmarshal(got, &responses); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(responses) != 1 || responses[0]["id"] != "list" || responses[0]["result"] == nil {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, got)
+	}
+
+	// and rejected with a single error since 2025-06-18
+	_, got, err = runRequest(ts, http.MethodPost, "/", strings.NewReader(body), map[string]string{"MCP-Protocol-Version": protocolVersion20250618})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	var response map[string]any
+	if err := json.Unmarshal(got, &response); err != nil {
+		t.Fatalf("unexpected error unmarshalling body: %s: %s", err, got)
+	}
+	want := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      response["id"],
+		"error": map[string]any{
+			"code":    -32600.0,
+			"message": "batch requests are not supported by protocol version 2025-06-18",
+		},
+	}
+	if !reflect.DeepEqual(response, want) {
+		t.Fatalf("unexpected response: got %+v, want %+v", response, want)
+	}
+}
+
+func TestMcpBatch(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	server.batchConcurrency = 2
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		want       []map[string]any
+	}{
+		{
+			name: "requests and notifications",
+			body: `[
+				{"jsonrpc": "2.0", "id": "call-1", "method": "tools/call", "params": {"name": "no_params", "arguments": {}}},
+				{"jsonrpc": "2.0", "method": "notifications/initialized"},
+				{"jsonrpc": "2.0", "id": "call-2", "method": "tools/call", "params": {"name": "some_params", "arguments": {"param1": 1, "param2": 2}}},
+				{"jsonrpc": "1.0", "id": "invalid-version", "method": "tools/list"}
+			]`,
+			wantStatus: http.StatusOK,
+			want: []map[string]any{
+				{
+					"jsonrpc": "2.0",
+					"id":      "call-1",
+					"result": map[string]any{
+						"content": []any{map[string]any{"type": "text", "text": `"no_params"`}},
+					},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      "call-2",
+					"result": map[string]any{
+						"content": []any{map[string]any{"type": "text", "text": `"some_params"`}},
+					},
+				},
+				{
+					"jsonrpc": "2.0",
+					"id":      "invalid-version",
+					"error": map[string]any{
+						"code":    -32600.0,
+						"message": "invalid json-rpc version",
+					},
+				},
+			},
+		},
+		{
+			name: "initialize and nested batch",
+			body: `[
+				{"jsonrpc": "2.0", "id": "init", "method": "initialize", "params": {"protocolVersion": "2024-11-05"}},
+				[{"jsonrpc": "2.0", "id": "nested", "method": "tools/list"}]
+			]`,
+			wantStatus: http.StatusOK,
+			want: []map[string]any{
+				{
+					"jsonrpc": "2.0",
+					"id":      "init",
+					"error": map[string]any{
+						"code":    -32600.0,
+						"message": "initialize request must not be part of a batch",
+					},
+				},
+				{
+					"jsonrpc": "2.0",
+					"error": map[string]any{
+						"code":    -32600.0,
+						"message": "nested batch requests are not supported",
+					},
+				},
+			},
+		},
+		{
+			name:       "only notifications",
+			body:       `[{"jsonrpc": "2.0", "method": "notifications/initialized"}]`,
+			wantStatus: http.StatusAccepted,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(tc.body), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+			// Notifications don't expect a response.
+			if tc.want == nil {
+				return
+			}
+			var got []map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("unexpected number of responses: got %d, want %d", len(got), len(tc.want))
+			}
+			for i := range got {
+				// for decode failure, a random uuid is generated in server
+				if tc.want[i]["id"] == nil {
+					tc.want[i]["id"] = got[i]["id"]
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	invoked := make(chan struct{})
+	cancelled := make(chan error, 1)
+	slowTool := MockTool{
+		Name:   "slow",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			util.ReportProgress(ctx, 1, 2, "started")
+			close(invoked)
+			<-ctx.Done()
+			cancelled <- context.Cause(ctx)
+			return nil, ctx.Err()
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{slowTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+
+	inReader, inWriter := io.Pipe()
+	defer inWriter.Close()
+	outReader, outWriter := io.Pipe()
+	go func() {
+		_ = NewStdioSession(server, inReader, outWriter).Start(util.WithLogger(ctx, server.logger))
+	}()
+	out := bufio.NewReader(outReader)
+	send := func(message string) {
+		if _, err := fmt.Fprintln(inWriter, message); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+	read := func() string {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read message: %s", err)
+		}
+		return strings.TrimSpace(line)
+	}
+
+	send(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	_ = read()
+
+	// the tool reports its progress to the client
+	send(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "slow", "arguments": {}, "_meta": {"progressToken": "progress-1"}}}`)
+	wantProgress := `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"progress-1","progress":1,"total":2,"message":"started"}}`
+	if got := read(); got != wantProgress {
+		t.Fatalf("unexpected progress notification: got %s, want %s", got, wantProgress)
+	}
+
+	// the client cancels the in-flight tool call
+	<-invoked
+	send(`{"jsonrpc": "2.0", "method": "notifications/cancelled", "params": {"requestId": 2, "reason": "no longer needed"}}`)
+	select {
+	case cause := <-cancelled:
+		if cause != errRequestCancelled {
+			t.Fatalf("unexpected cancellation cause: got %v, want %v", cause, errRequestCancelled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("tool call was not cancelled")
+	}
+
+	// the cancelled request has no response
+	send(`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "no_params", "arguments": {}}}`)
+	want := `{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"\"no_params\""}]}}`
+	if got := read(); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+}
+
+func TestMcpStreamedProgress(t *testing.T) {
+	progressTool := MockTool{
+		Name:   "progress",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			util.ReportProgress(ctx, 1, 0, "halfway")
+			return []any{"done"}, nil
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{progressTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2025-03-26",
+			"capabilities": map[string]any{
+				"completions": map[string]any{},
+				"logging":     map[string]any{},
+				"tools":       map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	sessionId := runInitializeLifecycle(t, ts, "/", protocolVersion20250326, initWant, true)
+
+	// progress notifications are sent on the stream of the response
+	reqMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-call",
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":  "progress",
+			"_meta": map[string]any{"progressToken": 7},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), map[string]string{
+		"Mcp-Session-Id": sessionId,
+		"Accept":         "application/json, text/event-stream",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	wantBody := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progressToken\":7,\"progress\":1,\"message\":\"halfway\"}}\n\n" +
+		"event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"tools-call\",\"result\":{\"content\":[{\"type\":\"text\",\"text\":\"\\\"done\\\"\"}]}}\n\n"
+	if string(body) != wantBody {
+		t.Fatalf("unexpected response: got %q, want %q", body, wantBody)
+	}
+}
+
+func TestMcpLogging(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	loggingTool := MockTool{
+		Name:   "logging",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			logger, err := util.LoggerFromContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+			logger.DebugContext(ctx, "query started")
+			logger.WarnContext(ctx, "slow query", "rows", 10)
+			return []any{"done"}, nil
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{loggingTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+
+	inReader, inWriter := io.Pipe()
+	defer inWriter.Close()
+	outReader, outWriter := io.Pipe()
+	go func() {
+		_ = NewStdioSession(server, inReader, outWriter).Start(util.WithLogger(ctx, server.logger))
+	}()
+	out := bufio.NewReader(outReader)
+	send := func(message string) {
+		if _, err := fmt.Fprintln(inWriter, message); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+	read := func() string {
+		line, err := out.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read message: %s", err)
+		}
+		return strings.TrimSpace(line)
+	}
+
+	send(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-06-18"}}`)
+	_ = read()
+
+	// no log messages are sent until the client sets a level
+	send(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "logging", "arguments": {}}}`)
+	want := `{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"\"done\""}]}}`
+	if got := read(); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+
+	send(`{"jsonrpc": "2.0", "id": 3, "method": "logging/setLevel", "params": {"level": "warning"}}`)
+	want = `{"jsonrpc":"2.0","id":3,"result":{}}`
+	if got := read(); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+
+	// log messages of the level or higher are sent before the response
+	send(`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "logging", "arguments": {}}}`)
+	wantMessage := `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"warning","logger":"Toolbox","data":{"message":"slow query","rows":10}}}`
+	if got := read(); got != wantMessage {
+		t.Fatalf("unexpected log message: got %s, want %s", got, wantMessage)
+	}
+	want = `{"jsonrpc":"2.0","id":4,"result":{"content":[{"type":"text","text":"\"done\""}]}}`
+	if got := read(); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+
+	send(`{"jsonrpc": "2.0", "id": 5, "method": "logging/setLevel", "params": {"level": "verbose"}}`)
+	want = `{"jsonrpc":"2.0","id":5,"error":{"code":-32602,"message":"invalid logging level: \"verbose\""}}`
+	if got := read(); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+
+	// logging is not available without a session
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+	reqBody := `{"jsonrpc": "2.0", "id": 6, "method": "logging/setLevel", "params": {"level": "debug"}}`
+	resp, body, err := runRequest(ts, http.MethodPost, "/", strings.NewReader(reqBody), nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	want = `{"jsonrpc":"2.0","id":6,"error":{"code":-32600,"message":"logging requires a session"}}`
+	if got := strings.TrimSpace(string(body)); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+}
+
+func TestMcpCompletion(t *testing.T) {
+	listDatasets := MockTool{
+		Name:   "list_datasets",
+		Params: []tools.Parameter{tools.NewStringParameterWithDefault("project", "", "the project")},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			return []any{
+				map[string]any{"dataset_id": "sales"},
+				map[string]any{"dataset_id": "marketing"},
+				map[string]any{"dataset_id": "sales_eu"},
+			}, nil
+		},
+	}
+	dataset := tools.NewStringParameter("dataset", "the dataset")
+	dataset.CompletionSource = &tools.CompletionSource{Tool: "list_datasets", Column: "dataset_id"}
+	query := MockTool{
+		Name:   "query",
+		Params: []tools.Parameter{dataset, tools.NewStringParameter("table", "the table")},
+	}
+	authQuery := MockTool{
+		Name:         "auth_query",
+		AuthRequired: []string{"my-auth"},
+		Params:       []tools.Parameter{dataset},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{query, listDatasets, authQuery})
+
+	promptCfg := custom.Config{
+		Name:        "dataset_report",
+		Kind:        "custom",
+		Description: "Summarize a dataset",
+		Messages:    []prompts.Message{{Content: "Summarize {{.dataset}}."}},
+		Arguments:   tools.Parameters{dataset},
+	}
+	prompt, err := promptCfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+	promptsMap := map[string]prompts.Prompt{"dataset_report": prompt}
+	promptset, err := prompts.PromptsetConfig{Name: "", PromptNames: []string{"dataset_report"}}.Initialize(promptsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize promptset: %s", err)
+	}
+	// the completion source of the prompt is not part of the tool1_only toolset
+	promptsets := map[string]prompts.Promptset{"": promptset, "tool1_only": promptset}
+
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, promptsMap, promptsets))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	testCases := []struct {
+		name string
+		url  string
+		ref  map[string]any
+		arg  map[string]any
+		want map[string]any
+	}{
+		{
+			name: "tool parameter with completion source",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/tool", "name": "query"},
+			arg:  map[string]any{"name": "dataset", "value": "sa"},
+			want: map[string]any{"completion": map[string]any{"values": []any{"sales", "sales_eu"}}},
+		},
+		{
+			name: "prompt argument with completion source",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/prompt", "name": "dataset_report"},
+			arg:  map[string]any{"name": "dataset", "value": "m"},
+			want: map[string]any{"completion": map[string]any{"values": []any{"marketing"}}},
+		},
+		{
+			name: "parameter without completion",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/tool", "name": "query"},
+			arg:  map[string]any{"name": "table", "value": "or"},
+			want: map[string]any{"completion": map[string]any{"values": []any{}}},
+		},
+		{
+			name: "resource",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/resource", "uri": "file:///data"},
+			arg:  map[string]any{"name": "path", "value": ""},
+			want: map[string]any{"completion": map[string]any{"values": []any{}}},
+		},
+	}
+	errorCases := []struct {
+		name     string
+		url      string
+		ref      map[string]any
+		arg      map[string]any
+		wantCode int
+		want     string
+	}{
+		{
+			name: "unknown tool",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/tool", "name": "unknown"},
+			arg:  map[string]any{"name": "dataset", "value": ""},
+			want: `invalid tool name: tool with name "unknown" does not exist`,
+		},
+		{
+			name: "tool outside of the toolset",
+			url:  "/tool2_only",
+			ref:  map[string]any{"type": "ref/tool", "name": "query"},
+			arg:  map[string]any{"name": "dataset", "value": ""},
+			want: `invalid tool name: tool with name "query" is not part of toolset "tool2_only"`,
+		},
+		{
+			name:     "tool without the tokens of its auth services",
+			url:      "/",
+			ref:      map[string]any{"type": "ref/tool", "name": "auth_query"},
+			arg:      map[string]any{"name": "dataset", "value": ""},
+			wantCode: jsonrpc.INVALID_REQUEST,
+			want:     tools.ErrToolUnauthorized.Error(),
+		},
+		{
+			name:     "completion source outside of the toolset",
+			url:      "/tool1_only",
+			ref:      map[string]any{"type": "ref/prompt", "name": "dataset_report"},
+			arg:      map[string]any{"name": "dataset", "value": ""},
+			wantCode: jsonrpc.INVALID_REQUEST,
+			want:     `unable to use completion source tool "list_datasets": invalid tool name: tool with name "list_datasets" is not part of toolset "tool1_only"`,
+		},
+		{
+			name: "invalid argument",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/tool", "name": "query"},
+			arg:  map[string]any{"name": "region", "value": ""},
+			want: `invalid argument name: "region"`,
+		},
+		{
+			name: "invalid reference type",
+			url:  "/",
+			ref:  map[string]any{"type": "ref/unknown", "name": "query"},
+			arg:  map[string]any{"name": "dataset", "value": ""},
+			want: `invalid reference type: "ref/unknown"`,
+		},
+	}
+	complete := func(url, protocolVersion string, ref, arg map[string]any) map[string]any {
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "completion-complete",
+			"method":  "completion/complete",
+			"params":  map[string]any{"ref": ref, "argument": arg},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		header := map[string]string{"MCP-Protocol-Version": protocolVersion}
+		_, body, err := runRequest(ts, http.MethodPost, url, bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+	for _, protocolVersion := range []string{protocolVersion20241105, protocolVersion20250326, protocolVersion20250618} {
+		for _, tc := range testCases {
+			t.Run(protocolVersion+" "+tc.name, func(t *testing.T) {
+				got := complete(tc.url, protocolVersion, tc.ref, tc.arg)
+				if !reflect.DeepEqual(got["result"], tc.want) {
+					t.Fatalf("unexpected result: got %+v, want %+v", got, tc.want)
+				}
+			})
+		}
+		for _, tc := range errorCases {
+			t.Run(protocolVersion+" "+tc.name, func(t *testing.T) {
+				got := complete(tc.url, protocolVersion, tc.ref, tc.arg)
+				code := jsonrpc.INVALID_PARAMS
+				if tc.wantCode != 0 {
+					code = tc.wantCode
+				}
+				want := map[string]any{"code": float64(code), "message": tc.want}
+				if !reflect.DeepEqual(got["error"], want) {
+					t.Fatalf("unexpected error: got %+v, want %+v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestMcpWebSocket(t *testing.T) {
+	progressTool := MockTool{
+		Name:   "progress",
+		Params: []tools.Parameter{},
+		InvokeFunc: func(ctx context.Context) ([]any, error) {
+			util.ReportProgress(ctx, 1, 2, "halfway")
+			return []any{"done"}, nil
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{progressTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	dial := func(path string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+path, nil)
+		if err != nil {
+			t.Fatalf("unable to dial websocket: %s", err)
+		}
+		return conn
+	}
+	send := func(conn *websocket.Conn, message string) {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			t.Fatalf("unable to write message: %s", err)
+		}
+	}
+	read := func(conn *websocket.Conn) string {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("unable to read message: %s", err)
+		}
+		return string(message)
+	}
+
+	conn := dial("/ws")
+	defer conn.Close()
+	send(conn, `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-06-18"}}`)
+	var initRes map[string]any
+	if err := json.Unmarshal([]byte(read(conn)), &initRes); err != nil {
+		t.Fatalf("unable to unmarshal initialize response: %s", err)
+	}
+	if got := initRes["result"].(map[string]any)["protocolVersion"]; got != protocolVersion20250618 {
+		t.Fatalf("unexpected protocol version: got %v, want %s", got, protocolVersion20250618)
+	}
+
+	// progress notifications are sent before the response
+	send(conn, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "progress", "arguments": {}, "_meta": {"progressToken": "progress-1"}}}`)
+	wantProgress := `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progressToken":"progress-1","progress":1,"total":2,"message":"halfway"}}`
+	if got := read(conn); got != wantProgress {
+		t.Fatalf("unexpected progress notification: got %s, want %s", got, wantProgress)
+	}
+	want := `{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"\"done\""}]}}`
+	if got := read(conn); got != want {
+		t.Fatalf("unexpected response: got %s, want %s", got, want)
+	}
+
+	// sessions of a toolset only list its tools
+	toolsetConn := dial("/tool2_only/ws")
+	defer toolsetConn.Close()
+	send(toolsetConn, `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-06-18"}}`)
+	_ = read(toolsetConn)
+	send(toolsetConn, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "progress", "arguments": {}}}`)
+	wantErr := `"invalid tool name: tool with name \"progress\" is not part of toolset \"tool2_only\""`
+	if got := read(toolsetConn); !strings.Contains(got, wantErr) {
+		t.Fatalf("unexpected response: got %s, want error %s", got, wantErr)
+	}
+
+	// sessions are notified when the tools of their toolset change
+	newToolsets := make(map[string]tools.Toolset)
+	for name, l := range map[string][]string{
+		"":           {progressTool.Name},
+		"tool1_only": {progressTool.Name},
+		"tool2_only": {tool1.Name},
+	} {
+		tc := tools.ToolsetConfig{Name: name, ToolNames: l}
+		m, err := tc.Initialize(fakeVersionString, toolsMap)
+		if err != nil {
+			t.Fatalf("unable to initialize toolset %q: %s", name, err)
+		}
+		newToolsets[name] = m
+	}
+	server.ResourceMgr.SetResources(nil, nil, toolsMap, newToolsets, nil, nil)
+	wantNotification := `{"jsonrpc":"2.0","method":"notifications/tools/list_changed","params":{}}`
+	if got := read(conn); got != wantNotification {
+		t.Fatalf("unexpected notification: got %s, want %s", got, wantNotification)
+	}
+
+	// the session answers pings of the client
+	pong := make(chan struct{})
+	conn.SetPongHandler(func(string) error {
+		close(pong)
+		return nil
+	})
+	if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("unable to ping: %s", err)
+	}
+	// control messages are handled while reading
+	go func() { _, _, _ = conn.ReadMessage() }()
+	select {
+	case <-pong:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no pong received")
+	}
+}
+
+func TestMcpInitializeInstructions(t *testing.T) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2})
+	tc := tools.ToolsetConfig{
+		Name:         "guided",
+		ToolNames:    []string{tool1.Name},
+		Description:  "A guided toolset.",
+		Instructions: "Call no_params first.",
+	}
+	toolset, err := tc.Initialize(fakeVersionString, toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	toolsets[tc.Name] = toolset
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	for path, want := range map[string]string{
+		"/guided": "Call no_params first.",
+		"/":       "",
+	} {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05"}}`
+		_, res, err := runRequest(ts, http.MethodPost, path, strings.NewReader(body), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got struct {
+			Result struct {
+				Instructions *string `json:"instructions"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal(res, &got); err != nil {
+			t.Fatalf("unable to unmarshal response: %s", err)
+		}
+		// the instructions are omitted if the toolset has none
+		if (want == "") != (got.Result.Instructions == nil) || (want != "" && *got.Result.Instructions != want) {
+			t.Fatalf("unexpected instructions for %q: got %s, want %q", path, res, want)
+		}
+	}
+}
+
+func TestMcpToolsListPagination(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	server.toolsPageSize = 2
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	listTools := func(cursor string) map[string]any {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "tools-list",
+			"method":  "tools/list",
+			"params":  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+	toolNames := func(res map[string]any) []string {
+		var names []string
+		result, _ := res["result"].(map[string]any)
+		list, _ := result["tools"].([]any)
+		for _, tool := range list {
+			names = append(names, tool.(map[string]any)["name"].(string))
+		}
+		return names
+	}
+
+	// the tools are listed in pages, in the order of the toolset
+	first := listTools("")
+	if got, want := toolNames(first), []string{tool1.Name, tool2.Name}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tools on the first page: got %v, want %v", got, want)
+	}
+	cursor, _ := first["result"].(map[string]any)["nextCursor"].(string)
+	if cursor == "" {
+		t.Fatalf("expected a next cursor: %+v", first)
+	}
+	second := listTools(cursor)
+	if got, want := toolNames(second), []string{tool3.Name}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tools on the second page: got %v, want %v", got, want)
+	}
+	if next, ok := second["result"].(map[string]any)["nextCursor"]; ok {
+		t.Fatalf("unexpected next cursor on the last page: %v", next)
+	}
+
+	// invalid cursors are rejected
+	want := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "tools-list",
+		"error": map[string]any{
+			"code":    -32602.0,
+			"message": `invalid cursor "invalid"`,
+		},
+	}
+	if got := listTools("invalid"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected response: got %+v, want %+v", got, want)
+	}
+}
+
+func TestMcpResources(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	sourcesMap := map[string]sources.Source{
+		"my-source":    &MockSource{Name: "my-source", Tables: map[string]string{"users": "integer"}},
+		"other-source": &MockSource{Name: "other-source", Tables: map[string]string{"orders": "integer"}},
+	}
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	authServices := map[string]auth.AuthService{authService.Name: authService}
+	// the default toolset publishes my-source, tool1_only no source, and
+	// tool2_only publishes other-source to the requests of some_user
+	toolset := toolsets[""]
+	toolset.SourceNames = []string{"my-source"}
+	toolsets[""] = toolset
+	policies := tools.AuthPolicies{{AuthService: authService.Name, Claim: "sub", Equals: "some_user"}}
+	if err := policies.Initialize(); err != nil {
+		t.Fatalf("unable to initialize auth policies: %s", err)
+	}
+	toolset = toolsets["tool2_only"]
+	toolset.SourceNames = []string{"other-source"}
+	toolset.AuthPolicies = policies
+	toolsets["tool2_only"] = toolset
+	server, shutdown := newTestServer(t, NewResourceManager(sourcesMap, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2024-11-05",
+			"capabilities": map[string]any{
+				"logging":   map[string]any{},
+				"tools":     map[string]any{"listChanged": true},
+				"resources": map[string]any{"subscribe": false, "listChanged": false},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	runInitializeLifecycle(t, ts, "/", protocolVersion20241105, initWant, false)
+
+	testCases := []struct {
+		name   string
+		url    string
+		header map[string]string
+		body   map[string]any
+		want   map[string]any
+	}{
+		{
+			name: "resources/list",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-list",
+				"method":  "resources/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-list",
+				"result": map[string]any{
+					"resources": []any{
+						map[string]any{
+							"uri":         "toolbox://sources/my-source/tables/users",
+							"name":        "users",
+							"description": `Schema of table "users" in mock source "my-source".`,
+							"mimeType":    "application/json",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/list of toolset without sources",
+			url:  "/tool1_only",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-list",
+				"method":  "resources/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-list",
+				"result":  map[string]any{"resources": []any{}},
+			},
+		},
+		{
+			name: "resources/list not allowed by the auth policies of the toolset",
+			url:  "/tool2_only",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-list",
+				"method":  "resources/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-list",
+				"result":  map[string]any{"resources": []any{}},
+			},
+		},
+		{
+			name:   "resources/list allowed by the auth policies of the toolset",
+			url:    "/tool2_only",
+			header: map[string]string{"my-auth_token": "valid-token"},
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-list",
+				"method":  "resources/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-list",
+				"result": map[string]any{
+					"resources": []any{
+						map[string]any{
+							"uri":         "toolbox://sources/other-source/tables/orders",
+							"name":        "orders",
+							"description": `Schema of table "orders" in mock source "other-source".`,
+							"mimeType":    "application/json",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/read",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-read",
+				"method":  "resources/read",
+				"params":  map[string]any{"uri": "toolbox://sources/my-source/tables/users"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read",
+				"result": map[string]any{
+					"contents": []any{
+						map[string]any{
+							"uri":      "toolbox://sources/my-source/tables/users",
+							"mimeType": "application/json",
+							"text":     `{"source":"my-source","name":"users","columns":[{"name":"id","dataType":"integer","nullable":false}]}`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resources/read source outside of the toolset",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-read-outside",
+				"method":  "resources/read",
+				"params":  map[string]any{"uri": "toolbox://sources/other-source/tables/orders"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-outside",
+				"error": map[string]any{
+					"code":    -32002.0,
+					"message": `resource not found: source "other-source" does not provide resources`,
+					"data":    map[string]any{"uri": "toolbox://sources/other-source/tables/orders"},
+				},
+			},
+		},
+		{
+			name: "resources/read not allowed by the auth policies of the toolset",
+			url:  "/tool2_only",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-read-denied",
+				"method":  "resources/read",
+				"params":  map[string]any{"uri": "toolbox://sources/other-source/tables/orders"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-denied",
+				"error": map[string]any{
+					"code":    -32002.0,
+					"message": `resource not found: source "other-source" does not provide resources`,
+					"data":    map[string]any{"uri": "toolbox://sources/other-source/tables/orders"},
+				},
+			},
+		},
+		{
+			name: "resources/read unknown source",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-read-unknown",
+				"method":  "resources/read",
+				"params":  map[string]any{"uri": "toolbox://sources/foo/tables/users"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-unknown",
+				"error": map[string]any{
+					"code":    -32002.0,
+					"message": `resource not found: source "foo" does not provide resources`,
+					"data":    map[string]any{"uri": "toolbox://sources/foo/tables/users"},
+				},
+			},
+		},
+		{
+			name: "resources/read invalid uri",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "resources-read-invalid",
+				"method":  "resources/read",
+				"params":  map[string]any{"uri": "file:///etc/passwd"},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "resources-read-invalid",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `invalid resource uri "file:///etc/passwd": must start with "toolbox://sources/"`,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+
+			_, body, err := runRequest(ts, http.MethodPost, tc.url, bytes.NewBuffer(reqMarshal), tc.header)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpPrompts(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2, tool3}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+
+	promptCfg := custom.Config{
+		Name:        "sales_report",
+		Kind:        "custom",
+		Description: "Summarize the sales of a country",
+		Messages:    []prompts.Message{{Content: "Summarize the sales in {{.country}}."}},
+		Arguments:   tools.Parameters{tools.NewStringParameter("country", "the country to report on")},
+	}
+	prompt, err := promptCfg.Initialize()
+	if err != nil {
+		t.Fatalf("unable to initialize prompt: %s", err)
+	}
+	promptsMap := map[string]prompts.Prompt{"sales_report": prompt}
+	promptsets := make(map[string]prompts.Promptset)
+	for name, l := range map[string][]string{
+		"":           {"sales_report"},
+		"tool1_only": {},
+	} {
+		ps, err := prompts.PromptsetConfig{Name: name, PromptNames: l}.Initialize(promptsMap)
+		if err != nil {
+			t.Fatalf("unable to initialize promptset %q: %s", name, err)
+		}
+		promptsets[name] = ps
+	}
+
+	server, shutdown := newTestServer(t, NewResourceManager(nil, nil, toolsMap, toolsets, promptsMap, promptsets))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	initWant := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "mcp-initialize",
+		"result": map[string]any{
+			"protocolVersion": "2024-11-05",
+			"capabilities": map[string]any{
+				"logging": map[string]any{},
+				"tools":   map[string]any{"listChanged": true},
+				"prompts": map[string]any{"listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": serverName, "version": fakeVersionString},
+		},
+	}
+	runInitializeLifecycle(t, ts, "/", protocolVersion20241105, initWant, false)
+
+	testCases := []struct {
+		name string
+		url  string
+		body map[string]any
+		want map[string]any
+	}{
+		{
+			name: "prompts/list",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "prompts-list",
+				"method":  "prompts/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-list",
+				"result": map[string]any{
+					"prompts": []any{
+						map[string]any{
+							"name":        "sales_report",
+							"description": "Summarize the sales of a country",
+							"arguments": []any{
+								map[string]any{
+									"name":        "country",
+									"description": "the country to report on",
+									"required":    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prompts/list on toolset without prompts",
+			url:  "/tool1_only",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "prompts-list-toolset",
+				"method":  "prompts/list",
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-list-toolset",
+				"result": map[string]any{
+					"prompts": []any{},
+				},
+			},
+		},
+		{
+			name: "prompts/get",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "prompts-get",
+				"method":  "prompts/get",
+				"params": map[string]any{
+					"name":      "sales_report",
+					"arguments": map[string]any{"country": "France"},
+				},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get",
+				"result": map[string]any{
+					"description": "Summarize the sales of a country",
+					"messages": []any{
+						map[string]any{
+							"role": "user",
+							"content": map[string]any{
+								"type": "text",
+								"text": "Summarize the sales in France.",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "prompts/get out of toolset",
+			url:  "/tool1_only",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "prompts-get-toolset",
+				"method":  "prompts/get",
+				"params": map[string]any{
+					"name":      "sales_report",
+					"arguments": map[string]any{"country": "France"},
+				},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get-toolset",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `invalid prompt name: prompt with name "sales_report" does not exist`,
+				},
+			},
+		},
+		{
+			name: "prompts/get missing argument",
+			url:  "/",
+			body: map[string]any{
+				"jsonrpc": jsonrpcVersion,
+				"id":      "prompts-get-missing",
+				"method":  "prompts/get",
+				"params": map[string]any{
+					"name": "sales_report",
+				},
+			},
+			want: map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "prompts-get-missing",
+				"error": map[string]any{
+					"code":    -32602.0,
+					"message": `provided arguments were invalid: parameter "country" is required`,
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqMarshal, err := json.Marshal(tc.body)
+			if err != nil {
+				t.Fatalf("unexpected error during marshaling of body")
+			}
+
+			_, body, err := runRequest(ts, http.MethodPost, tc.url, bytes.NewBuffer(reqMarshal), nil)
+			if err != nil {
+				t.Fatalf("unexpected error during request: %s", err)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("unexpected error unmarshalling body: %s", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("unexpected response: got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMcpStructuredOutput(t *testing.T) {
+	readOnly := true
+	tool4 := MockTool{
+		Name:         "structured_output",
+		Title:        "Structured Output",
+		Description:  "some description",
+		Params:       []tools.Parameter{},
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  &tools.ToolAnnotations{ReadOnlyHint: &readOnly},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool4, tool1})
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	inputSchema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{},
+		"required":   []any{},
+	}
+	outputSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"result": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type":        "object",
+					"description": "A row, mapping the column names to their values.",
+				},
+			},
+		},
+		"required": []any{"result"},
+	}
+	content := []any{map[string]any{"type": "text", "text": `"structured_output"`}}
+
+	testCases := []struct {
+		name     string
+		protocol string
+		listWant map[string]any
+		callWant map[string]any
+	}{
+		{
+			name:     "version 2024-11-05",
+			protocol: protocolVersion20241105,
+			listWant: map[string]any{
+				"name":        "structured_output",
+				"description": "some description",
+				"inputSchema": inputSchema,
+			},
+			callWant: map[string]any{"content": content},
+		},
+		{
+			name:     "version 2025-03-26",
+			protocol: protocolVersion20250326,
+			listWant: map[string]any{
+				"name":        "structured_output",
+				"description": "some description",
+				"inputSchema": inputSchema,
+				"annotations": map[string]any{"readOnlyHint": true},
+			},
+			callWant: map[string]any{"content": content},
+		},
+		{
+			name:     "version 2025-06-18",
+			protocol: protocolVersion20250618,
+			listWant: map[string]any{
+				"name":         "structured_output",
+				"title":        "Structured Output",
+				"description":  "some description",
+				"inputSchema":  inputSchema,
+				"outputSchema": outputSchema,
+				"annotations":  map[string]any{"readOnlyHint": true},
+			},
+			callWant: map[string]any{
+				"content":           content,
+				"structuredContent": map[string]any{"result": []any{"structured_output"}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capabilities := map[string]any{
+				"logging": map[string]any{},
+				"tools":   map[string]any{"listChanged": true},
+			}
+			if tc.protocol != protocolVersion20241105 {
+				capabilities["completions"] = map[string]any{}
+			}
+			initWant := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      "mcp-initialize",
+				"result": map[string]any{
+					"protocolVersion": tc.protocol,
+					"capabilities":    capabilities,
+					"serverInfo":      map[string]any{"name": serverName, "version": fakeVersionString},
+				},
+			}
+			sessionId := runInitializeLifecycle(t, ts, "/tool1_only", tc.protocol, initWant, tc.protocol != protocolVersion20241105)
+			header := map[string]string{}
+			if sessionId != "" {
+				header["Mcp-Session-Id"] = sessionId
+			}
+			if tc.protocol == protocolVersion20250618 {
+				header["MCP-Protocol-Version"] = tc.protocol
+			}
+
+			requests := []struct {
+				body map[string]any
+				want map[string]any
+			}{
+				{
+					body: map[string]any{
+						"jsonrpc": jsonrpcVersion,
+						"id":      "tools-list",
+						"method":  "tools/list",
+					},
+					want: map[string]any{
+						"jsonrpc": "2.0",
+						"id":      "tools-list",
+						"result": map[string]any{
+							"tools": []any{tc.listWant},
+						},
+					},
+				},
+				{
+					body: map[string]any{
+						"jsonrpc": jsonrpcVersion,
+						"id":      "tools-call",
+						"method":  "tools/call",
+						"params":  map[string]any{"name": "structured_output"},
+					},
+					want: map[string]any{
+						"jsonrpc": "2.0",
+						"id":      "tools-call",
+						"result":  tc.callWant,
+					},
+				},
+			}
+			for _, req := range requests {
+				reqMarshal, err := json.Marshal(req.body)
+				if err != nil {
+					t.Fatalf("unexpected error during marshaling of body")
+				}
+				_, body, err := runRequest(ts, http.MethodPost, "/tool1_only", bytes.NewBuffer(reqMarshal), header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				var got map[string]any
+				if err := json.Unmarshal(body, &got); err != nil {
+					t.Fatalf("unexpected error unmarshalling body: %s", err)
+				}
+				if !reflect.DeepEqual(got, req.want) {
+					t.Fatalf("unexpected response: got %+v, want %+v", got, req.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMcpUnsupportedProtocolHeader(t *testing.T) {
+	mockTools := []MockTool{tool1, tool2}
+	toolsMap, toolsets := setUpResources(t, mockTools)
+	r, shutdown := setUpServer(t, "mcp", toolsMap, toolsets)
+	defer shutdown()
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	reqMarshal, err := json.Marshal(map[string]any{
+		"jsonrpc": jsonrpcVersion,
+		"id":      "tools-list",
+		"method":  "tools/list",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error during marshaling of body")
+	}
+	resp, _, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), map[string]string{"MCP-Protocol-Version": "1999-01-01"})
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unexpected status code: got %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
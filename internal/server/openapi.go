@@ -0,0 +1,395 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// openAPIVersion is the version of the OpenAPI specification of the documents.
+const openAPIVersion = "3.1.0"
+
+// Names of the schemas of the responses of the tool endpoints.
+const (
+	openAPIResultSchema = "ResultResponse"
+	openAPIErrorSchema  = "ErrorResponse"
+	openAPIJobSchema    = "Job"
+	openAPIBatchSchema  = "BatchResponse"
+)
+
+// openAPIMaxSecurityRequirements caps the combinations of auth services that
+// are listed as the security requirements of an operation.
+const openAPIMaxSecurityRequirements = 16
+
+// openAPIDocument is an OpenAPI document describing the invoke, jobs and batch
+// endpoints of the tools of a toolset.
+type openAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       openAPIInfo                `json:"info"`
+	Paths      map[string]openAPIPathItem `json:"paths"`
+	Components openAPIComponents          `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIPathItem struct {
+	Post *openAPIOperation `json:"post,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationId string                     `json:"operationId"`
+	Description string                     `json:"description,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	// Security lists the alternative sets of auth services, the tokens of
+	// all the auth services of one set are required.
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Properties  map[string]*openAPISchema `json:"properties,omitempty"`
+	Items       *openAPISchema            `json:"items,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// openAPIHandler handles the request for the OpenAPI document of a toolset.
+// The document is built from the current resources, so that it follows the
+// dynamic reloads of the tools file.
+func openAPIHandler(s *Server, w http.ResponseWriter, r *http.Request) {
+	ctx, span := s.instrumentation.Tracer.Start(r.Context(), "toolbox/server/openapi/get")
+	r = r.WithContext(ctx)
+
+	toolsetName := chi.URLParam(r, "toolsetName")
+	span.SetAttributes(attribute.String("toolset_name", toolsetName))
+	var err error
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	toolset, ok := s.ResourceMgr.GetToolset(toolsetName)
+	if !ok {
+		err = fmt.Errorf("toolset %q does not exist", toolsetName)
+		s.logger.DebugContext(ctx, err.Error())
+		_ = render.Render(w, r, newErrResponse(err, http.StatusNotFound))
+		return
+	}
+	render.JSON(w, r, newOpenAPIDocument(s, toolsetName, toolset))
+}
+
+// newOpenAPIDocument returns the OpenAPI document of a toolset, with an
+// invoke operation per tool. The tools of a named toolset are invoked through
+// the toolset-scoped routes.
+func newOpenAPIDocument(s *Server, toolsetName string, toolset tools.Toolset) openAPIDocument {
+	doc := openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:       "Toolbox",
+			Version:     s.version,
+			Description: toolset.Manifest.Description,
+		},
+		Paths: make(map[string]openAPIPathItem),
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{
+				openAPIResultSchema: {
+					Type:        "object",
+					Description: "The results of a tool invocation.",
+					Properties: map[string]*openAPISchema{
+						"result": {Type: "string", Description: "The results of the tool, encoded as JSON."},
+					},
+					Required: []string{"result"},
+				},
+				openAPIErrorSchema: {
+					Type:        "object",
+					Description: "The error of a failed request.",
+					Properties: map[string]*openAPISchema{
+						"status": {Type: "string", Description: "The status text of the HTTP status code."},
+						"error":  {Type: "string", Description: "The error message."},
+					},
+					Required: []string{"status"},
+				},
+				openAPIJobSchema: {
+					Type:        "object",
+					Description: "A job invoking a tool in the background.",
+					Properties: map[string]*openAPISchema{
+						"id":        {Type: "string", Description: "The ID of the job."},
+						"tool":      {Type: "string", Description: "The name of the tool."},
+						"toolset":   {Type: "string", Description: "The name of the toolset of the tool."},
+						"status":    {Type: "string", Description: "The status of the job: running, succeeded, failed or cancelled."},
+						"result":    {Type: "string", Description: "The results of the tool once the job succeeded, encoded as JSON."},
+						"error":     {Type: "string", Description: "The error of the job once it failed."},
+						"createdAt": {Type: "string", Description: "The time at which the job was created."},
+						"updatedAt": {Type: "string", Description: "The time at which the job was last updated."},
+					},
+					Required: []string{"id", "tool", "status", "createdAt", "updatedAt"},
+				},
+				openAPIBatchSchema: {
+					Type:        "object",
+					Description: "The outcomes of the invocations of a batch, in the order of the request.",
+					Properties: map[string]*openAPISchema{
+						"results": {
+							Type: "array",
+							Items: &openAPISchema{
+								Type:        "object",
+								Description: "The results of a successful invocation, or the error of a failed one.",
+								Properties: map[string]*openAPISchema{
+									"result": {Type: "string", Description: "The results of the tool, encoded as JSON."},
+									"status": {Type: "string", Description: "The status text of the HTTP status code of the failure."},
+									"error":  {Type: "string", Description: "The error message."},
+								},
+							},
+						},
+					},
+					Required: []string{"results"},
+				},
+			},
+		},
+	}
+	if toolsetName != "" {
+		doc.Info.Title = fmt.Sprintf("Toolbox toolset %s", toolsetName)
+	}
+
+	authServices := s.ResourceMgr.GetAuthServiceMap()
+	for _, name := range toolset.ToolNames {
+		m := toolset.Manifest.ToolsManifest[name]
+		ops, schemes := openAPIToolOperations(name, m)
+		for _, scheme := range schemes {
+			if doc.Components.SecuritySchemes == nil {
+				doc.Components.SecuritySchemes = make(map[string]openAPISecurityScheme)
+			}
+			desc := fmt.Sprintf("Token verified by the auth service %q.", scheme)
+			header := scheme + "_token"
+			if a, ok := authServices[scheme]; ok {
+				desc = fmt.Sprintf("Token verified by the %q auth service %q.", a.AuthServiceKind(), scheme)
+				header = auth.TokenHeader(a)
+			}
+			securityScheme := openAPISecurityScheme{
+				Type:        "apiKey",
+				In:          "header",
+				Name:        header,
+				Description: desc,
+			}
+			switch {
+			case header == "":
+				// the auth service verifies client certificates
+				desc = fmt.Sprintf("Client certificate verified by the %q auth service %q.", authServices[scheme].AuthServiceKind(), scheme)
+				securityScheme = openAPISecurityScheme{Type: "mutualTLS", Description: desc}
+			case strings.EqualFold(header, "Authorization"):
+				// the Authorization header is not allowed for apiKey schemes
+				securityScheme = openAPISecurityScheme{Type: "http", Scheme: "bearer", Description: desc}
+			}
+			doc.Components.SecuritySchemes[scheme] = securityScheme
+		}
+		prefix := fmt.Sprintf("/api/tool/%s", name)
+		if toolsetName != "" {
+			prefix = fmt.Sprintf("/api/toolset/%s/tool/%s", toolsetName, name)
+		}
+		for suffix, op := range ops {
+			doc.Paths[prefix+"/"+suffix] = openAPIPathItem{Post: op}
+		}
+	}
+	return doc
+}
+
+// openAPIToolOperations returns the invoke, jobs and batch operations of a
+// tool keyed by the suffix of their path, and the auth services that they
+// refer to. Authenticated parameters are left out of the request bodies, since
+// their values are taken from the claims of the tokens.
+func openAPIToolOperations(name string, m tools.Manifest) (map[string]*openAPIOperation, []string) {
+	body := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	// each group lists the auth services of which one token is required
+	groups := make([][]string, 0)
+	if len(m.AuthRequired) > 0 {
+		groups = append(groups, m.AuthRequired)
+	}
+	for _, p := range m.Parameters {
+		if len(p.AuthServices) > 0 {
+			groups = append(groups, p.AuthServices)
+			continue
+		}
+		body.Properties[p.Name] = openAPIParameterSchema(p)
+		if p.Required {
+			body.Required = append(body.Required, p.Name)
+		}
+	}
+	security, schemes := openAPISecurity(groups)
+
+	jsonContent := func(schema *openAPISchema) map[string]openAPIMediaType {
+		return map[string]openAPIMediaType{"application/json": {Schema: schema}}
+	}
+	ref := func(name string) *openAPISchema {
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	errResponse := func(desc string) openAPIResponse {
+		return openAPIResponse{Description: desc, Content: jsonContent(ref(openAPIErrorSchema))}
+	}
+	// responses returns the responses of an operation, with the failures of
+	// the checks shared by the endpoints of a tool
+	responses := func(others map[string]openAPIResponse) map[string]openAPIResponse {
+		responses := map[string]openAPIResponse{
+			"401": errResponse("The request is not authorized to invoke the tool."),
+			"403": errResponse("The claims of the tokens are not allowed by the auth policies of the tool or toolset."),
+			"404": errResponse("The tool does not exist."),
+		}
+		maps.Copy(responses, others)
+		return responses
+	}
+	operation := func(id, desc string, reqBody *openAPISchema, resp map[string]openAPIResponse) *openAPIOperation {
+		return &openAPIOperation{
+			OperationId: id,
+			Description: desc,
+			RequestBody: &openAPIRequestBody{Required: true, Content: jsonContent(reqBody)},
+			Responses:   responses(resp),
+			Security:    security,
+		}
+	}
+
+	ops := map[string]*openAPIOperation{
+		"invoke": operation(name, m.Description, body, map[string]openAPIResponse{
+			"200": {Description: "The tool was invoked successfully.", Content: jsonContent(ref(openAPIResultSchema))},
+			"400": errResponse("The parameters are invalid, or the invocation failed."),
+			"500": errResponse("The results could not be returned."),
+		}),
+		"jobs": operation(name+"_job", fmt.Sprintf("Invokes the tool %q in a job.", name), body, map[string]openAPIResponse{
+			"202": {Description: "The job was created.", Content: jsonContent(ref(openAPIJobSchema))},
+			"400": errResponse("The parameters are invalid."),
+			"429": errResponse("The maximum number of stored or running jobs is reached."),
+			"500": errResponse("The job could not be created."),
+		}),
+		"invoke:batch": operation(name+"_batch", fmt.Sprintf("Invokes the tool %q with each of the parameters of an array.", name), &openAPISchema{Type: "array", Items: body}, map[string]openAPIResponse{
+			"200": {Description: "The outcome of each invocation.", Content: jsonContent(ref(openAPIBatchSchema))},
+			"400": errResponse("The body is invalid, or the batch is empty or has more invocations than the maximum."),
+		}),
+	}
+	return ops, schemes
+}
+
+// openAPISecurity returns the security requirements of an operation that
+// requires a token of one auth service of each group, and the auth services
+// that they refer to. The requirements are the combinations of one auth
+// service of each group. Since their number grows with the product of the
+// sizes of the groups, past openAPIMaxSecurityRequirements each auth service
+// is listed as a requirement of its own instead, which tells the tokens that
+// the tool accepts without listing the combinations that it requires.
+func openAPISecurity(groups [][]string) ([]map[string][]string, []string) {
+	var schemes []string
+	for _, group := range groups {
+		for _, a := range group {
+			if !slices.Contains(schemes, a) {
+				schemes = append(schemes, a)
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return nil, schemes
+	}
+
+	combinations := 1
+	for _, group := range groups {
+		combinations *= len(group)
+		if combinations > openAPIMaxSecurityRequirements {
+			security := make([]map[string][]string, 0, len(schemes))
+			for _, a := range schemes {
+				security = append(security, map[string][]string{a: {}})
+			}
+			return security, schemes
+		}
+	}
+
+	requirements := [][]string{{}}
+	for _, group := range groups {
+		next := make([][]string, 0, len(requirements)*len(group))
+		for _, r := range requirements {
+			for _, a := range group {
+				req := slices.Clone(r)
+				if !slices.Contains(req, a) {
+					req = append(req, a)
+				}
+				next = append(next, req)
+			}
+		}
+		requirements = next
+	}
+	security := make([]map[string][]string, 0, len(requirements))
+	for _, r := range requirements {
+		req := make(map[string][]string, len(r))
+		for _, a := range r {
+			req[a] = []string{}
+		}
+		security = append(security, req)
+	}
+	return security, schemes
+}
+
+// openAPIParameterSchema returns the JSON Schema of a parameter.
+func openAPIParameterSchema(p tools.ParameterManifest) *openAPISchema {
+	schema := &openAPISchema{Type: p.Type, Description: p.Description}
+	switch p.Type {
+	case "float":
+		schema.Type = "number"
+	case "array":
+		if p.Items != nil {
+			schema.Items = openAPIParameterSchema(*p.Items)
+		}
+	}
+	return schema
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	authTool := MockTool{
+		Name:         "auth_tool",
+		Description:  "some description",
+		AuthRequired: []string{"my-auth", "other-auth"},
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAuth("user", "the user", []tools.ParamAuthService{{Name: "user-auth", Field: "sub"}}),
+			tools.NewFloatParameter("amount", "the amount"),
+		},
+	}
+	// the combinations of the auth services of the parameters are not listed
+	manyAuthTool := MockTool{
+		Name: "many_auth_tool",
+		Params: tools.Parameters{
+			tools.NewStringParameterWithAuth("a", "a", []tools.ParamAuthService{{Name: "a1", Field: "sub"}, {Name: "a2", Field: "sub"}, {Name: "a3", Field: "sub"}}),
+			tools.NewStringParameterWithAuth("b", "b", []tools.ParamAuthService{{Name: "b1", Field: "sub"}, {Name: "b2", Field: "sub"}, {Name: "b3", Field: "sub"}}),
+			tools.NewStringParameterWithAuth("c", "c", []tools.ParamAuthService{{Name: "a1", Field: "sub"}, {Name: "c2", Field: "sub"}}),
+		},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool3, authTool, manyAuthTool})
+	authServices := map[string]auth.AuthService{
+		"my-auth":    MockAuthService{Name: "my-auth", Token: "token"},
+		"other-auth": clientcert.AuthService{Name: "other-auth", Kind: clientcert.AuthServiceKind},
+	}
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	getDoc := func(path string) map[string]any {
+		resp, body, err := runRequest(ts, http.MethodGet, path, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		var doc map[string]any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			t.Fatalf("unable to parse OpenAPI document: %s", err)
+		}
+		return doc
+	}
+
+	doc := getDoc("/openapi.json")
+	if doc["openapi"] != "3.1.0" {
+		t.Fatalf("unexpected openapi version: %v", doc["openapi"])
+	}
+	paths := doc["paths"].(map[string]any)
+	if len(paths) != 9 {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+
+	// authenticated parameters are left out of the request body
+	op := paths["/api/tool/auth_tool/invoke"].(map[string]any)["post"].(map[string]any)
+	wantBody := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"amount": map[string]any{"type": "number", "description": "the amount"},
+		},
+		"required": []any{"amount"},
+	}
+	gotBody := op["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+	if diff := cmp.Diff(wantBody, gotBody); diff != "" {
+		t.Fatalf("unexpected request body (-want +got):\n%s", diff)
+	}
+	// one of the required auth services, and the auth service of the
+	// parameter, are required
+	wantSecurity := []any{
+		map[string]any{"my-auth": []any{}, "user-auth": []any{}},
+		map[string]any{"other-auth": []any{}, "user-auth": []any{}},
+	}
+	if diff := cmp.Diff(wantSecurity, op["security"]); diff != "" {
+		t.Fatalf("unexpected security (-want +got):\n%s", diff)
+	}
+	schemes := doc["components"].(map[string]any)["securitySchemes"].(map[string]any)
+	wantScheme := map[string]any{
+		"type":        "apiKey",
+		"in":          "header",
+		"name":        "my-auth_token",
+		"description": `Token verified by the "mock" auth service "my-auth".`,
+	}
+	if diff := cmp.Diff(wantScheme, schemes["my-auth"]); diff != "" {
+		t.Fatalf("unexpected security scheme (-want +got):\n%s", diff)
+	}
+	// auth services that verify client certificates use mutual TLS
+	wantScheme = map[string]any{
+		"type":        "mutualTLS",
+		"description": `Client certificate verified by the "client-cert" auth service "other-auth".`,
+	}
+	if diff := cmp.Diff(wantScheme, schemes["other-auth"]); diff != "" {
+		t.Fatalf("unexpected security scheme (-want +got):\n%s", diff)
+	}
+	if len(schemes) != 10 {
+		t.Fatalf("unexpected security schemes: %v", schemes)
+	}
+
+	// past the maximum number of combinations, each auth service is listed
+	// on its own
+	op = paths["/api/tool/many_auth_tool/invoke"].(map[string]any)["post"].(map[string]any)
+	wantSecurity = []any{
+		map[string]any{"a1": []any{}},
+		map[string]any{"a2": []any{}},
+		map[string]any{"a3": []any{}},
+		map[string]any{"b1": []any{}},
+		map[string]any{"b2": []any{}},
+		map[string]any{"b3": []any{}},
+		map[string]any{"c2": []any{}},
+	}
+	if diff := cmp.Diff(wantSecurity, op["security"]); diff != "" {
+		t.Fatalf("unexpected security (-want +got):\n%s", diff)
+	}
+
+	// the jobs and batch endpoints are described with their own statuses
+	responseCodes := func(op map[string]any) []string {
+		codes := make([]string, 0)
+		for code := range op["responses"].(map[string]any) {
+			codes = append(codes, code)
+		}
+		slices.Sort(codes)
+		return codes
+	}
+	for path, want := range map[string][]string{
+		"/api/tool/auth_tool/invoke":       {"200", "400", "401", "403", "404", "500"},
+		"/api/tool/auth_tool/jobs":         {"202", "400", "401", "403", "404", "429", "500"},
+		"/api/tool/auth_tool/invoke:batch": {"200", "400", "401", "403", "404"},
+	} {
+		op := paths[path].(map[string]any)["post"].(map[string]any)
+		if diff := cmp.Diff(want, responseCodes(op)); diff != "" {
+			t.Fatalf("unexpected responses of %s (-want +got):\n%s", path, diff)
+		}
+		if diff := cmp.Diff(map[string]any{"my-auth": []any{}, "user-auth": []any{}}, op["security"].([]any)[0]); diff != "" {
+			t.Fatalf("unexpected security of %s (-want +got):\n%s", path, diff)
+		}
+	}
+	op = paths["/api/tool/auth_tool/invoke:batch"].(map[string]any)["post"].(map[string]any)
+	gotBody = op["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+	if diff := cmp.Diff(map[string]any{"type": "array", "items": wantBody}, gotBody); diff != "" {
+		t.Fatalf("unexpected batch request body (-want +got):\n%s", diff)
+	}
+
+	// tools without auth are public
+	op = paths["/api/tool/array_param/invoke"].(map[string]any)["post"].(map[string]any)
+	if _, ok := op["security"]; ok {
+		t.Fatalf("unexpected security for a public tool: %v", op["security"])
+	}
+	if got := op["responses"].(map[string]any)["400"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]; !cmp.Equal(got, map[string]any{"$ref": "#/components/schemas/ErrorResponse"}) {
+		t.Fatalf("unexpected error response schema: %v", got)
+	}
+
+	// toolset documents use the toolset-scoped routes
+	doc = getDoc("/toolset/tool1_only/openapi.json")
+	paths = doc["paths"].(map[string]any)
+	if _, ok := paths["/api/toolset/tool1_only/tool/array_param/invoke"]; !ok || len(paths) != 3 {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+
+	// the document follows the reloads of the tools
+	server.ResourceMgr.SetResources(nil, authServices, toolsMap, map[string]tools.Toolset{"": toolsets["tool2_only"]}, nil, nil)
+	paths = getDoc("/openapi.json")["paths"].(map[string]any)
+	if _, ok := paths["/api/tool/auth_tool/invoke"]; !ok || len(paths) != 3 {
+		t.Fatalf("unexpected paths after reload: %v", paths)
+	}
+
+	resp, _, err := runRequest(ts, http.MethodGet, "/toolset/unknown/openapi.json", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error during request: %s", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// setUpPolicyResources setups resources whose tool no_params is only allowed
+// for the user of my-auth, and whose toolset tool2_only requires a token of
+// my-auth.
+func setUpPolicyResources(t *testing.T) (map[string]tools.Tool, map[string]tools.Toolset, map[string]auth.AuthService) {
+	toolsMap, toolsets := setUpResources(t, []MockTool{tool1, tool2, tool3})
+	toolPolicies := tools.AuthPolicies{{AuthService: "my-auth", Claim: "sub", Equals: "some_user"}}
+	exists := true
+	toolsetPolicies := tools.AuthPolicies{{AuthService: "my-auth", Claim: "sub", Exists: &exists}}
+	for name, toolset := range toolsets {
+		toolset.ToolAuthPolicies = map[string]tools.AuthPolicies{tool1.Name: toolPolicies}
+		if name == "tool2_only" {
+			toolset.AuthPolicies = toolsetPolicies
+		}
+		toolsets[name] = toolset
+	}
+	authService := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	return toolsMap, toolsets, map[string]auth.AuthService{authService.Name: authService}
+}
+
+func TestAuthPoliciesAPI(t *testing.T) {
+	toolsMap, toolsets, authServices := setUpPolicyResources(t)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	validHeader := map[string]string{"my-auth_token": "valid-token"}
+	t.Run("toolset manifests", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			path   string
+			header map[string]string
+			want   []string
+		}{
+			{
+				name: "without token",
+				path: "/toolset",
+				want: []string{tool3.Name, tool2.Name},
+			},
+			{
+				name:   "with token",
+				path:   "/toolset",
+				header: validHeader,
+				want:   []string{tool3.Name, tool1.Name, tool2.Name},
+			},
+			{
+				name: "toolset without token",
+				path: "/toolset/tool2_only",
+				want: []string{},
+			},
+			{
+				name:   "toolset with token",
+				path:   "/toolset/tool2_only",
+				header: validHeader,
+				want:   []string{tool2.Name},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				resp, body, err := runRequest(ts, http.MethodGet, tc.path, nil, tc.header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("unexpected status code: %d: %s", resp.StatusCode, body)
+				}
+				var m tools.ToolsetManifest
+				if err := json.Unmarshal(body, &m); err != nil {
+					t.Fatalf("unable to parse toolset manifest: %s", err)
+				}
+				got := make([]string, 0, len(m.ToolsManifest))
+				for name := range m.ToolsManifest {
+					got = append(got, name)
+				}
+				slices.Sort(got)
+				if !slices.Equal(got, tc.want) {
+					t.Fatalf("unexpected tools: got %v, want %v", got, tc.want)
+				}
+			})
+		}
+	})
+
+	t.Run("tools", func(t *testing.T) {
+		testCases := []struct {
+			name       string
+			method     string
+			path       string
+			body       string
+			header     map[string]string
+			wantStatus int
+		}{
+			{
+				name:       "get without token",
+				method:     http.MethodGet,
+				path:       "/tool/no_params",
+				wantStatus: http.StatusForbidden,
+			},
+			{
+				name:       "get with token",
+				method:     http.MethodGet,
+				path:       "/tool/no_params",
+				header:     validHeader,
+				wantStatus: http.StatusOK,
+			},
+			{
+				name:       "invoke without token",
+				method:     http.MethodPost,
+				path:       "/tool/no_params/invoke",
+				body:       `{}`,
+				wantStatus: http.StatusForbidden,
+			},
+			{
+				name:       "invoke with token",
+				method:     http.MethodPost,
+				path:       "/tool/no_params/invoke",
+				body:       `{}`,
+				header:     validHeader,
+				wantStatus: http.StatusOK,
+			},
+			{
+				name:       "invoke without policies",
+				method:     http.MethodPost,
+				path:       "/tool/array_param/invoke",
+				body:       `{"my_array": ["a"]}`,
+				wantStatus: http.StatusOK,
+			},
+			{
+				name:       "invoke through toolset without token",
+				method:     http.MethodPost,
+				path:       "/toolset/tool2_only/tool/some_params/invoke",
+				body:       `{"param1": 1, "param2": 2}`,
+				wantStatus: http.StatusForbidden,
+			},
+			{
+				name:       "invoke through toolset with token",
+				method:     http.MethodPost,
+				path:       "/toolset/tool2_only/tool/some_params/invoke",
+				body:       `{"param1": 1, "param2": 2}`,
+				header:     validHeader,
+				wantStatus: http.StatusOK,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				resp, body, err := runRequest(ts, tc.method, tc.path, strings.NewReader(tc.body), tc.header)
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				if resp.StatusCode != tc.wantStatus {
+					t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+				}
+				if tc.wantStatus == http.StatusForbidden && !strings.Contains(string(body), "not allowed by the auth policies") {
+					t.Fatalf("unexpected error: %s", body)
+				}
+			})
+		}
+	})
+}
+
+func TestAuthPoliciesMCP(t *testing.T) {
+	toolsMap, toolsets, authServices := setUpPolicyResources(t)
+	server, shutdown := newTestServer(t, NewResourceManager(nil, authServices, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := mcpRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize mcp router: %s", err)
+	}
+	ts := runServer(r, false)
+	defer ts.Close()
+
+	request := func(t *testing.T, method string, params map[string]any, header map[string]string) map[string]any {
+		reqMarshal, err := json.Marshal(map[string]any{
+			"jsonrpc": jsonrpcVersion,
+			"id":      "policies",
+			"method":  method,
+			"params":  params,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error during marshaling of body")
+		}
+		_, body, err := runRequest(ts, http.MethodPost, "/", bytes.NewBuffer(reqMarshal), header)
+		if err != nil {
+			t.Fatalf("unexpected error during request: %s", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatalf("unexpected error unmarshalling body: %s", err)
+		}
+		return got
+	}
+	validHeader := map[string]string{"my-auth_token": "valid-token"}
+
+	t.Run("tools/list", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			header map[string]string
+			want   []string
+		}{
+			{name: "without token", want: []string{tool2.Name, tool3.Name}},
+			{name: "with token", header: validHeader, want: []string{tool1.Name, tool2.Name, tool3.Name}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				got := request(t, "tools/list", map[string]any{}, tc.header)
+				result, ok := got["result"].(map[string]any)
+				if !ok {
+					t.Fatalf("unexpected response: %+v", got)
+				}
+				var names []string
+				for _, tool := range result["tools"].([]any) {
+					names = append(names, tool.(map[string]any)["name"].(string))
+				}
+				if !slices.Equal(names, tc.want) {
+					t.Fatalf("unexpected tools: got %v, want %v", names, tc.want)
+				}
+			})
+		}
+	})
+
+	t.Run("tools/call", func(t *testing.T) {
+		params := map[string]any{"name": tool1.Name, "arguments": map[string]any{}}
+		got := request(t, "tools/call", params, nil)
+		wantErr := map[string]any{
+			"code":    -32600.0,
+			"message": `tool "no_params" is not allowed by the auth policies for the verified claims`,
+		}
+		if gotErr, _ := got["error"].(map[string]any); gotErr == nil || gotErr["code"] != wantErr["code"] || gotErr["message"] != wantErr["message"] {
+			t.Fatalf("unexpected response: got %+v, want error %+v", got, wantErr)
+		}
+
+		got = request(t, "tools/call", params, validHeader)
+		if _, ok := got["result"]; !ok {
+			t.Fatalf("unexpected response: %+v", got)
+		}
+	})
+}
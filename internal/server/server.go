@@ -16,10 +16,14 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -29,6 +33,7 @@ import (
 	"github.com/go-chi/httplog/v2"
 	"github.com/googleapis/genai-toolbox/internal/auth"
 	"github.com/googleapis/genai-toolbox/internal/log"
+	"github.com/googleapis/genai-toolbox/internal/prompts"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -39,14 +44,23 @@ import (
 
 // Server contains info for running an instance of Toolbox. Should be instantiated with NewServer().
 type Server struct {
-	version         string
-	srv             *http.Server
-	listener        net.Listener
-	root            chi.Router
-	logger          log.Logger
-	instrumentation *telemetry.Instrumentation
-	sseManager      *sseManager
-	ResourceMgr     *ResourceManager
+	version             string
+	srv                 *http.Server
+	listener            net.Listener
+	root                chi.Router
+	logger              log.Logger
+	instrumentation     *telemetry.Instrumentation
+	sseManager          *sseManager
+	streamableManager   *streamableManager
+	jobManager          *jobManager
+	readiness           readiness
+	batchConcurrency    int
+	apiBatchConcurrency int
+	apiBatchMaxSize     int
+	adminAuthServices   []string
+	toolsPageSize       int
+	tlsClientCAFile     string
+	ResourceMgr         *ResourceManager
 }
 
 // ResourceManager contains available resources for the server. Should be initialized with NewResourceManager().
@@ -56,12 +70,26 @@ type ResourceManager struct {
 	authServices map[string]auth.AuthService
 	tools        map[string]tools.Tool
 	toolsets     map[string]tools.Toolset
+	prompts      map[string]prompts.Prompt
+	promptsets   map[string]prompts.Promptset
+
+	subscribersMu  sync.Mutex
+	subscribers    map[int]func(ResourceChange)
+	nextSubscriber int
+}
+
+// ResourceChange describes the toolsets and promptsets whose contents changed
+// when the resources of a ResourceManager were replaced.
+type ResourceChange struct {
+	Toolsets   []string
+	Promptsets []string
 }
 
 func NewResourceManager(
 	sourcesMap map[string]sources.Source,
 	authServicesMap map[string]auth.AuthService,
 	toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset,
+	promptsMap map[string]prompts.Prompt, promptsetsMap map[string]prompts.Promptset,
 ) *ResourceManager {
 	resourceMgr := &ResourceManager{
 		mu:           sync.RWMutex{},
@@ -69,6 +97,9 @@ func NewResourceManager(
 		authServices: authServicesMap,
 		tools:        toolsMap,
 		toolsets:     toolsetsMap,
+		prompts:      promptsMap,
+		promptsets:   promptsetsMap,
+		subscribers:  make(map[int]func(ResourceChange)),
 	}
 
 	return resourceMgr
@@ -102,13 +133,138 @@ func (r *ResourceManager) GetToolset(toolsetName string) (tools.Toolset, bool) {
 	return toolset, ok
 }
 
-func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset) {
+func (r *ResourceManager) GetPrompt(promptName string) (prompts.Prompt, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	prompt, ok := r.prompts[promptName]
+	return prompt, ok
+}
+
+func (r *ResourceManager) GetPromptset(promptsetName string) (prompts.Promptset, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	promptset, ok := r.promptsets[promptsetName]
+	return promptset, ok
+}
+
+// SetResources replaces the resources, and closes the replaced sources. The
+// returned error is the failure to close them, the resources are replaced
+// regardless.
+func (r *ResourceManager) SetResources(sourcesMap map[string]sources.Source, authServicesMap map[string]auth.AuthService, toolsMap map[string]tools.Tool, toolsetsMap map[string]tools.Toolset, promptsMap map[string]prompts.Prompt, promptsetsMap map[string]prompts.Promptset) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	change := ResourceChange{
+		Toolsets:   changedToolsets(r.toolsets, toolsetsMap),
+		Promptsets: changedPromptsets(r.promptsets, promptsetsMap),
+	}
+	replaced := r.sources
 	r.sources = sourcesMap
 	r.authServices = authServicesMap
 	r.tools = toolsMap
 	r.toolsets = toolsetsMap
+	r.prompts = promptsMap
+	r.promptsets = promptsetsMap
+	r.mu.Unlock()
+	err := closeSources(replaced, sourcesMap)
+
+	if len(change.Toolsets) == 0 && len(change.Promptsets) == 0 {
+		return err
+	}
+	r.subscribersMu.Lock()
+	subscribers := slices.Collect(maps.Values(r.subscribers))
+	r.subscribersMu.Unlock()
+	for _, fn := range subscribers {
+		fn(change)
+	}
+	return err
+}
+
+// Close closes the sources, e.g. the connections and processes of upstream
+// MCP servers.
+func (r *ResourceManager) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := closeSources(r.sources, nil)
+	r.sources = map[string]sources.Source{}
+	return err
+}
+
+// closeSources closes the sources of old that implement io.Closer, except
+// for those that are also sources of current.
+func closeSources(old, current map[string]sources.Source) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(old)) {
+		s := old[name]
+		closer, ok := s.(io.Closer)
+		if !ok {
+			continue
+		}
+		if reflect.TypeOf(s).Comparable() && current[name] == s {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close source %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers fn to be called with the changes every time the
+// resources are replaced. The returned function removes the subscription.
+func (r *ResourceManager) Subscribe(fn func(ResourceChange)) func() {
+	r.subscribersMu.Lock()
+	defer r.subscribersMu.Unlock()
+	id := r.nextSubscriber
+	r.nextSubscriber++
+	r.subscribers[id] = fn
+	return func() {
+		r.subscribersMu.Lock()
+		defer r.subscribersMu.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// changedToolsets returns the sorted names of the toolsets that were added,
+// removed, or whose tools changed.
+func changedToolsets(oldToolsets, newToolsets map[string]tools.Toolset) []string {
+	var changed []string
+	for name, n := range newToolsets {
+		o, ok := oldToolsets[name]
+		if !ok || !reflect.DeepEqual(o.McpManifest, n.McpManifest) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldToolsets {
+		if _, ok := newToolsets[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// changedPromptsets returns the sorted names of the promptsets that were
+// added, removed, or whose prompts changed.
+func changedPromptsets(oldPromptsets, newPromptsets map[string]prompts.Promptset) []string {
+	var changed []string
+	for name, n := range newPromptsets {
+		o, ok := oldPromptsets[name]
+		if !ok || !reflect.DeepEqual(o.McpManifest, n.McpManifest) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldPromptsets {
+		if _, ok := newPromptsets[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func (r *ResourceManager) GetSourcesMap() map[string]sources.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources
 }
 
 func (r *ResourceManager) GetAuthServiceMap() map[string]auth.AuthService {
@@ -128,6 +284,8 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	map[string]auth.AuthService,
 	map[string]tools.Tool,
 	map[string]tools.Toolset,
+	map[string]prompts.Prompt,
+	map[string]prompts.Promptset,
 	error,
 ) {
 	ctx = util.WithUserAgent(ctx, cfg.Version)
@@ -141,8 +299,17 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 		panic(err)
 	}
 
-	// initialize and validate the sources from configs
+	// initialize and validate the sources from configs, which are closed if
+	// the configs fail to initialize
 	sourcesMap := make(map[string]sources.Source)
+	initialized := false
+	defer func() {
+		if !initialized {
+			if err := closeSources(sourcesMap, nil); err != nil {
+				l.WarnContext(ctx, err.Error())
+			}
+		}
+	}()
 	for name, sc := range cfg.SourceConfigs {
 		s, err := func() (sources.Source, error) {
 			childCtx, span := instrumentation.Tracer.Start(
@@ -159,7 +326,7 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			return s, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 		sourcesMap[name] = s
 	}
@@ -169,30 +336,42 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 	authServicesMap := make(map[string]auth.AuthService)
 	for name, sc := range cfg.AuthServiceConfigs {
 		a, err := func() (auth.AuthService, error) {
-			_, span := instrumentation.Tracer.Start(
+			ctx, span := instrumentation.Tracer.Start(
 				ctx,
 				"toolbox/server/auth/init",
 				trace.WithAttributes(attribute.String("auth_kind", sc.AuthServiceConfigKind())),
 				trace.WithAttributes(attribute.String("auth_name", name)),
 			)
 			defer span.End()
-			a, err := sc.Initialize()
+			a, err := sc.Initialize(ctx, instrumentation.Tracer)
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize auth service %q: %w", name, err)
 			}
 			return a, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 		authServicesMap[name] = a
 	}
+	// checked here so that dynamic reloads are rejected as the startup is
+	if err := checkClientCertAuthServices(cfg, authServicesMap); err != nil {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("unable to initialize TLS: %w", err)
+	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d authServices.", len(authServicesMap)))
 
 	// initialize and validate the tools from configs
 	toolsMap := make(map[string]tools.Tool)
+	toolAuthPolicies := make(map[string]tools.AuthPolicies)
 	for name, tc := range cfg.ToolConfigs {
-		t, err := func() (tools.Tool, error) {
+		var policies tools.AuthPolicies
+		if pc, ok := tc.(tools.AuthPolicyToolConfig); ok {
+			tc, policies = pc.ToolConfig, pc.AuthPolicies
+			if err := policies.Initialize(); err != nil {
+				return nil, nil, nil, nil, nil, nil, fmt.Errorf("invalid tool %q: %w", name, err)
+			}
+		}
+		ts, err := func() (map[string]tools.Tool, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
 				"toolbox/server/tool/init",
@@ -200,33 +379,128 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 				trace.WithAttributes(attribute.String("tool_name", name)),
 			)
 			defer span.End()
+			if mc, ok := tc.(tools.MultiToolConfig); ok {
+				ts, err := mc.InitializeTools(sourcesMap)
+				if err != nil {
+					return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
+				}
+				return ts, nil
+			}
 			t, err := tc.Initialize(sourcesMap)
 			if err != nil {
 				return nil, fmt.Errorf("unable to initialize tool %q: %w", name, err)
 			}
-			return t, nil
+			return map[string]tools.Tool{name: t}, nil
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
+		}
+		for toolName, t := range ts {
+			// tools expanded from a config must not shadow other tools
+			_, configured := cfg.ToolConfigs[toolName]
+			if _, exists := toolsMap[toolName]; exists || (configured && toolName != name) {
+				return nil, nil, nil, nil, nil, nil, fmt.Errorf("tool %q of tool config %q has the same name as another tool", toolName, name)
+			}
+			toolsMap[toolName] = t
+			if policies != nil {
+				toolAuthPolicies[toolName] = policies
+			}
 		}
-		toolsMap[name] = t
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d tools.", len(toolsMap)))
 
-	// create a default toolset that contains all tools
+	// initialize and validate the prompts from configs
+	promptsMap := make(map[string]prompts.Prompt)
+	for name, pc := range cfg.PromptConfigs {
+		if _, ok := toolsMap[name]; ok {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("prompt %q has the same name as a tool", name)
+		}
+		p, err := func() (prompts.Prompt, error) {
+			_, span := instrumentation.Tracer.Start(
+				ctx,
+				"toolbox/server/prompt/init",
+				trace.WithAttributes(attribute.String("prompt_kind", pc.PromptConfigKind())),
+				trace.WithAttributes(attribute.String("prompt_name", name)),
+			)
+			defer span.End()
+			p, err := pc.Initialize()
+			if err != nil {
+				return nil, fmt.Errorf("unable to initialize prompt %q: %w", name, err)
+			}
+			return p, nil
+		}()
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
+		promptsMap[name] = p
+	}
+	l.InfoContext(ctx, fmt.Sprintf("Initialized %d prompts.", len(promptsMap)))
+
+	// the completion sources of tool parameters and prompt arguments must be tools
+	for name, t := range toolsMap {
+		if err := tools.ValidateCompletionSources(t.Manifest().Parameters, toolsMap); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("invalid tool %q: %w", name, err)
+		}
+	}
+	for name, p := range promptsMap {
+		if err := prompts.ValidateCompletionSources(p.McpManifest().Arguments, toolsMap); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("invalid prompt %q: %w", name, err)
+		}
+	}
+
+	// the sources of toolsets publish their resources to MCP clients
+	for name, tc := range cfg.ToolsetConfigs {
+		for _, sourceName := range tc.Sources {
+			if _, ok := sourcesMap[sourceName]; !ok {
+				return nil, nil, nil, nil, nil, nil, fmt.Errorf("unable to initialize toolset %q: source %q does not exist", name, sourceName)
+			}
+		}
+	}
+
+	// create a default toolset that contains all tools, prompts and sources,
+	// except for the tools and sources of strict toolsets
+	strictToolNames := make(map[string]bool)
+	strictSourceNames := make(map[string]bool)
+	for _, tc := range cfg.ToolsetConfigs {
+		if tc.Strict {
+			for _, name := range tc.ToolNames {
+				strictToolNames[name] = true
+			}
+			for _, name := range tc.Sources {
+				strictSourceNames[name] = true
+			}
+		}
+	}
+	// the names are sorted so that the default toolset lists its tools in the
+	// same order between restarts
 	allToolNames := make([]string, 0, len(toolsMap))
-	for name := range toolsMap {
+	for _, name := range slices.Sorted(maps.Keys(toolsMap)) {
+		if strictToolNames[name] {
+			continue
+		}
 		allToolNames = append(allToolNames, name)
 	}
+	allPromptNames := slices.Sorted(maps.Keys(promptsMap))
+	allSourceNames := make([]string, 0, len(sourcesMap))
+	for _, name := range slices.Sorted(maps.Keys(sourcesMap)) {
+		if strictSourceNames[name] {
+			continue
+		}
+		allSourceNames = append(allSourceNames, name)
+	}
 	if cfg.ToolsetConfigs == nil {
 		cfg.ToolsetConfigs = make(ToolsetConfigs)
 	}
-	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: allToolNames}
+	cfg.ToolsetConfigs[""] = tools.ToolsetConfig{Name: "", ToolNames: slices.Concat(allToolNames, allPromptNames), Sources: allSourceNames}
 
 	// initialize and validate the toolsets from configs
+	// a toolset lists both tools and prompts, the prompts are split into a
+	// promptset of the same name
 	toolsetsMap := make(map[string]tools.Toolset)
+	promptsetsMap := make(map[string]prompts.Promptset)
 	for name, tc := range cfg.ToolsetConfigs {
-		t, err := func() (tools.Toolset, error) {
+		tc, pc := splitToolsetConfig(tc, promptsMap)
+		t, p, err := func() (tools.Toolset, prompts.Promptset, error) {
 			_, span := instrumentation.Tracer.Start(
 				ctx,
 				"toolbox/server/toolset/init",
@@ -235,18 +509,42 @@ func InitializeConfigs(ctx context.Context, cfg ServerConfig) (
 			defer span.End()
 			t, err := tc.Initialize(cfg.Version, toolsMap)
 			if err != nil {
-				return tools.Toolset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
+				return tools.Toolset{}, prompts.Promptset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
+			}
+			if len(toolAuthPolicies) > 0 {
+				t.ToolAuthPolicies = toolAuthPolicies
+			}
+			p, err := pc.Initialize(promptsMap)
+			if err != nil {
+				return tools.Toolset{}, prompts.Promptset{}, fmt.Errorf("unable to initialize toolset %q: %w", name, err)
 			}
-			return t, err
+			return t, p, err
 		}()
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, nil, nil, nil, nil, nil, err
 		}
 		toolsetsMap[name] = t
+		promptsetsMap[name] = p
 	}
 	l.InfoContext(ctx, fmt.Sprintf("Initialized %d toolsets.", len(toolsetsMap)))
 
-	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, nil
+	initialized = true
+	return sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, nil
+}
+
+// splitToolsetConfig separates the prompts listed in a toolset from its tools.
+func splitToolsetConfig(tc tools.ToolsetConfig, promptsMap map[string]prompts.Prompt) (tools.ToolsetConfig, prompts.PromptsetConfig) {
+	toolNames := make([]string, 0, len(tc.ToolNames))
+	var promptNames []string
+	for _, name := range tc.ToolNames {
+		if _, ok := promptsMap[name]; ok {
+			promptNames = append(promptNames, name)
+			continue
+		}
+		toolNames = append(toolNames, name)
+	}
+	tc.ToolNames = toolNames
+	return tc, prompts.PromptsetConfig{Name: tc.Name, PromptNames: promptNames}
 }
 
 // NewServer returns a Server object based on provided Config.
@@ -298,27 +596,57 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 	httpLogger := httplog.NewLogger("httplog", httpOpts)
 	r.Use(httplog.RequestLogger(httpLogger))
 
-	sourcesMap, authServicesMap, toolsMap, toolsetsMap, err := InitializeConfigs(ctx, cfg)
+	sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap, err := InitializeConfigs(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize configs: %w", err)
 	}
 
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize TLS: %w", err)
+	}
+	if tlsConfig != nil {
+		r.Use(clientCertificates)
+	}
+
 	addr := net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port))
-	srv := &http.Server{Addr: addr, Handler: r}
+	srv := &http.Server{Addr: addr, Handler: r, TLSConfig: tlsConfig}
 
 	sseManager := newSseManager(ctx)
+	streamableManager := newStreamableManager(ctx)
+
+	jobStore, err := newJobStore(ctx, cfg.JobsStore)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize jobs store: %w", err)
+	}
+	jobManager, err := newJobManager(ctx, jobStore, cfg.JobsTTL, cfg.JobsMax, cfg.JobsMaxRunning)
+	if err != nil {
+		_ = jobStore.close()
+		return nil, fmt.Errorf("unable to initialize jobs: %w", err)
+	}
 
-	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap)
+	resourceManager := NewResourceManager(sourcesMap, authServicesMap, toolsMap, toolsetsMap, promptsMap, promptsetsMap)
 
 	s := &Server{
-		version:         cfg.Version,
-		srv:             srv,
-		root:            r,
-		logger:          l,
-		instrumentation: instrumentation,
-		sseManager:      sseManager,
-		ResourceMgr:     resourceManager,
+		version:             cfg.Version,
+		srv:                 srv,
+		root:                r,
+		logger:              l,
+		instrumentation:     instrumentation,
+		sseManager:          sseManager,
+		streamableManager:   streamableManager,
+		jobManager:          jobManager,
+		batchConcurrency:    cfg.McpBatchConcurrency,
+		apiBatchConcurrency: cfg.ApiBatchConcurrency,
+		apiBatchMaxSize:     cfg.ApiBatchMaxSize,
+		adminAuthServices:   cfg.AdminAuthServices,
+		toolsPageSize:       cfg.ToolsPageSize,
+		tlsClientCAFile:     cfg.TLSClientCAFile,
+		ResourceMgr:         resourceManager,
 	}
+	// notify connected MCP clients when the resources are reloaded
+	s.ResourceMgr.Subscribe(s.notifyListChanged)
+
 	// control plane
 	apiR, err := apiRouter(s)
 	if err != nil {
@@ -330,6 +658,7 @@ func NewServer(ctx context.Context, cfg ServerConfig) (*Server, error) {
 		return nil, err
 	}
 	r.Mount("/mcp", mcpR)
+	addHealthRoutes(s, r)
 	// default endpoint for validating server is running
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("🧰 Hello, World! 🧰"))
@@ -355,12 +684,25 @@ func (s *Server) Listen(ctx context.Context) error {
 	return nil
 }
 
-// Serve starts an HTTP server for the given Server instance.
+// Serve starts an HTTP server for the given Server instance, or an HTTPS
+// server if it is configured with TLS.
 func (s *Server) Serve(ctx context.Context) error {
+	if s.srv.TLSConfig != nil {
+		s.logger.DebugContext(ctx, "Starting a HTTPS server.")
+		// the certificate is set by the TLS config
+		return s.srv.ServeTLS(s.listener, "", "")
+	}
 	s.logger.DebugContext(ctx, "Starting a HTTP server.")
 	return s.srv.Serve(s.listener)
 }
 
+// TLSClientCAFile returns the CA bundle that verifies the client certificates
+// of the server, so that the auth services of dynamic reloads are checked
+// against it.
+func (s *Server) TLSClientCAFile() string {
+	return s.tlsClientCAFile
+}
+
 // ServeStdio starts a new stdio session for mcp.
 func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
 	stdioServer := NewStdioSession(s, stdin, stdout)
@@ -369,7 +711,16 @@ func (s *Server) ServeStdio(ctx context.Context, stdin io.Reader, stdout io.Writ
 
 // Shutdown gracefully shuts down the server without interrupting any active
 // connections. It uses http.Server.Shutdown() and has the same functionality.
+// The running jobs are cancelled and the sources closed once the connections
+// are closed.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.DebugContext(ctx, "shutting down the server.")
-	return s.srv.Shutdown(ctx)
+	err := s.srv.Shutdown(ctx)
+	if jobsErr := s.jobManager.close(); jobsErr != nil && err == nil {
+		err = fmt.Errorf("unable to close jobs store: %w", jobsErr)
+	}
+	if sourcesErr := s.ResourceMgr.Close(); sourcesErr != nil && err == nil {
+		err = fmt.Errorf("unable to close sources: %w", sourcesErr)
+	}
+	return err
 }
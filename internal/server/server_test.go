@@ -29,6 +29,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/alloydbpg"
+	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/telemetry"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
 	"github.com/googleapis/genai-toolbox/internal/tools"
@@ -147,7 +148,7 @@ func TestUpdateServer(t *testing.T) {
 			Name: "example-toolset", Tools: []*tools.Tool{},
 		},
 	}
-	s.ResourceMgr.SetResources(newSources, newAuth, newTools, newToolsets)
+	s.ResourceMgr.SetResources(newSources, newAuth, newTools, newToolsets, nil, nil)
 	if err != nil {
 		t.Errorf("error updating server: %s", err)
 	}
@@ -172,3 +173,90 @@ func TestUpdateServer(t *testing.T) {
 		t.Errorf("error updating server, toolset (-want +got):\n%s", diff)
 	}
 }
+
+func TestInitializeToolsetSources(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("error setting up logger: %s", err)
+	}
+	instrumentation, err := telemetry.CreateTelemetryInstrumentation("0.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx = util.WithInstrumentation(ctx, instrumentation)
+
+	cfg := server.ServerConfig{
+		Version: "0.0.0",
+		SourceConfigs: server.SourceConfigs{
+			"my-source":     sqlite.Config{Name: "my-source", Kind: sqlite.SourceKind, Database: ":memory:"},
+			"strict-source": sqlite.Config{Name: "strict-source", Kind: sqlite.SourceKind, Database: ":memory:"},
+		},
+		ToolsetConfigs: server.ToolsetConfigs{
+			"plain_toolset":  tools.ToolsetConfig{Name: "plain_toolset"},
+			"strict_toolset": tools.ToolsetConfig{Name: "strict_toolset", Strict: true, Sources: []string{"strict-source"}},
+		},
+	}
+	_, _, _, toolsets, _, _, err := server.InitializeConfigs(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unable to initialize configs: %s", err)
+	}
+	want := map[string][]string{
+		"":               {"my-source"},
+		"plain_toolset":  nil,
+		"strict_toolset": {"strict-source"},
+	}
+	for name, wantSources := range want {
+		if diff := cmp.Diff(wantSources, toolsets[name].SourceNames); diff != "" {
+			t.Errorf("incorrect sources of toolset %q (-want +got):\n%s", name, diff)
+		}
+	}
+
+	cfg.ToolsetConfigs["plain_toolset"] = tools.ToolsetConfig{Name: "plain_toolset", Sources: []string{"unknown-source"}}
+	_, _, _, _, _, _, err = server.InitializeConfigs(ctx, cfg)
+	if err == nil || err.Error() != `unable to initialize toolset "plain_toolset": source "unknown-source" does not exist` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// closerSource is a source that records whether it was closed.
+type closerSource struct {
+	closed bool
+}
+
+func (s *closerSource) SourceKind() string {
+	return "closer"
+}
+
+func (s *closerSource) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestSetResourcesClosesSources(t *testing.T) {
+	first, second, third := &closerSource{}, &closerSource{}, &closerSource{}
+	r := server.NewResourceManager(map[string]sources.Source{"upstream": first}, nil, nil, nil, nil, nil)
+
+	// the first reload replaces the upstream
+	if err := r.SetResources(map[string]sources.Source{"upstream": second}, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !first.closed || second.closed {
+		t.Fatalf("unexpected closed sources after the first reload: first %t, second %t", first.closed, second.closed)
+	}
+
+	// the second reload keeps the upstream and adds another source
+	if err := r.SetResources(map[string]sources.Source{"upstream": second, "other": third}, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if second.closed || third.closed {
+		t.Fatalf("unexpected closed sources after the second reload: second %t, third %t", second.closed, third.closed)
+	}
+
+	// the remaining sources are closed on shutdown
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !second.closed || !third.closed {
+		t.Fatalf("unexpected open sources after closing: second %t, third %t", !second.closed, !third.closed)
+	}
+}
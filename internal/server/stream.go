@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+// Formats of the streamed results of tool invocations.
+const (
+	streamNDJSON = "ndjson"
+	streamSSE    = "sse"
+)
+
+// Media types of the streamed results of tool invocations.
+const (
+	ndjsonMediaType = "application/x-ndjson"
+	sseMediaType    = "text/event-stream"
+)
+
+// streamErrorTrailer is the trailer that holds the error of an NDJSON stream
+// that failed after its first result was written.
+const streamErrorTrailer = "Toolbox-Error"
+
+// streamFormat returns the format in which the results of a tool invocation
+// are streamed, or "" if they are not. The `stream` query parameter takes
+// precedence over the Accept header.
+func streamFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("stream"); format {
+	case "":
+	case streamNDJSON, streamSSE:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid stream format %q: must be %q or %q", format, streamNDJSON, streamSSE)
+	}
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ndjsonMediaType:
+			return streamNDJSON, nil
+		case sseMediaType:
+			return streamSSE, nil
+		}
+	}
+	return "", nil
+}
+
+// writeResultStream writes the results of a tool invocation as they are
+// yielded, one JSON value per line for NDJSON or one `result` event each for
+// SSE. An error before the first result is rendered as an error response.
+// Once results were written, the error is sent in the Toolbox-Error trailer
+// for NDJSON, or as an `error` event for SSE. SSE streams that succeed end
+// with a `done` event. It returns the error that ended the stream.
+func writeResultStream(w http.ResponseWriter, r *http.Request, format string, results iter.Seq2[any, error]) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		err := fmt.Errorf("unable to retrieve flusher for streaming")
+		_ = render.Render(w, r, newErrResponse(err, http.StatusInternalServerError))
+		return err
+	}
+
+	started := false
+	start := func() {
+		started = true
+		if format == streamNDJSON {
+			w.Header().Set("Content-Type", ndjsonMediaType)
+			w.Header().Set("Trailer", streamErrorTrailer)
+		} else {
+			w.Header().Set("Content-Type", sseMediaType)
+			w.Header().Set("Connection", "keep-alive")
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+	}
+	fail := func(err error, code int) error {
+		if !started {
+			_ = render.Render(w, r, newErrResponse(err, code))
+			return err
+		}
+		if format == streamNDJSON {
+			w.Header().Set(streamErrorTrailer, err.Error())
+			return err
+		}
+		eventData, _ := json.Marshal(newErrResponse(err, code))
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", eventData)
+		flusher.Flush()
+		return err
+	}
+
+	for res, err := range results {
+		if err != nil {
+			return fail(fmt.Errorf("error while invoking tool: %w", err), http.StatusBadRequest)
+		}
+		b, err := json.Marshal(res)
+		if err != nil {
+			return fail(fmt.Errorf("unable to marshal result: %w", err), http.StatusInternalServerError)
+		}
+		if !started {
+			start()
+		}
+		if format == streamNDJSON {
+			fmt.Fprintf(w, "%s\n", b)
+		} else {
+			fmt.Fprintf(w, "event: result\ndata: %s\n\n", b)
+		}
+		flusher.Flush()
+		if err := r.Context().Err(); err != nil {
+			// the client went away
+			return err
+		}
+	}
+	if !started {
+		start()
+	}
+	if format == streamSSE {
+		fmt.Fprint(w, "event: done\ndata: {}\n\n")
+	}
+	flusher.Flush()
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// newTLSConfig returns the TLS config of the server, or nil if the server
+// serves plain HTTP. Client certificates are verified by the client CAs if
+// they are set, and are required if RequireClientCert is set.
+func newTLSConfig(cfg ServerConfig) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" {
+		if cfg.TLSClientCAFile != "" || cfg.TLSRequireClientCert {
+			return nil, fmt.Errorf("client certificates require --tls-cert and --tls-key to be set")
+		}
+		return nil, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.TLSClientCAFile == "" {
+		if cfg.TLSRequireClientCert {
+			return nil, fmt.Errorf("--tls-require-client-cert requires --tls-client-ca to be set")
+		}
+		return tlsConfig, nil
+	}
+	b, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read TLS client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("unable to parse TLS client CA: no PEM certificates found")
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if cfg.TLSRequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// checkClientCertAuthServices returns an error if a client certificate auth
+// service is configured while the server does not verify client
+// certificates, since the auth service could never identify a client.
+func checkClientCertAuthServices(cfg ServerConfig, authServices map[string]auth.AuthService) error {
+	if cfg.TLSClientCAFile != "" {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(authServices)) {
+		if authServices[name].AuthServiceKind() == clientcert.AuthServiceKind {
+			return fmt.Errorf("auth service %q of kind %q requires --tls-cert, --tls-key and --tls-client-ca to be set", name, clientcert.AuthServiceKind)
+		}
+	}
+	return nil
+}
+
+// clientCertificates adds the verified certificate chain of the client into
+// the context of the request, for the auth services that verify client
+// certificates. Certificates that the server did not verify are ignored.
+func clientCertificates(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			r = r.WithContext(util.WithClientCertificates(r.Context(), r.TLS.VerifiedChains[0]))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
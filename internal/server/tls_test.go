@@ -0,0 +1,324 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/auth"
+	"github.com/googleapis/genai-toolbox/internal/auth/clientcert"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tlsFiles are the PEM files of a CA, of a server certificate and of a client
+// certificate issued by the CA.
+type tlsFiles struct {
+	ca                  *testutils.CA
+	caFile              string
+	certFile, keyFile   string
+	clientCertificate   tls.Certificate
+	untrustedClientCert tls.Certificate
+}
+
+func writeTLSFiles(t *testing.T) tlsFiles {
+	dir := t.TempDir()
+	write := func(name string, b []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, b, 0o600); err != nil {
+			t.Fatalf("unable to write %s: %s", name, err)
+		}
+		return path
+	}
+	keyPair := func(ca *testutils.CA, template x509.Certificate) tls.Certificate {
+		certPEM, keyPEM, err := ca.Issue(template)
+		if err != nil {
+			t.Fatalf("unable to issue certificate: %s", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("unable to load certificate: %s", err)
+		}
+		return cert
+	}
+
+	ca, err := testutils.NewCA("my-ca")
+	if err != nil {
+		t.Fatalf("unable to create CA: %s", err)
+	}
+	otherCA, err := testutils.NewCA("other-ca")
+	if err != nil {
+		t.Fatalf("unable to create CA: %s", err)
+	}
+	certPEM, keyPEM, err := ca.Issue(x509.Certificate{
+		Subject:     pkix.Name{CommonName: "toolbox"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+	})
+	if err != nil {
+		t.Fatalf("unable to issue certificate: %s", err)
+	}
+	return tlsFiles{
+		ca:                  ca,
+		caFile:              write("ca.pem", ca.PEM),
+		certFile:            write("cert.pem", certPEM),
+		keyFile:             write("key.pem", keyPEM),
+		clientCertificate:   keyPair(ca, x509.Certificate{Subject: pkix.Name{CommonName: "agent"}}),
+		untrustedClientCert: keyPair(otherCA, x509.Certificate{Subject: pkix.Name{CommonName: "agent"}}),
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	files := writeTLSFiles(t)
+	testCases := []struct {
+		name           string
+		cfg            ServerConfig
+		wantNil        bool
+		wantClientAuth tls.ClientAuthType
+		wantErr        string
+	}{
+		{
+			name:    "plain HTTP",
+			wantNil: true,
+		},
+		{
+			name:           "HTTPS",
+			cfg:            ServerConfig{TLSCertFile: files.certFile, TLSKeyFile: files.keyFile},
+			wantClientAuth: tls.NoClientCert,
+		},
+		{
+			name:           "optional client certificates",
+			cfg:            ServerConfig{TLSCertFile: files.certFile, TLSKeyFile: files.keyFile, TLSClientCAFile: files.caFile},
+			wantClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:           "required client certificates",
+			cfg:            ServerConfig{TLSCertFile: files.certFile, TLSKeyFile: files.keyFile, TLSClientCAFile: files.caFile, TLSRequireClientCert: true},
+			wantClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:    "missing key",
+			cfg:     ServerConfig{TLSCertFile: files.certFile},
+			wantErr: "--tls-cert and --tls-key must be set together",
+		},
+		{
+			name:    "client CA without certificate",
+			cfg:     ServerConfig{TLSClientCAFile: files.caFile},
+			wantErr: "client certificates require --tls-cert and --tls-key to be set",
+		},
+		{
+			name:    "required client certificates without client CA",
+			cfg:     ServerConfig{TLSCertFile: files.certFile, TLSKeyFile: files.keyFile, TLSRequireClientCert: true},
+			wantErr: "--tls-require-client-cert requires --tls-client-ca to be set",
+		},
+		{
+			name:    "invalid client CA",
+			cfg:     ServerConfig{TLSCertFile: files.certFile, TLSKeyFile: files.keyFile, TLSClientCAFile: files.keyFile},
+			wantErr: "unable to parse TLS client CA",
+		},
+		{
+			name:    "invalid key pair",
+			cfg:     ServerConfig{TLSCertFile: files.caFile, TLSKeyFile: files.keyFile},
+			wantErr: "unable to load TLS certificate",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newTLSConfig(tc.cfg)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantNil {
+				if got != nil {
+					t.Fatalf("unexpected TLS config: %+v", got)
+				}
+				return
+			}
+			if got.ClientAuth != tc.wantClientAuth {
+				t.Fatalf("unexpected client auth: got %s, want %s", got.ClientAuth, tc.wantClientAuth)
+			}
+		})
+	}
+}
+
+func TestCheckClientCertAuthServices(t *testing.T) {
+	certs, err := clientcert.Config{Name: "my-certs", Kind: clientcert.AuthServiceKind}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	other := MockAuthService{Name: "my-auth", Token: "valid-token"}
+	testCases := []struct {
+		name         string
+		cfg          ServerConfig
+		authServices map[string]auth.AuthService
+		wantErr      string
+	}{
+		{
+			name:         "no client certificate auth service",
+			authServices: map[string]auth.AuthService{"my-auth": other},
+		},
+		{
+			name:         "client certificate auth service with client CA",
+			cfg:          ServerConfig{TLSClientCAFile: "ca.pem"},
+			authServices: map[string]auth.AuthService{"my-auth": other, "my-certs": certs},
+		},
+		{
+			name:         "client certificate auth service without client CA",
+			cfg:          ServerConfig{TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"},
+			authServices: map[string]auth.AuthService{"my-auth": other, "my-certs": certs},
+			wantErr:      `auth service "my-certs" of kind "client-cert" requires --tls-cert, --tls-key and --tls-client-ca to be set`,
+		},
+		{
+			name:         "client certificate auth service without TLS",
+			authServices: map[string]auth.AuthService{"my-certs": certs},
+			wantErr:      `auth service "my-certs" of kind "client-cert" requires --tls-cert, --tls-key and --tls-client-ca to be set`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkClientCertAuthServices(tc.cfg, tc.authServices)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientCertificateAuth(t *testing.T) {
+	files := writeTLSFiles(t)
+	a, err := clientcert.Config{Name: "my-certs", Kind: clientcert.AuthServiceKind}.Initialize(context.Background(), noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize auth service: %s", err)
+	}
+	authRequiredTool := MockTool{
+		Name:         "auth_required",
+		Params:       []tools.Parameter{},
+		AuthRequired: []string{"my-certs"},
+	}
+	toolsMap, toolsets := setUpResources(t, []MockTool{authRequiredTool, tool1})
+	server, shutdown := newTestServer(t, NewResourceManager(nil, map[string]auth.AuthService{"my-certs": a}, toolsMap, toolsets, nil, nil))
+	defer shutdown()
+	r, err := apiRouter(server)
+	if err != nil {
+		t.Fatalf("unable to initialize api router: %s", err)
+	}
+
+	for _, required := range []bool{false, true} {
+		tlsConfig, err := newTLSConfig(ServerConfig{
+			TLSCertFile:          files.certFile,
+			TLSKeyFile:           files.keyFile,
+			TLSClientCAFile:      files.caFile,
+			TLSRequireClientCert: required,
+		})
+		if err != nil {
+			t.Fatalf("unable to create TLS config: %s", err)
+		}
+		ts := httptest.NewUnstartedServer(clientCertificates(r))
+		ts.TLS = tlsConfig
+		ts.StartTLS()
+		defer ts.Close()
+
+		roots := x509.NewCertPool()
+		roots.AddCert(files.ca.Cert)
+		testCases := []struct {
+			name       string
+			cert       *tls.Certificate
+			tool       string
+			wantStatus int
+			wantErr    bool
+		}{
+			{
+				name:       "without certificate",
+				tool:       tool1.Name,
+				wantStatus: http.StatusOK,
+				wantErr:    required,
+			},
+			{
+				name:       "authRequired without certificate",
+				tool:       authRequiredTool.Name,
+				wantStatus: http.StatusUnauthorized,
+				wantErr:    required,
+			},
+			{
+				name:       "authRequired with certificate",
+				cert:       &files.clientCertificate,
+				tool:       authRequiredTool.Name,
+				wantStatus: http.StatusOK,
+			},
+			{
+				name:    "untrusted certificate",
+				cert:    &files.untrustedClientCert,
+				tool:    authRequiredTool.Name,
+				wantErr: true,
+			},
+		}
+		for _, tc := range testCases {
+			name := tc.name
+			if required {
+				name = "required " + name
+			}
+			t.Run(name, func(t *testing.T) {
+				clientTLS := &tls.Config{RootCAs: roots}
+				if tc.cert != nil {
+					// present the certificate even if the server does not
+					// accept its CA
+					clientTLS.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+						return tc.cert, nil
+					}
+				}
+				client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+				resp, err := client.Post(ts.URL+"/tool/"+tc.tool+"/invoke", "application/json", strings.NewReader(`{}`))
+				if tc.wantErr {
+					if err == nil {
+						resp.Body.Close()
+						t.Fatalf("expected the TLS handshake to fail, got status %d", resp.StatusCode)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error during request: %s", err)
+				}
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				if resp.StatusCode != tc.wantStatus {
+					t.Fatalf("unexpected status code: got %d, want %d: %s", resp.StatusCode, tc.wantStatus, body)
+				}
+			})
+		}
+	}
+}
@@ -17,6 +17,7 @@ package bigquery
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	"github.com/goccy/go-yaml"
@@ -24,6 +25,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -77,6 +79,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
 
 type Source struct {
 	// BigQuery Google SQL struct with client
@@ -100,6 +103,55 @@ func (s *Source) GetProject() string {
 	return s.Project
 }
 
+// ListResources lists the tables of every dataset in the source project.
+// Table names are of the form `<dataset>.<table>`.
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	var tables []string
+	datasetIterator := s.Client.Datasets(ctx)
+	for {
+		dataset, err := datasetIterator.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate through datasets: %w", err)
+		}
+		tableIterator := dataset.Tables(ctx)
+		for {
+			table, err := tableIterator.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unable to iterate through tables in dataset %q: %w", dataset.DatasetID, err)
+			}
+			tables = append(tables, fmt.Sprintf("%s.%s", dataset.DatasetID, table.TableID))
+		}
+	}
+	return sources.NewTableResources(s.Name, SourceKind, tables), nil
+}
+
+// ReadResource returns the schema of a `<dataset>.<table>` table resource.
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	table, err := sources.TableFromResourceURI(s.Name, uri)
+	if err != nil {
+		return sources.ResourceContents{}, err
+	}
+	datasetId, tableId, ok := strings.Cut(table, ".")
+	if !ok {
+		return sources.ResourceContents{}, fmt.Errorf("invalid table %q: must be of the form <dataset>.<table>", table)
+	}
+	metadata, err := s.Client.Dataset(datasetId).Table(tableId).Metadata(ctx)
+	if err != nil {
+		return sources.ResourceContents{}, fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+	}
+	schema := sources.TableSchema{Source: s.Name, Name: table, Columns: []sources.ColumnSchema{}}
+	for _, f := range metadata.Schema {
+		schema.Columns = append(schema.Columns, sources.ColumnSchema{Name: f.Name, DataType: string(f.Type), Nullable: !f.Required})
+	}
+	return sources.NewTableSchemaContents(uri, schema)
+}
+
 func initBigQueryConnection(
 	ctx context.Context,
 	tracer trace.Tracer,
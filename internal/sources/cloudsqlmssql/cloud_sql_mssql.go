@@ -24,6 +24,7 @@ import (
 	"cloud.google.com/go/cloudsqlconn/sqlserver/mssql"
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	mssqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mssql"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -88,6 +89,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	// Cloud SQL MSSQL struct with connection pool
@@ -106,6 +110,22 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLPoolStats(s.Db)
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: mssqlsrc.SchemaQueries}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: mssqlsrc.SchemaQueries}, s.Name, uri)
+}
+
 func initCloudSQLMssqlConnection(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipAddress, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -23,6 +23,7 @@ import (
 	"cloud.google.com/go/cloudsqlconn/mysql/mysql"
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	mysqlsrc "github.com/googleapis/genai-toolbox/internal/sources/mysql"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -82,6 +83,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -97,6 +101,22 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Pool.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLPoolStats(s.Pool)
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, sources.SQLSchemaQuerier{DB: s.Pool, Queries: mysqlsrc.SchemaQueries}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, sources.SQLSchemaQuerier{DB: s.Pool, Queries: mysqlsrc.SchemaQueries}, s.Name, uri)
+}
+
 func initCloudSQLMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, project, region, instance, ipType, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -22,6 +22,7 @@ import (
 	"cloud.google.com/go/cloudsqlconn"
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/postgres"
 	"github.com/googleapis/genai-toolbox/internal/util"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel/trace"
@@ -82,6 +83,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -97,6 +101,22 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.PostgresPoolStats(s.Pool)
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, postgres.PoolSchemaQuerier{Pool: s.Pool}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, postgres.PoolSchemaQuerier{Pool: s.Pool}, s.Name, uri)
+}
+
 func getConnectionConfig(ctx context.Context, user, pass, dbname string) (string, bool, error) {
 	useIAM := true
 
@@ -90,6 +90,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.HealthChecker = &Source{}
 
 type Source struct {
 	Name                 string `yaml:"name"`
@@ -110,6 +111,16 @@ func (s *Source) CouchbaseQueryScanConsistency() uint {
 	return s.QueryScanConsistency
 }
 
+// CheckHealth runs a trivial query, since the scope has no access to the
+// ping of its cluster.
+func (s *Source) CheckHealth(ctx context.Context) error {
+	results, err := s.Scope.Query("SELECT 1", &gocb.QueryOptions{Context: ctx})
+	if err != nil {
+		return err
+	}
+	return results.Close()
+}
+
 func (r Config) createCouchbaseOptions() (gocb.ClusterOptions, error) {
 	cbOpts := gocb.ClusterOptions{}
 
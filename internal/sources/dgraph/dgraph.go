@@ -84,7 +84,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 		return nil, err
 	}
 
-	if err := hc.healthCheck(); err != nil {
+	if err := hc.healthCheck(ctx); err != nil {
 		return nil, err
 	}
 
@@ -97,6 +97,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.HealthChecker = &Source{}
 
 type Source struct {
 	Name   string        `yaml:"name"`
@@ -112,6 +113,10 @@ func (s *Source) DgraphClient() *DgraphClient {
 	return s.Client
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Client.healthCheck(ctx)
+}
+
 func initDgraphHttpClient(ctx context.Context, tracer trace.Tracer, r Config) (*DgraphClient, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
@@ -310,12 +315,12 @@ func (hc *DgraphClient) doLogin(creds map[string]interface{}) error {
 	return nil
 }
 
-func (hc *DgraphClient) healthCheck() error {
+func (hc *DgraphClient) healthCheck(ctx context.Context) error {
 	url, err := getUrl(hc.baseUrl, "/health", nil)
 	if err != nil {
 		return err
 	}
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HealthChecker is an optional interface that a Source can implement to
+// report whether it is able to serve requests, e.g. by pinging its database.
+type HealthChecker interface {
+	// CheckHealth returns an error if the source is unhealthy.
+	CheckHealth(context.Context) error
+}
+
+// PoolStatsProvider is an optional interface that a Source backed by a
+// connection pool can implement to report the statistics of the pool.
+type PoolStatsProvider interface {
+	PoolStats() PoolStats
+}
+
+// PoolStats are the statistics of the connection pool of a source.
+type PoolStats struct {
+	// MaxConns is the maximum number of connections, 0 if unlimited.
+	MaxConns int `json:"maxConns"`
+	// OpenConns is the number of established connections.
+	OpenConns int `json:"openConns"`
+	// InUseConns is the number of connections that are in use.
+	InUseConns int `json:"inUseConns"`
+	// IdleConns is the number of idle connections.
+	IdleConns int `json:"idleConns"`
+}
+
+// SQLPoolStats returns the statistics of a database/sql connection pool.
+func SQLPoolStats(db *sql.DB) PoolStats {
+	stats := db.Stats()
+	return PoolStats{
+		MaxConns:   stats.MaxOpenConnections,
+		OpenConns:  stats.OpenConnections,
+		InUseConns: stats.InUse,
+		IdleConns:  stats.Idle,
+	}
+}
+
+// PostgresPoolStats returns the statistics of a pgx connection pool.
+func PostgresPoolStats(pool *pgxpool.Pool) PoolStats {
+	stats := pool.Stat()
+	return PoolStats{
+		MaxConns:   int(stats.MaxConns()),
+		OpenConns:  int(stats.TotalConns()),
+		InUseConns: int(stats.AcquiredConns()),
+		IdleConns:  int(stats.IdleConns()),
+	}
+}
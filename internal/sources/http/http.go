@@ -106,6 +106,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.HealthChecker = &Source{}
 
 type Source struct {
 	Name           string            `yaml:"name"`
@@ -119,3 +120,20 @@ type Source struct {
 func (s *Source) SourceKind() string {
 	return SourceKind
 }
+
+// CheckHealth checks that the base URL is reachable. Any response counts as
+// healthy, since the base URL itself may not be an endpoint.
+func (s *Source) CheckHealth(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.DefaultHeaders {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,505 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/googleapis/genai-toolbox/internal/server/mcp/jsonrpc"
+)
+
+// errClosed is returned for the requests that are pending when the
+// connection to the upstream server is closed.
+var errClosed = errors.New("connection to upstream MCP server closed")
+
+// transport carries the JSON-RPC messages between the client and an upstream
+// MCP server. Messages received from the server are passed to the handler of
+// the transport.
+type transport interface {
+	// send sends a message to the server.
+	send(ctx context.Context, message []byte) error
+	// close closes the connection to the server.
+	close() error
+}
+
+// response is a JSON-RPC response, or error response, of the server.
+type response struct {
+	Id     jsonrpc.RequestId `json:"id"`
+	Method string            `json:"method"`
+	Result json.RawMessage   `json:"result"`
+	Error  *jsonrpc.Error    `json:"error"`
+}
+
+// client is a JSON-RPC client of an upstream MCP server. Requests are matched
+// to their responses by id, so that they can be sent concurrently.
+type client struct {
+	transport transport
+
+	mu      sync.Mutex
+	nextId  int64
+	pending map[int64]chan response
+	closed  bool
+}
+
+func newClient() *client {
+	return &client{pending: make(map[int64]chan response)}
+}
+
+// call sends a request and unmarshals the result of its response into result.
+func (c *client) call(ctx context.Context, method string, params any, result any) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return errClosed
+	}
+	c.nextId++
+	id := c.nextId
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	req := jsonrpc.JSONRPCRequest{
+		Jsonrpc: jsonrpc.JSONRPC_VERSION,
+		Id:      id,
+		Request: jsonrpc.Request{Method: method},
+		Params:  params,
+	}
+	message, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s request: %w", method, err)
+	}
+	if err := c.transport.send(ctx, message); err != nil {
+		return fmt.Errorf("unable to send %s request: %w", method, err)
+	}
+
+	select {
+	case res, ok := <-ch:
+		if !ok {
+			return errClosed
+		}
+		if res.Error != nil {
+			return fmt.Errorf("%s request failed: %s", method, res.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		if err := json.Unmarshal(res.Result, result); err != nil {
+			return fmt.Errorf("invalid %s response: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		// let the server stop processing the request
+		_ = c.notify(context.WithoutCancel(ctx), "notifications/cancelled", map[string]any{"requestId": id, "reason": context.Cause(ctx).Error()})
+		return ctx.Err()
+	}
+}
+
+// notify sends a notification.
+func (c *client) notify(ctx context.Context, method string, params any) error {
+	notification := map[string]any{"jsonrpc": jsonrpc.JSONRPC_VERSION, "method": method}
+	if params != nil {
+		notification["params"] = params
+	}
+	message, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("unable to marshal %s notification: %w", method, err)
+	}
+	return c.transport.send(ctx, message)
+}
+
+// handle dispatches a message received from the server. Responses are passed
+// to their pending request. Requests of the server are answered, pings with an
+// empty result and anything else as an unknown method, and notifications are
+// ignored.
+func (c *client) handle(message []byte) {
+	var res response
+	if err := json.Unmarshal(message, &res); err != nil {
+		return
+	}
+	if res.Method != "" {
+		if res.Id == nil {
+			return
+		}
+		var reply any = jsonrpc.JSONRPCResponse{Jsonrpc: jsonrpc.JSONRPC_VERSION, Id: res.Id, Result: struct{}{}}
+		if res.Method != "ping" {
+			reply = jsonrpc.NewError(res.Id, jsonrpc.METHOD_NOT_FOUND, fmt.Sprintf("invalid method %s", res.Method), nil)
+		}
+		if b, err := json.Marshal(reply); err == nil {
+			go func() { _ = c.transport.send(context.Background(), b) }()
+		}
+		return
+	}
+	// ids are sent as numbers, and decoded as float64
+	id, ok := res.Id.(float64)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	ch, ok := c.pending[int64(id)]
+	c.mu.Unlock()
+	if ok {
+		ch <- res
+	}
+}
+
+// handleClose fails the pending requests once the connection is closed.
+func (c *client) handleClose() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+// close closes the connection to the server.
+func (c *client) close() error {
+	c.handleClose()
+	return c.transport.close()
+}
+
+// streamTransport exchanges newline-delimited messages over a pair of
+// streams, e.g. the stdin and stdout of a process.
+type streamTransport struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	closer func() error
+}
+
+// newStreamTransport returns a transport that writes to w and reads from r
+// until it ends. closer, if set, is called when the transport is closed.
+func newStreamTransport(c *client, r io.Reader, w io.WriteCloser, closer func() error) *streamTransport {
+	t := &streamTransport{w: w, closer: closer}
+	go func() {
+		defer c.handleClose()
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				c.handle(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return t
+}
+
+func (t *streamTransport) send(_ context.Context, message []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.w.Write(append(message, '\n'))
+	return err
+}
+
+func (t *streamTransport) close() error {
+	err := t.w.Close()
+	if t.closer != nil {
+		return errors.Join(err, t.closer())
+	}
+	return err
+}
+
+// newStdioTransport spawns the command of an upstream server and exchanges
+// messages over its stdin and stdout.
+func newStdioTransport(c *client, command string, args []string, env []string) (*streamTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = env
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start command %q: %w", command, err)
+	}
+	closer := func() error {
+		// the server is expected to exit once its stdin is closed
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil
+	}
+	return newStreamTransport(c, stdout, stdin, closer), nil
+}
+
+// readSseEvents reads the events of an SSE stream, and calls fn with the type
+// and data of each event until the stream ends or fn returns false.
+func readSseEvents(r io.Reader, fn func(event, data string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	event, data := "", make([]string, 0)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if event == "" {
+					event = "message"
+				}
+				if !fn(event, strings.Join(data, "\n")) {
+					return nil
+				}
+			}
+			event, data = "", data[:0]
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return scanner.Err()
+}
+
+// sseTransport is the client of the HTTP with SSE transport. Messages are
+// received as events of the stream, and sent to the endpoint announced by the
+// first event.
+type sseTransport struct {
+	httpClient *http.Client
+	headers    map[string]string
+	endpoint   string
+	body       io.ReadCloser
+}
+
+// newSseTransport opens the SSE stream of an upstream server and waits for
+// its endpoint event.
+func newSseTransport(ctx context.Context, c *client, httpClient *http.Client, sseURL string, headers map[string]string) (*sseTransport, error) {
+	// the stream outlives the context of the initialization
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodGet, sseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	// the stream is long-lived, so the timeout of the client does not apply
+	streamClient := *httpClient
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open SSE stream: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unable to open SSE stream: unexpected status %s", resp.Status)
+	}
+
+	t := &sseTransport{httpClient: httpClient, headers: headers, body: resp.Body}
+	endpoint := make(chan string, 1)
+	go func() {
+		defer c.handleClose()
+		defer close(endpoint)
+		_ = readSseEvents(resp.Body, func(event, data string) bool {
+			switch event {
+			case "endpoint":
+				select {
+				case endpoint <- data:
+				default:
+				}
+			case "message":
+				c.handle([]byte(data))
+			}
+			return true
+		})
+	}()
+
+	select {
+	case e, ok := <-endpoint:
+		if !ok {
+			return nil, fmt.Errorf("SSE stream closed before the endpoint event")
+		}
+		base, err := url.Parse(sseURL)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		ref, err := url.Parse(e)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("invalid endpoint %q: %w", e, err)
+		}
+		t.endpoint = base.ResolveReference(ref).String()
+		return t, nil
+	case <-ctx.Done():
+		resp.Body.Close()
+		return nil, ctx.Err()
+	}
+}
+
+func (t *sseTransport) send(ctx context.Context, message []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (t *sseTransport) close() error {
+	return t.body.Close()
+}
+
+// streamableTransport is the client of the streamable HTTP transport. Each
+// message is posted to the endpoint, and the responses are returned either as
+// the JSON body or as an SSE stream of the response.
+type streamableTransport struct {
+	client     *client
+	httpClient *http.Client
+	url        string
+	headers    map[string]string
+
+	mu        sync.Mutex
+	sessionId string
+	protocol  string
+}
+
+func newStreamableTransport(c *client, httpClient *http.Client, endpoint string, headers map[string]string) *streamableTransport {
+	return &streamableTransport{client: c, httpClient: httpClient, url: endpoint, headers: headers}
+}
+
+// setProtocolVersion sets the protocol version negotiated during the
+// initialization, which is sent with every following request.
+func (t *streamableTransport) setProtocolVersion(v string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protocol = v
+}
+
+func (t *streamableTransport) send(ctx context.Context, message []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	if t.sessionId != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionId)
+	}
+	if t.protocol != "" {
+		req.Header.Set("MCP-Protocol-Version", t.protocol)
+	}
+	t.mu.Unlock()
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionId = id
+		t.mu.Unlock()
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		return readSseEvents(resp.Body, func(event, data string) bool {
+			if event == "message" {
+				t.client.handle([]byte(data))
+			}
+			return true
+		})
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	// the body is either a single response or a batch of them
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return fmt.Errorf("invalid batch response: %w", err)
+		}
+		for _, m := range batch {
+			t.client.handle(m)
+		}
+		return nil
+	}
+	t.client.handle(body)
+	return nil
+}
+
+func (t *streamableTransport) close() error {
+	t.mu.Lock()
+	sessionId := t.sessionId
+	t.mu.Unlock()
+	if sessionId == "" {
+		return nil
+	}
+	// terminate the session
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Mcp-Session-Id", sessionId)
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
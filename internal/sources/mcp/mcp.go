@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const SourceKind string = "mcp"
+
+// Transports of upstream MCP servers.
+const (
+	TransportStdio          = "stdio"
+	TransportSSE            = "sse"
+	TransportStreamableHTTP = "http"
+)
+
+// protocolVersion is the MCP protocol version requested from upstream servers.
+const protocolVersion = "2025-06-18"
+
+// validate interface
+var _ sources.SourceConfig = Config{}
+
+func init() {
+	if !sources.Register(SourceKind, newConfig) {
+		panic(fmt.Sprintf("source kind %q already registered", SourceKind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (sources.SourceConfig, error) {
+	actual := Config{Name: name, Timeout: "30s"} // Default timeout
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name      string `yaml:"name" validate:"required"`
+	Kind      string `yaml:"kind" validate:"required"`
+	Transport string `yaml:"transport" validate:"required"`
+	// Command, Args and Env configure the process spawned for the stdio
+	// transport.
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	// URL and Headers configure the HTTP requests of the SSE and streamable
+	// HTTP transports.
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Timeout string            `yaml:"timeout"`
+}
+
+func (r Config) SourceConfigKind() string {
+	return SourceKind
+}
+
+// Initialize connects to the upstream MCP server, initializes the session and
+// lists the tools of the server.
+func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.Source, error) {
+	//nolint:all // Reassigned ctx
+	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, r.Name)
+	defer span.End()
+
+	timeout, err := time.ParseDuration(r.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Timeout string as time.Duration: %s", err)
+	}
+	c := newClient()
+	switch r.Transport {
+	case TransportStdio:
+		if r.Command == "" {
+			return nil, fmt.Errorf("command is required for the %q transport", r.Transport)
+		}
+		env := os.Environ()
+		for k, v := range r.Env {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		c.transport, err = newStdioTransport(c, r.Command, r.Args, env)
+	case TransportSSE, TransportStreamableHTTP:
+		if _, err := url.ParseRequestURI(r.URL); err != nil {
+			return nil, fmt.Errorf("failed to parse url %v", err)
+		}
+		httpClient := &http.Client{Timeout: timeout}
+		if r.Transport == TransportSSE {
+			initCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			c.transport, err = newSseTransport(initCtx, c, httpClient, r.URL, r.Headers)
+		} else {
+			c.transport = newStreamableTransport(c, httpClient, r.URL, r.Headers)
+		}
+	default:
+		return nil, fmt.Errorf("invalid transport %q: must be one of %q, %q or %q", r.Transport, TransportStdio, TransportSSE, TransportStreamableHTTP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to upstream MCP server: %w", err)
+	}
+
+	s := &Source{
+		Name:    r.Name,
+		Kind:    SourceKind,
+		client:  c,
+		timeout: timeout,
+	}
+	if err := s.initialize(ctx); err != nil {
+		_ = c.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+var _ sources.Source = &Source{}
+
+type Source struct {
+	Name string `yaml:"name"`
+	Kind string `yaml:"kind"`
+	// Tools are the tools of the upstream server, in the order listed by it.
+	Tools []Tool
+
+	client  *client
+	timeout time.Duration
+}
+
+func (s *Source) SourceKind() string {
+	return SourceKind
+}
+
+// Tool is the definition of a tool of the upstream server.
+type Tool struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	Annotations json.RawMessage `json:"annotations,omitempty"`
+}
+
+// Content is a content item of the result of a tool call.
+type Content struct {
+	Type string `json:"type"`
+	// Text is set for text content.
+	Text string `json:"text,omitempty"`
+	// Raw is the whole content item.
+	Raw map[string]any `json:"-"`
+}
+
+func (c *Content) UnmarshalJSON(b []byte) error {
+	type content Content
+	if err := json.Unmarshal(b, (*content)(c)); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &c.Raw)
+}
+
+// CallToolResult is the result of a tool call.
+type CallToolResult struct {
+	Content           []Content      `json:"content"`
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
+	IsError           bool           `json:"isError,omitempty"`
+}
+
+// initialize runs the initialization handshake and lists the tools of the
+// upstream server.
+func (s *Source) initialize(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	version, err := util.UserAgentFromContext(ctx)
+	if err != nil {
+		version = "unknown"
+	}
+	params := map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "toolbox", "version": version},
+	}
+	var res struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := s.client.call(ctx, "initialize", params, &res); err != nil {
+		return fmt.Errorf("unable to initialize upstream MCP server: %w", err)
+	}
+	if t, ok := s.client.transport.(*streamableTransport); ok {
+		t.setProtocolVersion(res.ProtocolVersion)
+	}
+	if err := s.client.notify(ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("unable to initialize upstream MCP server: %w", err)
+	}
+
+	s.Tools = make([]Tool, 0)
+	cursor := ""
+	for {
+		var params map[string]any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		var res struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := s.client.call(ctx, "tools/list", params, &res); err != nil {
+			return fmt.Errorf("unable to list tools of upstream MCP server: %w", err)
+		}
+		s.Tools = append(s.Tools, res.Tools...)
+		if res.NextCursor == "" {
+			return nil
+		}
+		cursor = res.NextCursor
+	}
+}
+
+// CallTool calls a tool of the upstream server.
+func (s *Source) CallTool(ctx context.Context, name string, arguments map[string]any) (*CallToolResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	params := map[string]any{"name": name, "arguments": arguments}
+	var res CallToolResult
+	if err := s.client.call(ctx, "tools/call", params, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Close closes the connection to the upstream server.
+func (s *Source) Close() error {
+	return s.client.close()
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	"github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// fakeServerEnv makes the test binary serve fakeServer over stdio, so that it
+// can be spawned as the command of the stdio transport.
+const fakeServerEnv = "TOOLBOX_FAKE_MCP_SERVER"
+
+var fakeServer = &testutils.FakeMcpServer{
+	Tools: []map[string]any{
+		{"name": "echo", "description": "Echoes its arguments.", "inputSchema": map[string]any{"type": "object"}},
+		{"name": "add", "inputSchema": map[string]any{"type": "object"}},
+	},
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeServerEnv) == "1" {
+		if err := fakeServer.ServeStdio(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestParseFromYamlMcp(t *testing.T) {
+	tcs := []struct {
+		desc string
+		in   string
+		want server.SourceConfigs
+	}{
+		{
+			desc: "stdio example",
+			in: `
+			sources:
+				my-mcp-server:
+					kind: mcp
+					transport: stdio
+					command: npx
+					args:
+						- -y
+						- some-mcp-server
+					env:
+						API_KEY: secret
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mcp-server": mcp.Config{
+					Name:      "my-mcp-server",
+					Kind:      mcp.SourceKind,
+					Transport: mcp.TransportStdio,
+					Command:   "npx",
+					Args:      []string{"-y", "some-mcp-server"},
+					Env:       map[string]string{"API_KEY": "secret"},
+					Timeout:   "30s",
+				},
+			},
+		},
+		{
+			desc: "http example",
+			in: `
+			sources:
+				my-mcp-server:
+					kind: mcp
+					transport: http
+					url: http://test_server/mcp
+					headers:
+						Authorization: test_header
+					timeout: 10s
+			`,
+			want: map[string]sources.SourceConfig{
+				"my-mcp-server": mcp.Config{
+					Name:      "my-mcp-server",
+					Kind:      mcp.SourceKind,
+					Transport: mcp.TransportStreamableHTTP,
+					URL:       "http://test_server/mcp",
+					Headers:   map[string]string{"Authorization": "test_header"},
+					Timeout:   "10s",
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Sources server.SourceConfigs `yaml:"sources"`
+			}{}
+			// Parse contents
+			err := yaml.Unmarshal(testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if !cmp.Equal(tc.want, got.Sources) {
+				t.Fatalf("incorrect parse: want %v, got %v", tc.want, got.Sources)
+			}
+		})
+	}
+}
+
+func TestInitialize(t *testing.T) {
+	ts := httptest.NewServer(fakeServer)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc string
+		cfg  mcp.Config
+	}{
+		{
+			desc: "stdio",
+			cfg: mcp.Config{
+				Transport: mcp.TransportStdio,
+				Command:   os.Args[0],
+				Args:      []string{"-test.run=^$"},
+				Env:       map[string]string{fakeServerEnv: "1"},
+			},
+		},
+		{
+			desc: "sse",
+			cfg:  mcp.Config{Transport: mcp.TransportSSE, URL: ts.URL + "/sse"},
+		},
+		{
+			desc: "streamable http",
+			cfg:  mcp.Config{Transport: mcp.TransportStreamableHTTP, URL: ts.URL + "/mcp"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, err := testutils.ContextWithNewLogger()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Timeout = "my-mcp-server", mcp.SourceKind, "10s"
+			s, err := tc.cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+			if err != nil {
+				t.Fatalf("unable to initialize source: %s", err)
+			}
+			src := s.(*mcp.Source)
+			defer src.Close()
+
+			// tools of every page are listed
+			gotNames := make([]string, 0)
+			for _, tool := range src.Tools {
+				gotNames = append(gotNames, tool.Name)
+			}
+			if diff := cmp.Diff([]string{"echo", "add"}, gotNames); diff != "" {
+				t.Fatalf("unexpected tools (-want +got):\n%s", diff)
+			}
+
+			res, err := src.CallTool(context.Background(), "echo", map[string]any{"message": "hello"})
+			if err != nil {
+				t.Fatalf("unable to call tool: %s", err)
+			}
+			if len(res.Content) != 1 || res.Content[0].Text != `{"message":"hello"}` {
+				t.Fatalf("unexpected result: %+v", res)
+			}
+		})
+	}
+}
+
+func TestFailInitialize(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ts := httptest.NewServer(fakeServer)
+	defer ts.Close()
+
+	tcs := []struct {
+		desc string
+		cfg  mcp.Config
+		err  string
+	}{
+		{
+			desc: "invalid transport",
+			cfg:  mcp.Config{Transport: "grpc"},
+			err:  `invalid transport "grpc"`,
+		},
+		{
+			desc: "missing command",
+			cfg:  mcp.Config{Transport: mcp.TransportStdio},
+			err:  `command is required for the "stdio" transport`,
+		},
+		{
+			desc: "unknown endpoint",
+			cfg:  mcp.Config{Transport: mcp.TransportStreamableHTTP, URL: ts.URL + "/unknown"},
+			err:  "unable to initialize upstream MCP server",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind, tc.cfg.Timeout = "my-mcp-server", mcp.SourceKind, "10s"
+			_, err := tc.cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+			if err == nil {
+				t.Fatalf("expect initialization to fail")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
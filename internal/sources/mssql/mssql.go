@@ -83,6 +83,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	// Cloud SQL MSSQL struct with connection pool
@@ -101,6 +104,33 @@ func (s *Source) MSSQLDB() *sql.DB {
 	return s.Db
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLPoolStats(s.Db)
+}
+
+// SchemaQueries describe the tables of the connected SQL Server database,
+// which are named by their schema and name.
+var SchemaQueries = sources.SQLSchemaQueries{
+	ListTables: `SELECT TABLE_SCHEMA + '.' + TABLE_NAME FROM INFORMATION_SCHEMA.TABLES
+		WHERE TABLE_TYPE = 'BASE TABLE'
+		ORDER BY 1`,
+	ListColumns: `SELECT COLUMN_NAME, DATA_TYPE, IS_NULLABLE FROM INFORMATION_SCHEMA.COLUMNS
+		WHERE TABLE_SCHEMA + '.' + TABLE_NAME = @p1
+		ORDER BY ORDINAL_POSITION`,
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: SchemaQueries}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: SchemaQueries}, s.Name, uri)
+}
+
 func initMssqlConnection(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
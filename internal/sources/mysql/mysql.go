@@ -78,6 +78,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -93,6 +96,32 @@ func (s *Source) MySQLPool() *sql.DB {
 	return s.Pool
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Pool.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLPoolStats(s.Pool)
+}
+
+// SchemaQueries describe the tables of the connected MySQL database.
+var SchemaQueries = sources.SQLSchemaQueries{
+	ListTables: `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'
+		ORDER BY table_name`,
+	ListColumns: `SELECT column_name, column_type, is_nullable FROM information_schema.columns
+		WHERE table_schema = DATABASE() AND table_name = ?
+		ORDER BY ordinal_position`,
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, sources.SQLSchemaQuerier{DB: s.Pool, Queries: SchemaQueries}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, sources.SQLSchemaQuerier{DB: s.Pool, Queries: SchemaQueries}, s.Name, uri)
+}
+
 func initMySQLConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
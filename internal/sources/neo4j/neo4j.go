@@ -17,6 +17,7 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -80,6 +81,8 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
 
 type Source struct {
 	Name     string `yaml:"name"`
@@ -100,6 +103,71 @@ func (s *Source) Neo4jDatabase() string {
 	return s.Database
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Driver.VerifyConnectivity(ctx)
+}
+
+// ListResources lists the node labels of the graph. Each label is published
+// under the `labels` collection.
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	config := neo4j.ExecuteQueryWithDatabase(s.Database)
+	results, err := neo4j.ExecuteQuery(ctx, s.Driver, "CALL db.labels() YIELD label RETURN label ORDER BY label", nil,
+		neo4j.EagerResultTransformer, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list labels: %w", err)
+	}
+
+	resources := make([]sources.Resource, 0, len(results.Records))
+	for _, record := range results.Records {
+		label, ok := record.Values[0].(string)
+		if !ok {
+			continue
+		}
+		resources = append(resources, sources.Resource{
+			URI:         sources.ResourceURI(s.Name, sources.CollectionLabels, label),
+			Name:        label,
+			Description: fmt.Sprintf("Properties of nodes labeled %q in %s source %q.", label, SourceKind, s.Name),
+			MimeType:    "application/json",
+		})
+	}
+	return resources, nil
+}
+
+// ReadResource returns the properties of the nodes with a given label.
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	name, collection, label, err := sources.ParseResourceURI(uri)
+	if err != nil {
+		return sources.ResourceContents{}, err
+	}
+	if name != s.Name || collection != sources.CollectionLabels {
+		return sources.ResourceContents{}, fmt.Errorf("resource %q does not belong to source %q", uri, s.Name)
+	}
+
+	config := neo4j.ExecuteQueryWithDatabase(s.Database)
+	results, err := neo4j.ExecuteQuery(ctx, s.Driver,
+		"CALL db.schema.nodeTypeProperties() YIELD nodeLabels, propertyName, propertyTypes, mandatory "+
+			"WHERE $label IN nodeLabels AND propertyName IS NOT NULL "+
+			"RETURN propertyName, propertyTypes, mandatory ORDER BY propertyName",
+		map[string]any{"label": label}, neo4j.EagerResultTransformer, config)
+	if err != nil {
+		return sources.ResourceContents{}, fmt.Errorf("unable to describe label %q: %w", label, err)
+	}
+
+	schema := sources.TableSchema{Source: s.Name, Name: label, Columns: []sources.ColumnSchema{}}
+	for _, record := range results.Records {
+		propertyName, _ := record.Values[0].(string)
+		var types []string
+		if ts, ok := record.Values[1].([]any); ok {
+			for _, t := range ts {
+				types = append(types, fmt.Sprint(t))
+			}
+		}
+		mandatory, _ := record.Values[2].(bool)
+		schema.Columns = append(schema.Columns, sources.ColumnSchema{Name: propertyName, DataType: strings.Join(types, "|"), Nullable: !mandatory})
+	}
+	return sources.NewTableSchemaContents(uri, schema)
+}
+
 func initNeo4jDriver(ctx context.Context, tracer trace.Tracer, uri, user, password, name string) (neo4j.DriverWithContext, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
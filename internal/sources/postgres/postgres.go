@@ -78,6 +78,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -93,6 +96,57 @@ func (s *Source) PostgresPool() *pgxpool.Pool {
 	return s.Pool
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Pool.Ping(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.PostgresPoolStats(s.Pool)
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, PoolSchemaQuerier{Pool: s.Pool}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, PoolSchemaQuerier{Pool: s.Pool}, s.Name, uri)
+}
+
+// SchemaQueries describe the tables of a Postgres compatible database, which
+// are named by their schema and name.
+var SchemaQueries = sources.SQLSchemaQueries{
+	ListTables: `SELECT table_schema || '.' || table_name FROM information_schema.tables
+		WHERE table_schema NOT IN ('pg_catalog', 'information_schema') AND table_type = 'BASE TABLE'
+		ORDER BY 1`,
+	ListColumns: `SELECT column_name, data_type, is_nullable FROM information_schema.columns
+		WHERE table_schema || '.' || table_name = $1
+		ORDER BY ordinal_position`,
+}
+
+// PoolSchemaQuerier runs the SchemaQueries on a pgx pool.
+type PoolSchemaQuerier struct {
+	Pool *pgxpool.Pool
+}
+
+var _ sources.SchemaQuerier = PoolSchemaQuerier{}
+
+func (q PoolSchemaQuerier) ListTables(ctx context.Context, scan func(sources.Rows) error) error {
+	return q.query(ctx, SchemaQueries.ListTables, scan)
+}
+
+func (q PoolSchemaQuerier) ListColumns(ctx context.Context, table string, scan func(sources.Rows) error) error {
+	return q.query(ctx, SchemaQueries.ListColumns, scan, table)
+}
+
+func (q PoolSchemaQuerier) query(ctx context.Context, statement string, scan func(sources.Rows) error, args ...any) error {
+	rows, err := q.Pool.Query(ctx, statement, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scan(rows)
+}
+
 func initPostgresConnectionPool(ctx context.Context, tracer trace.Tracer, name, host, port, user, pass, dbname string) (*pgxpool.Pool, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -136,6 +136,7 @@ func initRedisClient(ctx context.Context, r Config) (RedisClient, error) {
 }
 
 var _ sources.Source = &Source{}
+var _ sources.HealthChecker = &Source{}
 
 type Source struct {
 	Name   string `yaml:"name"`
@@ -150,3 +151,7 @@ func (s *Source) SourceKind() string {
 func (s *Source) RedisClient() RedisClient {
 	return s.Client
 }
+
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Client.Do(ctx, "PING").Err()
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResourceURIScheme is the URI scheme used for resources published by sources.
+const ResourceURIScheme = "toolbox"
+
+// Resource collections that a source can publish.
+const (
+	CollectionTables = "tables"
+	CollectionLabels = "labels"
+)
+
+// ResourceProvider is an optional interface that a Source can implement to
+// publish schema metadata (e.g. table descriptions) as readable resources.
+type ResourceProvider interface {
+	ListResources(context.Context) ([]Resource, error)
+	ReadResource(ctx context.Context, uri string) (ResourceContents, error)
+}
+
+// Resource describes a single resource published by a source.
+type Resource struct {
+	URI         string
+	Name        string
+	Description string
+	MimeType    string
+}
+
+// ResourceContents is the contents of a resource.
+type ResourceContents struct {
+	URI      string
+	MimeType string
+	Text     string
+}
+
+// ColumnSchema describes a single column (or property) of a table.
+type ColumnSchema struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Nullable bool   `json:"nullable"`
+}
+
+// TableSchema is the description of a table that is served as a resource.
+type TableSchema struct {
+	Source  string         `json:"source"`
+	Name    string         `json:"name"`
+	Columns []ColumnSchema `json:"columns"`
+}
+
+// ResourceURI returns the URI of an item in the collection of a source, e.g.
+// `toolbox://sources/my-pg-source/tables/public.users`.
+func ResourceURI(sourceName, collection, item string) string {
+	return fmt.Sprintf("%s://sources/%s/%s/%s", ResourceURIScheme, sourceName, collection, item)
+}
+
+// ParseResourceURI splits a resource URI into the source name, collection and item.
+func ParseResourceURI(uri string) (string, string, string, error) {
+	prefix := ResourceURIScheme + "://sources/"
+	if !strings.HasPrefix(uri, prefix) {
+		return "", "", "", fmt.Errorf("invalid resource uri %q: must start with %q", uri, prefix)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(uri, prefix), "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid resource uri %q: must be of the form %s<source>/<collection>/<name>", uri, prefix)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// NewTableResources returns the list of table resources for a source.
+func NewTableResources(sourceName, sourceKind string, tables []string) []Resource {
+	resources := make([]Resource, 0, len(tables))
+	for _, t := range tables {
+		resources = append(resources, Resource{
+			URI:         ResourceURI(sourceName, CollectionTables, t),
+			Name:        t,
+			Description: fmt.Sprintf("Schema of table %q in %s source %q.", t, sourceKind, sourceName),
+			MimeType:    "application/json",
+		})
+	}
+	return resources
+}
+
+// NewTableSchemaContents returns the resource contents for a table schema.
+func NewTableSchemaContents(uri string, schema TableSchema) (ResourceContents, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return ResourceContents{}, fmt.Errorf("unable to marshal schema for %q: %w", schema.Name, err)
+	}
+	return ResourceContents{URI: uri, MimeType: "application/json", Text: string(b)}, nil
+}
+
+// TableFromResourceURI checks that the uri refers to a table of the named
+// source and returns the table name.
+func TableFromResourceURI(sourceName, uri string) (string, error) {
+	name, collection, table, err := ParseResourceURI(uri)
+	if err != nil {
+		return "", err
+	}
+	if name != sourceName || collection != CollectionTables {
+		return "", fmt.Errorf("resource %q does not belong to source %q", uri, sourceName)
+	}
+	return table, nil
+}
+
+// Rows are the rows of a query, e.g. *sql.Rows or pgx.Rows.
+type Rows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// SchemaQuerier runs the dialect specific queries that describe the tables of
+// a source, and passes their rows to scan before closing them.
+type SchemaQuerier interface {
+	// ListTables queries a single column with the name of each table.
+	ListTables(ctx context.Context, scan func(Rows) error) error
+	// ListColumns queries the name, the data type and whether it is nullable
+	// ("YES" or "NO") of each column of the table.
+	ListColumns(ctx context.Context, table string, scan func(Rows) error) error
+}
+
+// ListTableResources lists the tables of a source as resources.
+func ListTableResources(ctx context.Context, q SchemaQuerier, sourceName, sourceKind string) ([]Resource, error) {
+	var tables []string
+	err := q.ListTables(ctx, func(rows Rows) error {
+		for rows.Next() {
+			var t string
+			if err := rows.Scan(&t); err != nil {
+				return fmt.Errorf("unable to parse row: %w", err)
+			}
+			tables = append(tables, t)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("errors encountered during row iteration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables: %w", err)
+	}
+	return NewTableResources(sourceName, sourceKind, tables), nil
+}
+
+// ReadTableResource describes a table of a source.
+func ReadTableResource(ctx context.Context, q SchemaQuerier, sourceName, uri string) (ResourceContents, error) {
+	table, err := TableFromResourceURI(sourceName, uri)
+	if err != nil {
+		return ResourceContents{}, err
+	}
+	schema := TableSchema{Source: sourceName, Name: table, Columns: []ColumnSchema{}}
+	err = q.ListColumns(ctx, table, func(rows Rows) error {
+		for rows.Next() {
+			var name, dataType, nullable string
+			if err := rows.Scan(&name, &dataType, &nullable); err != nil {
+				return fmt.Errorf("unable to parse row: %w", err)
+			}
+			schema.Columns = append(schema.Columns, ColumnSchema{Name: name, DataType: dataType, Nullable: strings.EqualFold(nullable, "YES")})
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("errors encountered during row iteration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return ResourceContents{}, fmt.Errorf("unable to describe table %q: %w", table, err)
+	}
+	if len(schema.Columns) == 0 {
+		return ResourceContents{}, fmt.Errorf("table %q does not exist in source %q", table, sourceName)
+	}
+	return NewTableSchemaContents(uri, schema)
+}
+
+// SQLSchemaQueries are the statements that describe the tables of a
+// database/sql backed source.
+type SQLSchemaQueries struct {
+	// ListTables returns a single column with the name of each table.
+	ListTables string
+	// ListColumns takes the table name as its only argument and returns the
+	// column name, data type and whether it is nullable ("YES" or "NO").
+	ListColumns string
+}
+
+// SQLSchemaQuerier runs the schema queries of a database/sql backed source.
+type SQLSchemaQuerier struct {
+	DB      *sql.DB
+	Queries SQLSchemaQueries
+}
+
+var _ SchemaQuerier = SQLSchemaQuerier{}
+
+func (q SQLSchemaQuerier) ListTables(ctx context.Context, scan func(Rows) error) error {
+	return q.query(ctx, q.Queries.ListTables, scan)
+}
+
+func (q SQLSchemaQuerier) ListColumns(ctx context.Context, table string, scan func(Rows) error) error {
+	return q.query(ctx, q.Queries.ListColumns, scan, table)
+}
+
+func (q SQLSchemaQuerier) query(ctx context.Context, statement string, scan func(Rows) error, args ...any) error {
+	rows, err := q.DB.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scan(rows)
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+)
+
+func TestParseResourceURI(t *testing.T) {
+	tcs := []struct {
+		desc       string
+		uri        string
+		source     string
+		collection string
+		item       string
+		isErr      bool
+	}{
+		{
+			desc:       "table",
+			uri:        sources.ResourceURI("my-pg-source", sources.CollectionTables, "public.users"),
+			source:     "my-pg-source",
+			collection: "tables",
+			item:       "public.users",
+		},
+		{
+			desc:       "item with slash",
+			uri:        "toolbox://sources/my-source/labels/a/b",
+			source:     "my-source",
+			collection: "labels",
+			item:       "a/b",
+		},
+		{
+			desc:  "wrong scheme",
+			uri:   "file://sources/my-source/tables/users",
+			isErr: true,
+		},
+		{
+			desc:  "missing item",
+			uri:   "toolbox://sources/my-source/tables/",
+			isErr: true,
+		},
+		{
+			desc:  "missing collection",
+			uri:   "toolbox://sources/my-source",
+			isErr: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			source, collection, item, err := sources.ParseResourceURI(tc.uri)
+			if tc.isErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if source != tc.source || collection != tc.collection || item != tc.item {
+				t.Fatalf("incorrect parse: got (%q, %q, %q), want (%q, %q, %q)", source, collection, item, tc.source, tc.collection, tc.item)
+			}
+		})
+	}
+}
+
+// fakeRows are rows of string values.
+type fakeRows struct {
+	rows [][]string
+	i    int
+}
+
+func (r *fakeRows) Next() bool {
+	r.i++
+	return r.i <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		*d.(*string) = r.rows[r.i-1][i]
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return nil
+}
+
+// fakeQuerier describes tables whose columns it holds.
+type fakeQuerier struct {
+	tables  []string
+	columns map[string][][]string
+}
+
+func (q fakeQuerier) ListTables(_ context.Context, scan func(sources.Rows) error) error {
+	rows := &fakeRows{}
+	for _, t := range q.tables {
+		rows.rows = append(rows.rows, []string{t})
+	}
+	return scan(rows)
+}
+
+func (q fakeQuerier) ListColumns(_ context.Context, table string, scan func(sources.Rows) error) error {
+	return scan(&fakeRows{rows: q.columns[table]})
+}
+
+func TestTableResources(t *testing.T) {
+	ctx := context.Background()
+	q := fakeQuerier{
+		tables:  []string{"public.users"},
+		columns: map[string][][]string{"public.users": {{"id", "integer", "NO"}, {"email", "text", "YES"}}},
+	}
+	resources, err := sources.ListTableResources(ctx, q, "my-pg-source", "postgres")
+	if err != nil {
+		t.Fatalf("unable to list resources: %s", err)
+	}
+	want := []sources.Resource{{
+		URI:         "toolbox://sources/my-pg-source/tables/public.users",
+		Name:        "public.users",
+		Description: `Schema of table "public.users" in postgres source "my-pg-source".`,
+		MimeType:    "application/json",
+	}}
+	if diff := cmp.Diff(want, resources); diff != "" {
+		t.Fatalf("incorrect resources (-want +got):\n%s", diff)
+	}
+
+	contents, err := sources.ReadTableResource(ctx, q, "my-pg-source", resources[0].URI)
+	if err != nil {
+		t.Fatalf("unable to read resource: %s", err)
+	}
+	wantText := `{"source":"my-pg-source","name":"public.users","columns":[{"name":"id","dataType":"integer","nullable":false},{"name":"email","dataType":"text","nullable":true}]}`
+	if contents.Text != wantText {
+		t.Fatalf("incorrect contents: got %s, want %s", contents.Text, wantText)
+	}
+
+	_, err = sources.ReadTableResource(ctx, q, "my-pg-source", sources.ResourceURI("my-pg-source", sources.CollectionTables, "public.orders"))
+	if err == nil || !strings.Contains(err.Error(), `table "public.orders" does not exist`) {
+		t.Fatalf("unexpected error of missing table: %v", err)
+	}
+	_, err = sources.ReadTableResource(ctx, q, "my-pg-source", sources.ResourceURI("other-source", sources.CollectionTables, "public.users"))
+	if err == nil || !strings.Contains(err.Error(), "does not belong to source") {
+		t.Fatalf("unexpected error of other source: %v", err)
+	}
+}
@@ -73,6 +73,7 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
 
 type Source struct {
 	Name    string `yaml:"name"`
@@ -93,6 +94,74 @@ func (s *Source) DatabaseDialect() string {
 	return s.Dialect
 }
 
+// schemaStatement returns an information_schema query for user tables in the
+// database dialect. The query takes the table schema as its first parameter,
+// followed by args.
+func (s *Source) schemaStatement(googleSQL, postgreSQL string, args ...any) spanner.Statement {
+	if s.Dialect == "postgresql" {
+		params := map[string]any{"p1": "public"}
+		for i, a := range args {
+			params[fmt.Sprintf("p%d", i+2)] = a
+		}
+		return spanner.Statement{SQL: postgreSQL, Params: params}
+	}
+	params := map[string]any{"schema": ""}
+	if len(args) > 0 {
+		params["table"] = args[0]
+	}
+	return spanner.Statement{SQL: googleSQL, Params: params}
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	stmt := s.schemaStatement(
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = @schema ORDER BY table_name",
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = $1 ORDER BY table_name",
+	)
+
+	var tables []string
+	err := s.Client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
+		var t string
+		if err := row.Columns(&t); err != nil {
+			return err
+		}
+		tables = append(tables, t)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tables: %w", err)
+	}
+	return sources.NewTableResources(s.Name, SourceKind, tables), nil
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	table, err := sources.TableFromResourceURI(s.Name, uri)
+	if err != nil {
+		return sources.ResourceContents{}, err
+	}
+	stmt := s.schemaStatement(
+		"SELECT column_name, spanner_type, is_nullable FROM information_schema.columns WHERE table_schema = @schema AND table_name = @table ORDER BY ordinal_position",
+		"SELECT column_name, spanner_type, is_nullable FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position",
+		table,
+	)
+
+	schema := sources.TableSchema{Source: s.Name, Name: table, Columns: []sources.ColumnSchema{}}
+	err = s.Client.Single().Query(ctx, stmt).Do(func(row *spanner.Row) error {
+		var name, dataType, nullable string
+		if err := row.Columns(&name, &dataType, &nullable); err != nil {
+			return err
+		}
+		schema.Columns = append(schema.Columns, sources.ColumnSchema{Name: name, DataType: dataType, Nullable: nullable == "YES"})
+		return nil
+	})
+	if err != nil {
+		return sources.ResourceContents{}, fmt.Errorf("unable to describe table %q: %w", table, err)
+	}
+	if len(schema.Columns) == 0 {
+		return sources.ResourceContents{}, fmt.Errorf("table %q does not exist in source %q", table, s.Name)
+	}
+	return sources.NewTableSchemaContents(uri, schema)
+}
+
 func initSpannerClient(ctx context.Context, tracer trace.Tracer, name, project, instance, dbname string) (*spanner.Client, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
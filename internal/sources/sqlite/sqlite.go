@@ -74,6 +74,9 @@ func (r Config) Initialize(ctx context.Context, tracer trace.Tracer) (sources.So
 }
 
 var _ sources.Source = &Source{}
+var _ sources.ResourceProvider = &Source{}
+var _ sources.HealthChecker = &Source{}
+var _ sources.PoolStatsProvider = &Source{}
 
 type Source struct {
 	Name string `yaml:"name"`
@@ -89,6 +92,32 @@ func (s *Source) SQLiteDB() *sql.DB {
 	return s.Db
 }
 
+func (s *Source) CheckHealth(ctx context.Context) error {
+	return s.Db.PingContext(ctx)
+}
+
+func (s *Source) PoolStats() sources.PoolStats {
+	return sources.SQLPoolStats(s.Db)
+}
+
+// SchemaQueries describe the tables of the SQLite database.
+var SchemaQueries = sources.SQLSchemaQueries{
+	ListTables: `SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%'
+		ORDER BY name`,
+	ListColumns: `SELECT name, type, CASE WHEN "notnull" = 0 THEN 'YES' ELSE 'NO' END
+		FROM pragma_table_info(?)
+		ORDER BY cid`,
+}
+
+func (s *Source) ListResources(ctx context.Context) ([]sources.Resource, error) {
+	return sources.ListTableResources(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: SchemaQueries}, s.Name, SourceKind)
+}
+
+func (s *Source) ReadResource(ctx context.Context, uri string) (sources.ResourceContents, error) {
+	return sources.ReadTableResource(ctx, sources.SQLSchemaQuerier{DB: s.Db, Queries: SchemaQueries}, s.Name, uri)
+}
+
 func initSQLiteConnection(ctx context.Context, tracer trace.Tracer, name, dbPath string) (*sql.DB, error) {
 	//nolint:all // Reassigned ctx
 	ctx, span := sources.InitConnectionSpan(ctx, tracer, SourceKind, name)
@@ -15,6 +15,7 @@
 package sqlite_test
 
 import (
+	"context"
 	"testing"
 
 	yaml "github.com/goccy/go-yaml"
@@ -23,6 +24,7 @@ import (
 	"github.com/googleapis/genai-toolbox/internal/sources"
 	"github.com/googleapis/genai-toolbox/internal/sources/sqlite"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestParseFromYamlSQLite(t *testing.T) {
@@ -64,3 +66,47 @@ func TestParseFromYamlSQLite(t *testing.T) {
 		})
 	}
 }
+
+func TestResourcesSQLite(t *testing.T) {
+	ctx := context.Background()
+	cfg := sqlite.Config{Name: "my-sqlite-db", Kind: sqlite.SourceKind, Database: ":memory:"}
+	src, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	s := src.(*sqlite.Source)
+	s.Db.SetMaxOpenConns(1)
+	if _, err := s.Db.ExecContext(ctx, "CREATE TABLE users (id INTEGER NOT NULL, name TEXT)"); err != nil {
+		t.Fatalf("unable to create table: %s", err)
+	}
+
+	got, err := s.ListResources(ctx)
+	if err != nil {
+		t.Fatalf("unable to list resources: %s", err)
+	}
+	uri := "toolbox://sources/my-sqlite-db/tables/users"
+	want := []sources.Resource{
+		{
+			URI:         uri,
+			Name:        "users",
+			Description: `Schema of table "users" in sqlite source "my-sqlite-db".`,
+			MimeType:    "application/json",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("incorrect resources (-want +got):\n%s", diff)
+	}
+
+	contents, err := s.ReadResource(ctx, uri)
+	if err != nil {
+		t.Fatalf("unable to read resource: %s", err)
+	}
+	wantText := `{"source":"my-sqlite-db","name":"users","columns":[{"name":"id","dataType":"INTEGER","nullable":false},{"name":"name","dataType":"TEXT","nullable":true}]}`
+	if contents.Text != wantText {
+		t.Fatalf("incorrect contents: got %s, want %s", contents.Text, wantText)
+	}
+
+	if _, err := s.ReadResource(ctx, "toolbox://sources/my-sqlite-db/tables/missing"); err == nil {
+		t.Fatalf("expected error reading missing table")
+	}
+}
@@ -31,17 +31,19 @@ const (
 	toolInvokeCountName = "toolbox.server.tool.invoke.count"
 	mcpSseCountName     = "toolbox.server.mcp.sse.count"
 	mcpPostCountName    = "toolbox.server.mcp.post.count"
+	mcpWsCountName      = "toolbox.server.mcp.websocket.count"
 )
 
 // Instrumentation defines the telemetry instrumentation for toolbox
 type Instrumentation struct {
-	Tracer     trace.Tracer
-	meter      metric.Meter
-	ToolsetGet metric.Int64Counter
-	ToolGet    metric.Int64Counter
-	ToolInvoke metric.Int64Counter
-	McpSse     metric.Int64Counter
-	McpPost    metric.Int64Counter
+	Tracer       trace.Tracer
+	meter        metric.Meter
+	ToolsetGet   metric.Int64Counter
+	ToolGet      metric.Int64Counter
+	ToolInvoke   metric.Int64Counter
+	McpSse       metric.Int64Counter
+	McpPost      metric.Int64Counter
+	McpWebSocket metric.Int64Counter
 }
 
 func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, error) {
@@ -96,14 +98,24 @@ func CreateTelemetryInstrumentation(versionString string) (*Instrumentation, err
 		return nil, fmt.Errorf("unable to create %s metric: %w", mcpPostCountName, err)
 	}
 
+	mcpWebSocket, err := meter.Int64Counter(
+		mcpWsCountName,
+		metric.WithDescription("Number of MCP WebSocket connection requests."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s metric: %w", mcpWsCountName, err)
+	}
+
 	instrumentation := &Instrumentation{
-		Tracer:     tracer,
-		meter:      meter,
-		ToolsetGet: toolsetGet,
-		ToolGet:    toolGet,
-		ToolInvoke: toolInvoke,
-		McpSse:     mcpSse,
-		McpPost:    mcpPost,
+		Tracer:       tracer,
+		meter:        meter,
+		ToolsetGet:   toolsetGet,
+		ToolGet:      toolGet,
+		ToolInvoke:   toolInvoke,
+		McpSse:       mcpSse,
+		McpPost:      mcpPost,
+		McpWebSocket: mcpWebSocket,
 	}
 	return instrumentation, nil
 }
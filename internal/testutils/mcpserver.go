@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// FakeMcpSessionId is the session id of the streamable HTTP transport of
+// FakeMcpServer.
+const FakeMcpSessionId = "fake-session"
+
+// FakeMcpServer is an in-process MCP server for the tests of MCP clients. It
+// serves the stdio transport with ServeStdio, and the streamable HTTP (at
+// `/mcp`) and SSE (at `/sse`) transports as an http.Handler. Its tools are
+// listed one per page.
+type FakeMcpServer struct {
+	// Tools are the definitions of the tools of the server.
+	Tools []map[string]any
+	// Call returns the result of a call of a tool. It returns the arguments
+	// of the call as text content if it is not set.
+	Call func(name string, arguments map[string]any) map[string]any
+
+	mu          sync.Mutex
+	sseSessions map[string]chan []byte
+}
+
+// Handle returns the response to a JSON-RPC message, or nil for notifications.
+func (s *FakeMcpServer) Handle(message []byte) []byte {
+	var req struct {
+		Id     any             `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &req); err != nil || req.Id == nil {
+		return nil
+	}
+	var result any
+	switch req.Method {
+	case "initialize":
+		result = map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "fake", "version": "0.0.0"},
+		}
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		i, _ := strconv.Atoi(params.Cursor)
+		res := map[string]any{"tools": []map[string]any{}}
+		if i < len(s.Tools) {
+			res["tools"] = s.Tools[i : i+1]
+		}
+		if i+1 < len(s.Tools) {
+			res["nextCursor"] = strconv.Itoa(i + 1)
+		}
+		result = res
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		if s.Call != nil {
+			result = s.Call(params.Name, params.Arguments)
+			break
+		}
+		b, _ := json.Marshal(params.Arguments)
+		result = map[string]any{"content": []map[string]any{{"type": "text", "text": string(b)}}}
+	default:
+		b, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      req.Id,
+			"error":   map[string]any{"code": -32601, "message": fmt.Sprintf("invalid method %s", req.Method)},
+		})
+		return b
+	}
+	b, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.Id, "result": result})
+	return b
+}
+
+// ServeStdio serves newline-delimited messages read from r until it ends.
+func (s *FakeMcpServer) ServeStdio(r io.Reader, w io.Writer) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if res := s.Handle(line); res != nil {
+				if _, err := w.Write(append(res, '\n')); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *FakeMcpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/mcp" && r.Method == http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.Unmarshal(body, &req)
+		if req.Method == "initialize" {
+			w.Header().Set("Mcp-Session-Id", FakeMcpSessionId)
+		} else if r.Header.Get("Mcp-Session-Id") != FakeMcpSessionId {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		res := s.Handle(body)
+		if res == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(res)
+	case r.URL.Path == "/mcp" && r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusOK)
+	case r.URL.Path == "/sse" && r.Method == http.MethodGet:
+		s.serveSse(w, r)
+	case r.URL.Path == "/message" && r.Method == http.MethodPost:
+		s.mu.Lock()
+		ch, ok := s.sseSessions[r.URL.Query().Get("sessionId")]
+		s.mu.Unlock()
+		if !ok {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if res := s.Handle(body); res != nil {
+			ch <- res
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSse announces the message endpoint of a new session, and streams the
+// responses to its messages.
+func (s *FakeMcpServer) serveSse(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.sseSessions == nil {
+		s.sseSessions = make(map[string]chan []byte)
+	}
+	id := strconv.Itoa(len(s.sseSessions))
+	ch := make(chan []byte, 16)
+	s.sseSessions[id] = ch
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprintf(w, "event: endpoint\ndata: /message?sessionId=%s\n\n", id)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case res := <-ch:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", res)
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
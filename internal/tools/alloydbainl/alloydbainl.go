@@ -17,6 +17,7 @@ package alloydbainl
 import (
 	"context"
 	"fmt"
+	"iter"
 	"strings"
 
 	yaml "github.com/goccy/go-yaml"
@@ -52,13 +53,15 @@ var _ compatibleSource = &alloydbpg.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	NLConfig           string           `yaml:"nlConfig" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	NLConfigParameters tools.Parameters `yaml:"nlConfigParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	NLConfig           string                 `yaml:"nlConfig" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	NLConfigParameters tools.Parameters       `yaml:"nlConfigParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -121,8 +124,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: cfg.NLConfigParameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	t := Tool{
@@ -141,7 +146,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -156,36 +161,50 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the natural language query and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	allParamValues := make([]any, len(sliceParams)+1)
-	allParamValues[0] = fmt.Sprintf("%s", sliceParams[0]) // nl_question
-	allParamValues[1] = t.NLConfig                        // nl_config
-	for i, param := range sliceParams[1:] {
-		allParamValues[i+2] = fmt.Sprintf("%s", param)
-	}
-
-	results, err := t.Pool.Query(ctx, t.Statement, allParamValues...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w. Query: %v , Values: %v", err, t.Statement, allParamValues)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	fields := results.FieldDescriptions()
+// InvokeStream runs the natural language query and yields its rows as they
+// are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		allParamValues := make([]any, len(sliceParams)+1)
+		allParamValues[0] = fmt.Sprintf("%s", sliceParams[0]) // nl_question
+		allParamValues[1] = t.NLConfig                        // nl_config
+		for i, param := range sliceParams[1:] {
+			allParamValues[i+2] = fmt.Sprintf("%s", param)
+		}
 
-	var out []any
-	for results.Next() {
-		v, err := results.Values()
+		results, err := t.Pool.Query(ctx, t.Statement, allParamValues...)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to execute query: %w. Query: %v , Values: %v", err, t.Statement, allParamValues))
+			return
+		}
+
+		defer results.Close()
+
+		fields := results.FieldDescriptions()
+		for results.Next() {
+			v, err := results.Values()
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, f := range fields {
+				vMap[f.Name] = v[i]
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		vMap := make(map[string]any)
-		for i, f := range fields {
-			vMap[f.Name] = v[i]
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
 		}
-		out = append(out, vMap)
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -17,6 +17,7 @@ package bigqueryexecutesql
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
 	yaml "github.com/goccy/go-yaml"
@@ -52,11 +53,13 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -84,8 +87,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -102,7 +107,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -114,39 +119,49 @@ type Tool struct {
 	mcpManifest  tools.McpManifest
 }
 
+// Invoke runs the SQL and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	sql, ok := sliceParams[0].(string)
-	if !ok {
-		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	query := t.Client.Query(sql)
-	query.Location = t.Client.Location
+// InvokeStream runs the SQL and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		sql, ok := sliceParams[0].(string)
+		if !ok {
+			yield(nil, fmt.Errorf("unable to get cast %s", sliceParams[0]))
+			return
+		}
 
-	it, err := query.Read(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+		query := t.Client.Query(sql)
+		query.Location = t.Client.Location
 
-	var out []any
-	for {
-		var row map[string]bigqueryapi.Value
-		err := it.Next(&row)
-		if err == iterator.Done {
-			break
-		}
+		it, err := query.Read(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for key, value := range row {
-			vMap[key] = value
+
+		for {
+			var row map[string]bigqueryapi.Value
+			err := it.Next(&row)
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to iterate through query results: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for key, value := range row {
+				vMap[key] = value
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		out = append(out, vMap)
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
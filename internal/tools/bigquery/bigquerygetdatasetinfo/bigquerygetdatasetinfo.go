@@ -54,11 +54,13 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -87,8 +89,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
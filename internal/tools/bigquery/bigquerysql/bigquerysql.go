@@ -17,6 +17,7 @@ package bigquerysql
 import (
 	"context"
 	"fmt"
+	"iter"
 	"strings"
 
 	bigqueryapi "cloud.google.com/go/bigquery"
@@ -53,14 +54,16 @@ var _ compatibleSource = &bigqueryds.Source{}
 var compatibleSources = [...]string{bigqueryds.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -86,9 +89,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -108,7 +114,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -124,70 +130,81 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	namedArgs := make([]bigqueryapi.QueryParameter, 0, len(params))
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	for _, p := range t.Parameters {
-		name := p.GetName()
-		value := paramsMap[name]
-
-		// BigQuery's QueryParameter only accepts typed slices as input
-		// This checks if the param is an array.
-		// If yes, convert []any to typed slice (e.g []string, []int)
-		switch arrayParam := value.(type) {
-		case []any:
-			var err error
-			itemType := p.McpManifest().Items.Type
-			value, err = convertAnySliceToTyped(arrayParam, itemType, name)
-			if err != nil {
-				return nil, fmt.Errorf("unable to convert []any to typed slice: %w", err)
-			}
+// InvokeStream runs the statement and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		namedArgs := make([]bigqueryapi.QueryParameter, 0, len(params))
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
 		}
 
-		if strings.Contains(t.Statement, "@"+name) {
-			namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
-				Name:  name,
-				Value: value,
-			})
-		} else {
-			namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
-				Value: value,
-			})
-		}
-	}
+		for _, p := range t.Parameters {
+			name := p.GetName()
+			value := paramsMap[name]
+
+			// BigQuery's QueryParameter only accepts typed slices as input
+			// This checks if the param is an array.
+			// If yes, convert []any to typed slice (e.g []string, []int)
+			switch arrayParam := value.(type) {
+			case []any:
+				var err error
+				itemType := p.McpManifest().Items.Type
+				value, err = convertAnySliceToTyped(arrayParam, itemType, name)
+				if err != nil {
+					yield(nil, fmt.Errorf("unable to convert []any to typed slice: %w", err))
+					return
+				}
+			}
 
-	query := t.Client.Query(newStatement)
-	query.Parameters = namedArgs
-	query.Location = t.Client.Location
+			if strings.Contains(t.Statement, "@"+name) {
+				namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
+					Name:  name,
+					Value: value,
+				})
+			} else {
+				namedArgs = append(namedArgs, bigqueryapi.QueryParameter{
+					Value: value,
+				})
+			}
+		}
 
-	it, err := query.Read(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+		query := t.Client.Query(newStatement)
+		query.Parameters = namedArgs
+		query.Location = t.Client.Location
 
-	var out []any
-	for {
-		var row map[string]bigqueryapi.Value
-		err := it.Next(&row)
-		if err == iterator.Done {
-			break
-		}
+		it, err := query.Read(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to iterate through query results: %w", err)
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for key, value := range row {
-			vMap[key] = value
+
+		for {
+			var row map[string]bigqueryapi.Value
+			err := it.Next(&row)
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to iterate through query results: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for key, value := range row {
+				vMap[key] = value
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		out = append(out, vMap)
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -51,14 +51,16 @@ var _ compatibleSource = &bigtabledb.Source{}
 var compatibleSources = [...]string{bigtabledb.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -85,8 +87,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: paramMcpManifest,
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
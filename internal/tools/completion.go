@@ -0,0 +1,166 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/googleapis/genai-toolbox/internal/util"
+)
+
+// Completer is an optional interface of tools and parameters that suggest
+// values for arguments, e.g. to autocomplete them in MCP clients.
+type Completer interface {
+	// Complete returns the values suggested for the argument, given its
+	// partial value and the values of the other arguments.
+	Complete(ctx context.Context, argument, value string, arguments map[string]string) ([]string, error)
+}
+
+// CompletionSource configures the tool whose results are the values suggested
+// for a parameter, e.g. a `bigquery-list-dataset-ids` tool for a dataset.
+type CompletionSource struct {
+	// Tool is the name of the tool that is invoked.
+	Tool string `yaml:"tool" validate:"required"`
+	// Column is the column of the results that holds the values. It is only
+	// set for tools that return rows rather than values.
+	Column string `yaml:"column"`
+}
+
+// Completion describes how the values of a parameter are suggested.
+type Completion struct {
+	// Completer suggests the values if the parameter is a Completer.
+	Completer Completer
+	// Source is the tool whose results are the suggested values otherwise.
+	Source *CompletionSource
+}
+
+// parameterCompletion returns the completion of a parameter, or nil if no
+// values are suggested for it.
+func parameterCompletion(p Parameter) *Completion {
+	var c Completion
+	if completer, ok := p.(Completer); ok {
+		c.Completer = completer
+	}
+	if s, ok := p.(interface{ GetCompletionSource() *CompletionSource }); ok {
+		c.Source = s.GetCompletionSource()
+	}
+	if c.Completer == nil && c.Source == nil {
+		return nil
+	}
+	return &c
+}
+
+// CompleteArgument returns the values suggested for an argument of c, a tool
+// or prompt. c suggests the values if it is a Completer, and completion, the
+// completion of the argument, does otherwise. Completion source tools are
+// only invoked if the request could invoke them through the toolset.
+func CompleteArgument(ctx context.Context, c any, completion *Completion, toolset Toolset, toolsMap map[string]Tool, argument, value string, arguments map[string]string) ([]string, error) {
+	if completer, ok := c.(Completer); ok {
+		return completer.Complete(ctx, argument, value, arguments)
+	}
+	return completion.Complete(ctx, toolset, toolsMap, argument, value, arguments)
+}
+
+// Complete returns the values suggested for the argument. Values of the
+// completion source are only suggested if they start with value. No values
+// are suggested for a nil Completion.
+func (c *Completion) Complete(ctx context.Context, toolset Toolset, toolsMap map[string]Tool, argument, value string, arguments map[string]string) ([]string, error) {
+	if c == nil {
+		return []string{}, nil
+	}
+	if c.Completer != nil {
+		return c.Completer.Complete(ctx, argument, value, arguments)
+	}
+	if c.Source == nil {
+		return []string{}, nil
+	}
+	candidates, err := c.Source.values(ctx, toolset, toolsMap, arguments)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(candidates))
+	for _, v := range candidates {
+		if strings.HasPrefix(v, value) {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// values invokes the tool of the completion source and returns its results.
+// The tool is authorized like the tools invoked through the toolset, so that
+// completions do not expose tools that the request can not invoke. The
+// arguments that are string parameters of the tool are passed to it, e.g.
+// the project of the datasets that are listed.
+func (s *CompletionSource) values(ctx context.Context, toolset Toolset, toolsMap map[string]Tool, arguments map[string]string) ([]string, error) {
+	tool, ok := toolsMap[s.Tool]
+	if !ok {
+		return nil, fmt.Errorf("completion source tool %q does not exist", s.Tool)
+	}
+	claims := util.ClaimsFromContext(ctx)
+	if err := toolset.Authorize(s.Tool, tool, claims); err != nil {
+		return nil, fmt.Errorf("unable to use completion source tool %q: %w", s.Tool, err)
+	}
+
+	data := make(map[string]any)
+	for _, p := range tool.Manifest().Parameters {
+		if v, ok := arguments[p.Name]; ok && p.Type == typeString {
+			data[p.Name] = v
+		}
+	}
+	params, err := tool.ParseParams(data, claims)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse parameters of completion source tool %q: %w", s.Tool, err)
+	}
+	results, err := tool.Invoke(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to invoke completion source tool %q: %w", s.Tool, err)
+	}
+
+	values := make([]string, 0, len(results))
+	for _, r := range results {
+		if s.Column != "" {
+			row, ok := r.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("completion source tool %q does not return rows", s.Tool)
+			}
+			r, ok = row[s.Column]
+			if !ok {
+				return nil, fmt.Errorf("completion source tool %q does not return column %q", s.Tool, s.Column)
+			}
+		}
+		if r == nil {
+			continue
+		}
+		values = append(values, fmt.Sprint(r))
+	}
+	return values, nil
+}
+
+// ValidateCompletionSources returns an error if the completion source of a
+// parameter is not a tool of toolsMap.
+func ValidateCompletionSources(params []ParameterManifest, toolsMap map[string]Tool) error {
+	for _, p := range params {
+		if p.Completion == nil || p.Completion.Source == nil {
+			continue
+		}
+		if _, ok := toolsMap[p.Completion.Source.Tool]; !ok {
+			return fmt.Errorf("completion source of parameter %q: tool %q does not exist", p.Name, p.Completion.Source.Tool)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+// listTool is a tool that returns its results, and records the parameters
+// that it is invoked with. It requires one of authRequired to be verified if
+// set.
+type listTool struct {
+	params       tools.Parameters
+	results      []any
+	got          *tools.ParamValues
+	authRequired []string
+}
+
+func (t listTool) Invoke(_ context.Context, params tools.ParamValues) ([]any, error) {
+	*t.got = params
+	return t.results, nil
+}
+
+func (t listTool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	return tools.ParseParams(t.params, data, claims)
+}
+
+func (t listTool) Manifest() tools.Manifest {
+	return tools.Manifest{Parameters: t.params.Manifest()}
+}
+
+func (t listTool) McpManifest() tools.McpManifest {
+	return tools.McpManifest{}
+}
+
+func (t listTool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.authRequired, verifiedAuthServices)
+}
+
+// newToolset returns a toolset of the tools.
+func newToolset(t *testing.T, toolsMap map[string]tools.Tool, toolNames ...string) tools.Toolset {
+	toolset, err := tools.ToolsetConfig{Name: "my_toolset", ToolNames: toolNames}.Initialize("0.0.0", toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	return toolset
+}
+
+// prefixCompleter suggests the argument name with the value as a prefix.
+type prefixCompleter struct{}
+
+func (prefixCompleter) Complete(_ context.Context, argument, value string, _ map[string]string) ([]string, error) {
+	return []string{value + argument}, nil
+}
+
+type completerParameter struct {
+	prefixCompleter
+	*tools.StringParameter
+}
+
+func TestCompleteArgument(t *testing.T) {
+	var got tools.ParamValues
+	toolsMap := map[string]tools.Tool{
+		"list_datasets": listTool{
+			params:  tools.Parameters{tools.NewStringParameterWithDefault("project", "", "the project")},
+			results: []any{"sales", "marketing", "sales_eu"},
+			got:     &got,
+		},
+		"list_tables": listTool{
+			params: tools.Parameters{},
+			results: []any{
+				map[string]any{"table_name": "orders"},
+				map[string]any{"table_name": "customers"},
+				map[string]any{"table_name": nil},
+			},
+			got: &got,
+		},
+	}
+
+	dataset := tools.NewStringParameter("dataset", "the dataset")
+	dataset.CompletionSource = &tools.CompletionSource{Tool: "list_datasets"}
+	table := tools.NewStringParameter("table", "the table")
+	table.CompletionSource = &tools.CompletionSource{Tool: "list_tables", Column: "table_name"}
+	params := tools.Parameters{
+		dataset,
+		table,
+		tools.NewStringParameter("plain", "no completion"),
+		completerParameter{StringParameter: tools.NewStringParameter("completed", "a completer")},
+	}
+	manifests := params.Manifest()
+	toolset := newToolset(t, toolsMap, "list_datasets", "list_tables")
+
+	tcs := []struct {
+		name      string
+		c         any
+		argument  string
+		value     string
+		arguments map[string]string
+		want      []string
+		wantParam tools.ParamValues
+	}{
+		{
+			name:      "values of completion source",
+			argument:  "dataset",
+			value:     "sa",
+			arguments: map[string]string{"project": "my-project", "table": "orders"},
+			want:      []string{"sales", "sales_eu"},
+			wantParam: tools.ParamValues{{Name: "project", Value: "my-project"}},
+		},
+		{
+			name:      "column of completion source",
+			argument:  "table",
+			want:      []string{"orders", "customers"},
+			wantParam: tools.ParamValues{},
+		},
+		{
+			name:     "no completion",
+			argument: "plain",
+			want:     []string{},
+		},
+		{
+			name:     "parameter completer",
+			argument: "completed",
+			value:    "a_",
+			want:     []string{"a_completed"},
+		},
+		{
+			name:     "tool completer",
+			c:        prefixCompleter{},
+			argument: "dataset",
+			value:    "b_",
+			want:     []string{"b_dataset"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			got = nil
+			var completion *tools.Completion
+			for _, m := range manifests {
+				if m.Name == tc.argument {
+					completion = m.Completion
+				}
+			}
+			values, err := tools.CompleteArgument(context.Background(), tc.c, completion, toolset, toolsMap, tc.argument, tc.value, tc.arguments)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, values); diff != "" {
+				t.Fatalf("incorrect values: diff %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantParam, got); diff != "" {
+				t.Fatalf("incorrect source tool parameters: diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailCompleteArgument(t *testing.T) {
+	var got tools.ParamValues
+	toolsMap := map[string]tools.Tool{
+		"list_datasets": listTool{params: tools.Parameters{}, results: []any{"sales"}, got: &got},
+		"list_secrets":  listTool{params: tools.Parameters{}, results: []any{"secret"}, got: &got, authRequired: []string{"my-auth"}},
+		"list_hidden":   listTool{params: tools.Parameters{}, results: []any{"hidden"}, got: &got},
+	}
+	toolset := newToolset(t, toolsMap, "list_datasets", "list_secrets")
+	tcs := []struct {
+		name   string
+		source tools.CompletionSource
+		err    string
+	}{
+		{
+			name:   "tool does not exist",
+			source: tools.CompletionSource{Tool: "list_tables"},
+			err:    `completion source tool "list_tables" does not exist`,
+		},
+		{
+			name:   "tool does not return rows",
+			source: tools.CompletionSource{Tool: "list_datasets", Column: "dataset_id"},
+			err:    `completion source tool "list_datasets" does not return rows`,
+		},
+		{
+			name:   "tool outside of the toolset",
+			source: tools.CompletionSource{Tool: "list_hidden"},
+			err:    `tool with name "list_hidden" is not part of toolset "my_toolset"`,
+		},
+		{
+			name:   "unauthorized tool",
+			source: tools.CompletionSource{Tool: "list_secrets"},
+			err:    `unable to use completion source tool "list_secrets": tool invocation not authorized`,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			completion := &tools.Completion{Source: &tc.source}
+			_, err := tools.CompleteArgument(context.Background(), nil, completion, toolset, toolsMap, "dataset", "", nil)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestValidateCompletionSources(t *testing.T) {
+	dataset := tools.NewStringParameter("dataset", "the dataset")
+	dataset.CompletionSource = &tools.CompletionSource{Tool: "list_datasets"}
+	params := tools.Parameters{dataset}.Manifest()
+
+	if err := tools.ValidateCompletionSources(params, map[string]tools.Tool{"list_datasets": listTool{}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	err := tools.ValidateCompletionSources(params, map[string]tools.Tool{})
+	want := `completion source of parameter "dataset": tool "list_datasets" does not exist`
+	if err == nil || err.Error() != want {
+		t.Fatalf("unexpected error: got %v, want %q", err, want)
+	}
+}
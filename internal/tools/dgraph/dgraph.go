@@ -51,15 +51,17 @@ var _ compatibleSource = &dgraph.Source{}
 var compatibleSources = [...]string{dgraph.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Statement    string           `yaml:"statement" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	IsQuery      bool             `yaml:"isQuery"`
-	Timeout      string           `yaml:"timeout"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Statement    string                 `yaml:"statement" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	IsQuery      bool                   `yaml:"isQuery"`
+	Timeout      string                 `yaml:"timeout"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -84,8 +86,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: cfg.Parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -50,19 +50,21 @@ func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.T
 }
 
 type Config struct {
-	Name         string            `yaml:"name" validate:"required"`
-	Kind         string            `yaml:"kind" validate:"required"`
-	Source       string            `yaml:"source" validate:"required"`
-	Description  string            `yaml:"description" validate:"required"`
-	AuthRequired []string          `yaml:"authRequired"`
-	Path         string            `yaml:"path" validate:"required"`
-	Method       tools.HTTPMethod  `yaml:"method" validate:"required"`
-	Headers      map[string]string `yaml:"headers"`
-	RequestBody  string            `yaml:"requestBody"`
-	PathParams   tools.Parameters  `yaml:"pathParams"`
-	QueryParams  tools.Parameters  `yaml:"queryParams"`
-	BodyParams   tools.Parameters  `yaml:"bodyParams"`
-	HeaderParams tools.Parameters  `yaml:"headerParams"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Path         string                 `yaml:"path" validate:"required"`
+	Method       tools.HTTPMethod       `yaml:"method" validate:"required"`
+	Headers      map[string]string      `yaml:"headers"`
+	RequestBody  string                 `yaml:"requestBody"`
+	PathParams   tools.Parameters       `yaml:"pathParams"`
+	QueryParams  tools.Parameters       `yaml:"queryParams"`
+	BodyParams   tools.Parameters       `yaml:"bodyParams"`
+	HeaderParams tools.Parameters       `yaml:"headerParams"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -153,8 +155,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: paramMcpManifest,
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -0,0 +1,304 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mcpsrc "github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+const kind string = "mcp-proxy"
+
+func init() {
+	if !tools.Register(kind, newConfig) {
+		panic(fmt.Sprintf("tool kind %q already registered", kind))
+	}
+}
+
+func newConfig(ctx context.Context, name string, decoder *yaml.Decoder) (tools.ToolConfig, error) {
+	actual := Config{Name: name}
+	if err := decoder.DecodeContext(ctx, &actual); err != nil {
+		return nil, err
+	}
+	return actual, nil
+}
+
+type Config struct {
+	Name   string `yaml:"name" validate:"required"`
+	Kind   string `yaml:"kind" validate:"required"`
+	Source string `yaml:"source" validate:"required"`
+	// Tool is the name of the upstream tool, which is exposed under the name
+	// of the config. All upstream tools are exposed if it is not set.
+	Tool string `yaml:"tool"`
+	// Prefix is prepended to the names of the upstream tools when all of them
+	// are exposed.
+	Prefix string `yaml:"prefix"`
+	// Description replaces the description of the upstream tool.
+	Description  string   `yaml:"description"`
+	AuthRequired []string `yaml:"authRequired"`
+	// Title and Annotations replace the title and the annotations of the
+	// upstream tools. Title is only set if a single tool is exposed.
+	Title       string                 `yaml:"title"`
+	Annotations *tools.ToolAnnotations `yaml:"annotations"`
+}
+
+// validate interface
+var _ tools.MultiToolConfig = Config{}
+
+func (cfg Config) ToolConfigKind() string {
+	return kind
+}
+
+// Initialize initializes the tool that exposes a single upstream tool.
+func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error) {
+	if cfg.Tool == "" {
+		return nil, fmt.Errorf("tool is required to initialize a single %q tool", kind)
+	}
+	ts, err := cfg.InitializeTools(srcs)
+	if err != nil {
+		return nil, err
+	}
+	return ts[cfg.Name], nil
+}
+
+// InitializeTools initializes the tools that expose either the configured
+// upstream tool, or all upstream tools.
+func (cfg Config) InitializeTools(srcs map[string]sources.Source) (map[string]tools.Tool, error) {
+	// verify source exists
+	rawS, ok := srcs[cfg.Source]
+	if !ok {
+		return nil, fmt.Errorf("no source named %q configured", cfg.Source)
+	}
+
+	// verify the source is compatible
+	s, ok := rawS.(*mcpsrc.Source)
+	if !ok {
+		return nil, fmt.Errorf("invalid source for %q tool: source kind must be `%s`", kind, mcpsrc.SourceKind)
+	}
+
+	if cfg.AuthRequired == nil {
+		cfg.AuthRequired = []string{}
+	}
+
+	if cfg.Tool != "" {
+		if cfg.Prefix != "" {
+			return nil, fmt.Errorf("prefix can only be set if all upstream tools are exposed")
+		}
+		idx := slices.IndexFunc(s.Tools, func(t mcpsrc.Tool) bool { return t.Name == cfg.Tool })
+		if idx < 0 {
+			return nil, fmt.Errorf("upstream MCP server of source %q has no tool named %q", cfg.Source, cfg.Tool)
+		}
+		t, err := newTool(cfg.Name, s.Tools[idx], s, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]tools.Tool{cfg.Name: t}, nil
+	}
+
+	if cfg.Description != "" {
+		return nil, fmt.Errorf("description can only be set if a single upstream tool is exposed")
+	}
+	if cfg.Title != "" {
+		return nil, fmt.Errorf("title can only be set if a single upstream tool is exposed")
+	}
+	ts := make(map[string]tools.Tool, len(s.Tools))
+	for _, upstream := range s.Tools {
+		name := cfg.Prefix + upstream.Name
+		t, err := newTool(name, upstream, s, cfg)
+		if err != nil {
+			return nil, err
+		}
+		ts[name] = t
+	}
+	return ts, nil
+}
+
+// schema is the subset of JSON Schema that is converted to parameter manifests.
+type schema struct {
+	Type        any                `json:"type"`
+	Description string             `json:"description"`
+	Properties  map[string]*schema `json:"properties"`
+	Items       *schema            `json:"items"`
+	Required    []string           `json:"required"`
+}
+
+// schemaType returns the type of a JSON Schema, the first type for schemas
+// of several types. Untyped schemas are strings.
+func schemaType(s *schema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if v, ok := v.(string); ok && v != "null" {
+				return v
+			}
+		}
+	}
+	return "string"
+}
+
+// parameterManifest converts the JSON Schema of a property to its parameter
+// manifest.
+func parameterManifest(name string, s *schema, required bool) tools.ParameterManifest {
+	m := tools.ParameterManifest{
+		Name:         name,
+		Type:         schemaType(s),
+		Required:     required,
+		Description:  s.Description,
+		AuthServices: []string{},
+	}
+	if s.Items != nil {
+		items := parameterManifest("", s.Items, required)
+		m.Items = &items
+	}
+	return m
+}
+
+func newTool(name string, upstream mcpsrc.Tool, s *mcpsrc.Source, cfg Config) (Tool, error) {
+	var input schema
+	if len(upstream.InputSchema) > 0 {
+		if err := json.Unmarshal(upstream.InputSchema, &input); err != nil {
+			return Tool{}, fmt.Errorf("invalid input schema of upstream tool %q: %w", upstream.Name, err)
+		}
+	}
+	paramManifest := make([]tools.ParameterManifest, 0, len(input.Properties))
+	for _, p := range slices.Sorted(maps.Keys(input.Properties)) {
+		paramManifest = append(paramManifest, parameterManifest(p, input.Properties[p], slices.Contains(input.Required, p)))
+	}
+
+	description := upstream.Description
+	if cfg.Description != "" {
+		description = cfg.Description
+	}
+	inputSchema := tools.McpToolsSchema{
+		Type:       "object",
+		Properties: map[string]tools.ParameterMcpManifest{},
+		Required:   []string{},
+		Raw:        upstream.InputSchema,
+	}
+	mcpManifest := tools.McpManifest{
+		Name:        name,
+		Title:       upstream.Title,
+		Description: description,
+		InputSchema: inputSchema,
+	}
+	if len(upstream.Annotations) > 0 {
+		var annotations tools.ToolAnnotations
+		if err := json.Unmarshal(upstream.Annotations, &annotations); err == nil {
+			mcpManifest.Annotations = &annotations
+		}
+	}
+	if cfg.Title != "" {
+		mcpManifest.Title = cfg.Title
+	}
+	if cfg.Annotations != nil {
+		mcpManifest.Annotations = cfg.Annotations
+	}
+
+	return Tool{
+		Name:         name,
+		Kind:         kind,
+		UpstreamName: upstream.Name,
+		AuthRequired: cfg.AuthRequired,
+		Source:       s,
+		required:     input.Required,
+		manifest:     tools.Manifest{Description: description, Parameters: paramManifest, AuthRequired: cfg.AuthRequired},
+		mcpManifest:  mcpManifest,
+	}, nil
+}
+
+// validate interface
+var _ tools.Tool = Tool{}
+
+type Tool struct {
+	Name         string   `yaml:"name"`
+	Kind         string   `yaml:"kind"`
+	UpstreamName string   `yaml:"upstreamName"`
+	AuthRequired []string `yaml:"authRequired"`
+
+	Source      *mcpsrc.Source
+	required    []string
+	manifest    tools.Manifest
+	mcpManifest tools.McpManifest
+}
+
+// Invoke calls the upstream tool. Text content is returned as the JSON value
+// it holds, or as a string if it is not JSON, and other content as is.
+func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
+	res, err := t.Source.CallTool(ctx, t.UpstreamName, params.AsMap())
+	if err != nil {
+		return nil, fmt.Errorf("unable to call upstream tool %q: %w", t.UpstreamName, err)
+	}
+	if res.IsError {
+		texts := make([]string, 0, len(res.Content))
+		for _, c := range res.Content {
+			if c.Type == "text" {
+				texts = append(texts, c.Text)
+			}
+		}
+		return nil, fmt.Errorf("upstream tool %q failed: %s", t.UpstreamName, strings.Join(texts, "\n"))
+	}
+
+	out := make([]any, 0, len(res.Content))
+	for _, c := range res.Content {
+		if c.Type != "text" {
+			out = append(out, c.Raw)
+			continue
+		}
+		var v any
+		if err := json.Unmarshal([]byte(c.Text), &v); err != nil {
+			v = c.Text
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// ParseParams passes the arguments through, since they are validated by the
+// upstream server. Only the presence of the required arguments is checked.
+func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
+	for _, name := range t.required {
+		if _, ok := data[name]; !ok {
+			return nil, fmt.Errorf("parameter %q is required", name)
+		}
+	}
+	params := make(tools.ParamValues, 0, len(data))
+	for _, name := range slices.Sorted(maps.Keys(data)) {
+		params = append(params, tools.ParamValue{Name: name, Value: data[name]})
+	}
+	return params, nil
+}
+
+func (t Tool) Manifest() tools.Manifest {
+	return t.manifest
+}
+
+func (t Tool) McpManifest() tools.McpManifest {
+	return t.mcpManifest
+}
+
+func (t Tool) Authorized(verifiedAuthServices []string) bool {
+	return tools.IsAuthorized(t.AuthRequired, verifiedAuthServices)
+}
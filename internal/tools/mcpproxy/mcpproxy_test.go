@@ -0,0 +1,308 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcpproxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/server"
+	"github.com/googleapis/genai-toolbox/internal/sources"
+	mcpsrc "github.com/googleapis/genai-toolbox/internal/sources/mcp"
+	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+	"github.com/googleapis/genai-toolbox/internal/tools/mcpproxy"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestParseFromYamlMcpProxy(t *testing.T) {
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	tcs := []struct {
+		desc string
+		in   string
+		want server.ToolConfigs
+	}{
+		{
+			desc: "single tool",
+			in: `
+			tools:
+				search_issues:
+					kind: mcp-proxy
+					source: my-mcp-server
+					tool: search
+					description: Search the issues of the tracker.
+					authRequired:
+						- my-google-auth-service
+			`,
+			want: server.ToolConfigs{
+				"search_issues": mcpproxy.Config{
+					Name:         "search_issues",
+					Kind:         "mcp-proxy",
+					Source:       "my-mcp-server",
+					Tool:         "search",
+					Description:  "Search the issues of the tracker.",
+					AuthRequired: []string{"my-google-auth-service"},
+				},
+			},
+		},
+		{
+			desc: "all tools",
+			in: `
+			tools:
+				tracker:
+					kind: mcp-proxy
+					source: my-mcp-server
+					prefix: tracker_
+			`,
+			want: server.ToolConfigs{
+				"tracker": mcpproxy.Config{
+					Name:         "tracker",
+					Kind:         "mcp-proxy",
+					Source:       "my-mcp-server",
+					Prefix:       "tracker_",
+					AuthRequired: []string{},
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := struct {
+				Tools server.ToolConfigs `yaml:"tools"`
+			}{}
+			// Parse contents
+			err := yaml.UnmarshalContext(ctx, testutils.FormatYaml(tc.in), &got)
+			if err != nil {
+				t.Fatalf("unable to unmarshal: %s", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Tools); diff != "" {
+				t.Fatalf("incorrect parse: diff %v", diff)
+			}
+		})
+	}
+}
+
+// newSource returns a source connected to fake, an upstream MCP server.
+func newSource(t *testing.T, fake *testutils.FakeMcpServer) map[string]sources.Source {
+	ts := httptest.NewServer(fake)
+	t.Cleanup(ts.Close)
+	ctx, err := testutils.ContextWithNewLogger()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cfg := mcpsrc.Config{Name: "my-mcp-server", Kind: mcpsrc.SourceKind, Transport: mcpsrc.TransportStreamableHTTP, URL: ts.URL + "/mcp", Timeout: "10s"}
+	s, err := cfg.Initialize(ctx, noop.NewTracerProvider().Tracer(""))
+	if err != nil {
+		t.Fatalf("unable to initialize source: %s", err)
+	}
+	t.Cleanup(func() { _ = s.(*mcpsrc.Source).Close() })
+	return map[string]sources.Source{"my-mcp-server": s}
+}
+
+var searchSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"query":  map[string]any{"type": "string", "description": "The query.", "minLength": 1},
+		"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	},
+	"required": []any{"query"},
+}
+
+func TestInitializeTools(t *testing.T) {
+	fake := &testutils.FakeMcpServer{
+		Tools: []map[string]any{
+			{"name": "search", "title": "Search", "description": "Searches issues.", "inputSchema": searchSchema, "annotations": map[string]any{"readOnlyHint": true}},
+			{"name": "create", "description": "Creates an issue.", "inputSchema": map[string]any{"type": "object"}},
+		},
+	}
+	srcs := newSource(t, fake)
+
+	// a single tool is renamed
+	cfg := mcpproxy.Config{Name: "search_issues", Kind: "mcp-proxy", Source: "my-mcp-server", Tool: "search"}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	wantManifest := tools.Manifest{
+		Description: "Searches issues.",
+		Parameters: []tools.ParameterManifest{
+			{Name: "labels", Type: "array", Description: "", AuthServices: []string{}, Items: &tools.ParameterManifest{Type: "string", AuthServices: []string{}}},
+			{Name: "query", Type: "string", Required: true, Description: "The query.", AuthServices: []string{}},
+		},
+		AuthRequired: []string{},
+	}
+	if diff := cmp.Diff(wantManifest, tool.Manifest()); diff != "" {
+		t.Fatalf("unexpected manifest (-want +got):\n%s", diff)
+	}
+	// the input schema is passed through as is
+	b, err := json.Marshal(tool.McpManifest())
+	if err != nil {
+		t.Fatalf("unable to marshal mcp manifest: %s", err)
+	}
+	var gotMcp map[string]any
+	if err := json.Unmarshal(b, &gotMcp); err != nil {
+		t.Fatalf("unable to unmarshal mcp manifest: %s", err)
+	}
+	wantMcp := map[string]any{
+		"name":        "search_issues",
+		"title":       "Search",
+		"description": "Searches issues.",
+		"inputSchema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query":  map[string]any{"type": "string", "description": "The query.", "minLength": float64(1)},
+				"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			"required": []any{"query"},
+		},
+		"annotations": map[string]any{"readOnlyHint": true},
+	}
+	if diff := cmp.Diff(wantMcp, gotMcp); diff != "" {
+		t.Fatalf("unexpected mcp manifest (-want +got):\n%s", diff)
+	}
+
+	// all tools are exposed with a prefix
+	cfg = mcpproxy.Config{Name: "tracker", Kind: "mcp-proxy", Source: "my-mcp-server", Prefix: "tracker_"}
+	ts, err := cfg.InitializeTools(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tools: %s", err)
+	}
+	if diff := cmp.Diff([]string{"tracker_create", "tracker_search"}, slices.Sorted(maps.Keys(ts))); diff != "" {
+		t.Fatalf("unexpected tools (-want +got):\n%s", diff)
+	}
+	if got := ts["tracker_create"].McpManifest().Name; got != "tracker_create" {
+		t.Fatalf("unexpected mcp manifest name: %s", got)
+	}
+
+	// the title and the annotations of the upstream tool are replaced
+	readOnly := false
+	cfg = mcpproxy.Config{Name: "search_issues", Kind: "mcp-proxy", Source: "my-mcp-server", Tool: "search", Title: "Search Issues", Annotations: &tools.ToolAnnotations{ReadOnlyHint: &readOnly}}
+	tool, err = cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+	if m := tool.McpManifest(); m.Title != "Search Issues" || m.Annotations == nil || *m.Annotations.ReadOnlyHint {
+		t.Fatalf("unexpected title and annotations: %+v", m)
+	}
+}
+
+func TestFailInitializeTools(t *testing.T) {
+	srcs := newSource(t, &testutils.FakeMcpServer{
+		Tools: []map[string]any{{"name": "search", "inputSchema": searchSchema}},
+	})
+	tcs := []struct {
+		desc string
+		cfg  mcpproxy.Config
+		err  string
+	}{
+		{
+			desc: "unknown source",
+			cfg:  mcpproxy.Config{Source: "other", Tool: "search"},
+			err:  `no source named "other" configured`,
+		},
+		{
+			desc: "unknown tool",
+			cfg:  mcpproxy.Config{Source: "my-mcp-server", Tool: "delete"},
+			err:  `upstream MCP server of source "my-mcp-server" has no tool named "delete"`,
+		},
+		{
+			desc: "prefix of a single tool",
+			cfg:  mcpproxy.Config{Source: "my-mcp-server", Tool: "search", Prefix: "tracker_"},
+			err:  "prefix can only be set if all upstream tools are exposed",
+		},
+		{
+			desc: "description of all tools",
+			cfg:  mcpproxy.Config{Source: "my-mcp-server", Description: "Tools of the tracker."},
+			err:  "description can only be set if a single upstream tool is exposed",
+		},
+		{
+			desc: "title of all tools",
+			cfg:  mcpproxy.Config{Source: "my-mcp-server", Title: "Tracker"},
+			err:  "title can only be set if a single upstream tool is exposed",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.cfg.Name, tc.cfg.Kind = "my-tool", "mcp-proxy"
+			_, err := tc.cfg.InitializeTools(srcs)
+			if err == nil {
+				t.Fatalf("expect initialization to fail")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("unexpected error: got %q, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	fake := &testutils.FakeMcpServer{
+		Tools: []map[string]any{{"name": "search", "inputSchema": searchSchema}},
+		Call: func(name string, arguments map[string]any) map[string]any {
+			if arguments["query"] == "fail" {
+				return map[string]any{"content": []map[string]any{{"type": "text", "text": "invalid query"}}, "isError": true}
+			}
+			return map[string]any{"content": []map[string]any{
+				{"type": "text", "text": `[{"id":1}]`},
+				{"type": "text", "text": "1 issue found"},
+				{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+			}}
+		},
+	}
+	srcs := newSource(t, fake)
+	cfg := mcpproxy.Config{Name: "search_issues", Kind: "mcp-proxy", Source: "my-mcp-server", Tool: "search"}
+	tool, err := cfg.Initialize(srcs)
+	if err != nil {
+		t.Fatalf("unable to initialize tool: %s", err)
+	}
+
+	if _, err := tool.ParseParams(map[string]any{"labels": []any{"bug"}}, nil); err == nil || err.Error() != `parameter "query" is required` {
+		t.Fatalf("unexpected error for a missing argument: %v", err)
+	}
+	params, err := tool.ParseParams(map[string]any{"query": "crash", "labels": []any{"bug"}}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	got, err := tool.Invoke(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unable to invoke tool: %s", err)
+	}
+	want := []any{
+		[]any{map[string]any{"id": float64(1)}},
+		"1 issue found",
+		map[string]any{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected results (-want +got):\n%s", diff)
+	}
+
+	params, err = tool.ParseParams(map[string]any{"query": "fail"}, nil)
+	if err != nil {
+		t.Fatalf("unable to parse params: %s", err)
+	}
+	if _, err := tool.Invoke(context.Background(), params); err == nil || !strings.Contains(err.Error(), "invalid query") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
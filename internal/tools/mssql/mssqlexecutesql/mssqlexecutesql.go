@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -53,11 +54,13 @@ var _ compatibleSource = &mssql.Source{}
 var compatibleSources = [...]string{cloudsqlmssql.SourceKind, mssql.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -85,8 +88,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -103,7 +108,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -116,52 +121,61 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the SQL and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	sql, ok := sliceParams[0].(string)
-	if !ok {
-		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
-	}
-	results, err := t.Pool.QueryContext(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
-	defer results.Close()
-
-	cols, err := results.Columns()
-	// If Columns() errors, it might be a DDL/DML without an OUTPUT clause.
-	// We proceed, and results.Err() will catch actual query execution errors.
-	// 'out' will remain nil if cols is empty or err is not nil here.
-
-	var out []any
-	if err == nil && len(cols) > 0 {
-		// create an array of values for each column, which can be re-used to scan each row
-		rawValues := make([]any, len(cols))
-		values := make([]any, len(cols))
-		for i := range rawValues {
-			values[i] = &rawValues[i]
-		}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-		for results.Next() {
-			scanErr := results.Scan(values...)
-			if scanErr != nil {
-				return nil, fmt.Errorf("unable to parse row: %w", scanErr)
+// InvokeStream runs the SQL and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		sql, ok := sliceParams[0].(string)
+		if !ok {
+			yield(nil, fmt.Errorf("unable to get cast %s", sliceParams[0]))
+			return
+		}
+		results, err := t.Pool.QueryContext(ctx, sql)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
+		defer results.Close()
+
+		cols, err := results.Columns()
+		// If Columns() errors, it might be a DDL/DML without an OUTPUT clause.
+		// We proceed, and results.Err() will catch actual query execution errors.
+		// No rows are yielded if cols is empty or err is not nil here.
+		if err == nil && len(cols) > 0 {
+			// create an array of values for each column, which can be re-used to scan each row
+			rawValues := make([]any, len(cols))
+			values := make([]any, len(cols))
+			for i := range rawValues {
+				values[i] = &rawValues[i]
 			}
-			vMap := make(map[string]any)
-			for i, name := range cols {
-				vMap[name] = rawValues[i]
+
+			for results.Next() {
+				scanErr := results.Scan(values...)
+				if scanErr != nil {
+					yield(nil, fmt.Errorf("unable to parse row: %w", scanErr))
+					return
+				}
+				vMap := make(map[string]any)
+				for i, name := range cols {
+					vMap[name] = rawValues[i]
+				}
+				if !yield(vMap, nil) {
+					return
+				}
 			}
-			out = append(out, vMap)
 		}
-	}
 
-	// Check for errors from iterating over rows or from the query execution itself.
-	// results.Close() is handled by defer.
-	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during query execution or row processing: %w", err)
+		// Check for errors from iterating over rows or from the query execution itself.
+		// results.Close() is handled by defer.
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("errors encountered during query execution or row processing: %w", err))
+		}
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
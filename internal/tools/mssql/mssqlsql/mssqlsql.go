@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 	"strings"
 
 	yaml "github.com/goccy/go-yaml"
@@ -54,14 +55,16 @@ var _ compatibleSource = &mssql.Source{}
 var compatibleSources = [...]string{cloudsqlmssql.SourceKind, mssql.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -87,9 +90,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -109,7 +115,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -125,71 +131,85 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
-
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	namedArgs := make([]any, 0, len(newParams))
-	// To support both named args (e.g @id) and positional args (e.g @p1), check
-	// if arg name is contained in the statement.
-	for _, p := range t.Parameters {
-		name := p.GetName()
-		value := paramsMap[name]
-		if strings.Contains(newStatement, "@"+name) {
-			namedArgs = append(namedArgs, sql.Named(name, value))
-		} else {
-			namedArgs = append(namedArgs, value)
+// InvokeStream runs the statement and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
 		}
-	}
 
-	rows, err := t.Db.QueryContext(ctx, newStatement, namedArgs...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract standard params %w", err))
+			return
+		}
 
-	cols, err := rows.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("unable to fetch column types: %w", err)
-	}
+		namedArgs := make([]any, 0, len(newParams))
+		// To support both named args (e.g @id) and positional args (e.g @p1), check
+		// if arg name is contained in the statement.
+		for _, p := range t.Parameters {
+			name := p.GetName()
+			value := paramsMap[name]
+			if strings.Contains(newStatement, "@"+name) {
+				namedArgs = append(namedArgs, sql.Named(name, value))
+			} else {
+				namedArgs = append(namedArgs, value)
+			}
+		}
 
-	// create an array of values for each column, which can be re-used to scan each row
-	rawValues := make([]any, len(cols))
-	values := make([]any, len(cols))
-	for i := range rawValues {
-		values[i] = &rawValues[i]
-	}
+		rows, err := t.Db.QueryContext(ctx, newStatement, namedArgs...)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
+		defer rows.Close()
 
-	var out []any
-	for rows.Next() {
-		err = rows.Scan(values...)
+		cols, err := rows.Columns()
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to fetch column types: %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for i, name := range cols {
-			vMap[name] = rawValues[i]
+
+		// create an array of values for each column, which can be re-used to scan each row
+		rawValues := make([]any, len(cols))
+		values := make([]any, len(cols))
+		for i := range rawValues {
+			values[i] = &rawValues[i]
 		}
-		out = append(out, vMap)
-	}
-	err = rows.Close()
-	if err != nil {
-		return nil, fmt.Errorf("unable to close rows: %w", err)
-	}
 
-	// Check if error occurred during iteration
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
+		for rows.Next() {
+			err = rows.Scan(values...)
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, name := range cols {
+				vMap[name] = rawValues[i]
+			}
+			if !yield(vMap, nil) {
+				return
+			}
+		}
+		err = rows.Close()
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to close rows: %w", err))
+			return
+		}
 
-	return out, nil
+		// Check if error occurred during iteration
+		if err := rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
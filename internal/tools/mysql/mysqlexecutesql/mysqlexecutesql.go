@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -53,11 +54,13 @@ var _ compatibleSource = &mysql.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -85,8 +88,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -103,7 +108,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -116,67 +121,80 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the SQL and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	sql, ok := sliceParams[0].(string)
-	if !ok {
-		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	results, err := t.Pool.QueryContext(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
-	defer results.Close()
+// InvokeStream runs the SQL and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		sql, ok := sliceParams[0].(string)
+		if !ok {
+			yield(nil, fmt.Errorf("unable to get cast %s", sliceParams[0]))
+			return
+		}
 
-	cols, err := results.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
-	}
+		results, err := t.Pool.QueryContext(ctx, sql)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
 
-	// create an array of values for each column, which can be re-used to scan each row
-	rawValues := make([]any, len(cols))
-	values := make([]any, len(cols))
-	for i := range rawValues {
-		values[i] = &rawValues[i]
-	}
+		defer results.Close()
 
-	colTypes, err := results.ColumnTypes()
-	if err != nil {
-		return nil, fmt.Errorf("unable to get column types: %w", err)
-	}
+		cols, err := results.Columns()
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to retrieve rows column name: %w", err))
+			return
+		}
 
-	var out []any
-	for results.Next() {
-		err := results.Scan(values...)
+		// create an array of values for each column, which can be re-used to scan each row
+		rawValues := make([]any, len(cols))
+		values := make([]any, len(cols))
+		for i := range rawValues {
+			values[i] = &rawValues[i]
+		}
+
+		colTypes, err := results.ColumnTypes()
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to get column types: %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for i, name := range cols {
-			val := rawValues[i]
-			if val == nil {
-				vMap[name] = nil
-				continue
-			}
 
-			// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
-			// we'll need to cast it back to string
-			switch colTypes[i].DatabaseTypeName() {
-			case "TEXT", "VARCHAR", "NVARCHAR":
-				vMap[name] = string(val.([]byte))
-			default:
-				vMap[name] = val
+		for results.Next() {
+			err := results.Scan(values...)
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, name := range cols {
+				val := rawValues[i]
+				if val == nil {
+					vMap[name] = nil
+					continue
+				}
+
+				// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
+				// we'll need to cast it back to string
+				switch colTypes[i].DatabaseTypeName() {
+				case "TEXT", "VARCHAR", "NVARCHAR":
+					vMap[name] = string(val.([]byte))
+				default:
+					vMap[name] = val
+				}
+			}
+			if !yield(vMap, nil) {
+				return
 			}
 		}
-		out = append(out, vMap)
-	}
 
-	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("errors encountered during row iteration: %w", err))
+		}
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
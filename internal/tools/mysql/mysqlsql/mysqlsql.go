@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -53,14 +54,16 @@ var _ compatibleSource = &mysql.Source{}
 var compatibleSources = [...]string{cloudsqlmysql.SourceKind, mysql.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -86,9 +89,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -108,7 +114,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -124,73 +130,87 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
-	}
+// InvokeStream runs the statement and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
+		}
 
-	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract standard params %w", err))
+			return
+		}
 
-	cols, err := results.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("unable to retrieve rows column name: %w", err)
-	}
+		sliceParams := newParams.AsSlice()
+		results, err := t.Pool.QueryContext(ctx, newStatement, sliceParams...)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
 
-	// create an array of values for each column, which can be re-used to scan each row
-	rawValues := make([]any, len(cols))
-	values := make([]any, len(cols))
-	for i := range rawValues {
-		values[i] = &rawValues[i]
-	}
-	defer results.Close()
+		defer results.Close()
 
-	colTypes, err := results.ColumnTypes()
-	if err != nil {
-		return nil, fmt.Errorf("unable to get column types: %w", err)
-	}
+		cols, err := results.Columns()
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to retrieve rows column name: %w", err))
+			return
+		}
+
+		// create an array of values for each column, which can be re-used to scan each row
+		rawValues := make([]any, len(cols))
+		values := make([]any, len(cols))
+		for i := range rawValues {
+			values[i] = &rawValues[i]
+		}
 
-	var out []any
-	for results.Next() {
-		err := results.Scan(values...)
+		colTypes, err := results.ColumnTypes()
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to get column types: %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for i, name := range cols {
-			val := rawValues[i]
-			if val == nil {
-				vMap[name] = nil
-				continue
-			}
 
-			// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
-			// we'll need to cast it back to string
-			switch colTypes[i].DatabaseTypeName() {
-			case "TEXT", "VARCHAR", "NVARCHAR":
-				vMap[name] = string(val.([]byte))
-			default:
-				vMap[name] = val
+		for results.Next() {
+			err := results.Scan(values...)
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, name := range cols {
+				val := rawValues[i]
+				if val == nil {
+					vMap[name] = nil
+					continue
+				}
+
+				// mysql driver return []uint8 type for "TEXT", "VARCHAR", and "NVARCHAR"
+				// we'll need to cast it back to string
+				switch colTypes[i].DatabaseTypeName() {
+				case "TEXT", "VARCHAR", "NVARCHAR":
+					vMap[name] = string(val.([]byte))
+				default:
+					vMap[name] = val
+				}
+			}
+			if !yield(vMap, nil) {
+				return
 			}
 		}
-		out = append(out, vMap)
-	}
 
-	if err := results.Err(); err != nil {
-		return nil, fmt.Errorf("errors encountered during row iteration: %w", err)
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("errors encountered during row iteration: %w", err))
+		}
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
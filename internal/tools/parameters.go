@@ -259,6 +259,17 @@ type McpToolsSchema struct {
 	Type       string                          `json:"type"`
 	Properties map[string]ParameterMcpManifest `json:"properties"`
 	Required   []string                        `json:"required"`
+	// Raw is an input schema that is passed through as is, e.g. the schema of
+	// a tool of an upstream MCP server. It replaces the other fields if set.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (s McpToolsSchema) MarshalJSON() ([]byte, error) {
+	if len(s.Raw) > 0 {
+		return s.Raw, nil
+	}
+	type schema McpToolsSchema
+	return json.Marshal(schema(s))
 }
 
 // Parameters is a type used to allow unmarshal a list of parameters
@@ -365,7 +376,9 @@ func parseParamFromDelayedUnmarshaler(ctx context.Context, u *util.DelayedUnmars
 func (ps Parameters) Manifest() []ParameterManifest {
 	rtn := make([]ParameterManifest, 0, len(ps))
 	for _, p := range ps {
-		rtn = append(rtn, p.Manifest())
+		m := p.Manifest()
+		m.Completion = parameterCompletion(p)
+		rtn = append(rtn, m)
 	}
 	return rtn
 }
@@ -398,6 +411,9 @@ type ParameterManifest struct {
 	Description  string             `json:"description"`
 	AuthServices []string           `json:"authSources"`
 	Items        *ParameterManifest `json:"items,omitempty"`
+	// Completion describes how values are suggested for the parameter. It is
+	// not sent to clients.
+	Completion *Completion `json:"-"`
 }
 
 // ParameterMcpManifest represents properties when served as part of a ToolMcpManifest.
@@ -414,6 +430,8 @@ type CommonParameter struct {
 	Desc         string             `yaml:"description" validate:"required"`
 	AuthServices []ParamAuthService `yaml:"authServices"`
 	AuthSources  []ParamAuthService `yaml:"authSources"` // Deprecated: Kept for compatibility.
+	// CompletionSource is the tool whose results are suggested as values.
+	CompletionSource *CompletionSource `yaml:"completionSource"`
 }
 
 // GetName returns the name specified for the Parameter.
@@ -426,6 +444,11 @@ func (p *CommonParameter) GetType() string {
 	return p.Type
 }
 
+// GetCompletionSource returns the completion source of the Parameter, if any.
+func (p *CommonParameter) GetCompletionSource() *CompletionSource {
+	return p.CompletionSource
+}
+
 // McpManifest returns the MCP manifest for the Parameter.
 func (p *CommonParameter) McpManifest() ParameterMcpManifest {
 	return ParameterMcpManifest{
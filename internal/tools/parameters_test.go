@@ -219,6 +219,30 @@ func TestParametersMarshal(t *testing.T) {
 				tools.NewArrayParameterWithDefault("my_array", []any{1.0, 1.1}, "this param is an array of floats", tools.NewFloatParameter("my_float", "float item")),
 			},
 		},
+		{
+			name: "string with completion source",
+			in: []map[string]any{
+				{
+					"name":        "my_dataset",
+					"type":        "string",
+					"description": "this param is a dataset",
+					"completionSource": map[string]any{
+						"tool":   "list_datasets",
+						"column": "dataset_id",
+					},
+				},
+			},
+			want: tools.Parameters{
+				&tools.StringParameter{
+					CommonParameter: tools.CommonParameter{
+						Name:             "my_dataset",
+						Type:             "string",
+						Desc:             "this param is a dataset",
+						CompletionSource: &tools.CompletionSource{Tool: "list_datasets", Column: "dataset_id"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
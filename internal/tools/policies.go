@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AuthPolicy is a predicate on the claims of the auth services that verified
+// the tokens of a request. A policy either checks a claim of an auth service
+// with one of `equals`, `in`, `regex` and `exists`, or combines other
+// policies with `all` or `any`. The policies combined by `all` and `any`
+// inherit the auth service of the combining policy, unless they set theirs.
+type AuthPolicy struct {
+	AuthService string `yaml:"authService"`
+	// Claim is the name of the claim. Nested claims are named by the path of
+	// their keys joined by dots, e.g. `realm_access.roles`.
+	Claim string `yaml:"claim"`
+	// Equals matches if the claim, or any value of a list claim, is equal to
+	// the value. Values are compared by their string representation.
+	Equals any `yaml:"equals"`
+	// In matches if the claim, or any value of a list claim, is one of the
+	// values.
+	In []any `yaml:"in"`
+	// Regex matches if the whole claim, or any whole value of a list claim,
+	// matches the regular expression, which is anchored at both ends.
+	Regex string `yaml:"regex"`
+	// Exists matches if the claim is present, or absent if false.
+	Exists *bool        `yaml:"exists"`
+	All    []AuthPolicy `yaml:"all"`
+	Any    []AuthPolicy `yaml:"any"`
+
+	regex *regexp.Regexp
+}
+
+// AuthPolicies are the policies of a tool or toolset, which all must match
+// for a request to use it.
+type AuthPolicies []AuthPolicy
+
+// Initialize validates the policies and compiles their regular expressions.
+func (ps AuthPolicies) Initialize() error {
+	for i := range ps {
+		if err := ps[i].initialize(""); err != nil {
+			return fmt.Errorf("invalid auth policy: %w", err)
+		}
+	}
+	return nil
+}
+
+// Allows returns true if all policies match the claims, keyed by the name of
+// the auth service that verified them.
+func (ps AuthPolicies) Allows(claimsFromAuth map[string]map[string]any) bool {
+	for _, p := range ps {
+		if !p.matches(claimsFromAuth) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *AuthPolicy) initialize(authService string) error {
+	if p.AuthService == "" {
+		p.AuthService = authService
+	}
+	set := 0
+	for _, isSet := range []bool{p.Equals != nil, p.In != nil, p.Regex != "", p.Exists != nil, p.All != nil, p.Any != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("a policy must set exactly one of equals, in, regex, exists, all and any")
+	}
+
+	children := p.All
+	if p.Any != nil {
+		children = p.Any
+	}
+	if children != nil {
+		if p.Claim != "" {
+			return fmt.Errorf("all and any policies can not set a claim")
+		}
+		if len(children) == 0 {
+			return fmt.Errorf("all and any policies must combine at least one policy")
+		}
+		for i := range children {
+			if err := children[i].initialize(p.AuthService); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if p.AuthService == "" || p.Claim == "" {
+		return fmt.Errorf("a policy on a claim must set authService and claim")
+	}
+	if p.Regex != "" {
+		// the regex matches whole claims, so that e.g. `admin` does not match
+		// `not-admin`
+		re, err := regexp.Compile(`^(?:` + p.Regex + `)$`)
+		if err != nil {
+			return fmt.Errorf("unable to compile regex of claim %q: %w", p.Claim, err)
+		}
+		p.regex = re
+	}
+	return nil
+}
+
+func (p AuthPolicy) matches(claimsFromAuth map[string]map[string]any) bool {
+	switch {
+	case p.All != nil:
+		for _, c := range p.All {
+			if !c.matches(claimsFromAuth) {
+				return false
+			}
+		}
+		return true
+	case p.Any != nil:
+		for _, c := range p.Any {
+			if c.matches(claimsFromAuth) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := lookupClaim(claimsFromAuth[p.AuthService], p.Claim)
+	if p.Exists != nil {
+		return ok == *p.Exists
+	}
+	if !ok {
+		return false
+	}
+	values := []any{value}
+	if l, isList := value.([]any); isList {
+		values = l
+	}
+	for _, v := range values {
+		if p.matchesValue(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p AuthPolicy) matchesValue(v any) bool {
+	s := fmt.Sprint(v)
+	switch {
+	case p.Equals != nil:
+		return s == fmt.Sprint(p.Equals)
+	case p.In != nil:
+		for _, want := range p.In {
+			if s == fmt.Sprint(want) {
+				return true
+			}
+		}
+		return false
+	case p.regex != nil:
+		return p.regex.MatchString(s)
+	}
+	return false
+}
+
+// lookupClaim returns the value of a claim, which is looked up by the path of
+// its name unless a claim has the full name.
+func lookupClaim(claims map[string]any, name string) (any, bool) {
+	if claims == nil {
+		return nil, false
+	}
+	if v, ok := claims[name]; ok {
+		return v, true
+	}
+	var v any = claims
+	for _, key := range strings.Split(name, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// AuthPolicyToolConfig is a ToolConfig with the auth policies of its tools.
+type AuthPolicyToolConfig struct {
+	ToolConfig
+	AuthPolicies AuthPolicies
+}
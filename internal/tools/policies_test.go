@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestAuthPolicies(t *testing.T) {
+	claims := map[string]map[string]any{
+		"my-auth": {
+			"sub":                      "some_user",
+			"email":                    "user@example.com",
+			"email_verified":           true,
+			"level":                    float64(3),
+			"groups":                   []any{"finance", "analysts"},
+			"realm_access":             map[string]any{"roles": []any{"admin"}},
+			"https://example.com/team": "data",
+		},
+		"other-auth": {
+			"sub": "other_user",
+		},
+	}
+	testCases := []struct {
+		name     string
+		policies string
+		want     bool
+	}{
+		{
+			name:     "no policies",
+			policies: `[]`,
+			want:     true,
+		},
+		{
+			name:     "equals",
+			policies: `[{authService: my-auth, claim: email, equals: user@example.com}]`,
+			want:     true,
+		},
+		{
+			name:     "equals bool and number",
+			policies: `[{authService: my-auth, claim: email_verified, equals: true}, {authService: my-auth, claim: level, equals: 3}]`,
+			want:     true,
+		},
+		{
+			name:     "equals list claim",
+			policies: `[{authService: my-auth, claim: groups, equals: finance}]`,
+			want:     true,
+		},
+		{
+			name:     "not equals",
+			policies: `[{authService: my-auth, claim: groups, equals: hr}]`,
+			want:     false,
+		},
+		{
+			name:     "claim of other auth service",
+			policies: `[{authService: other-auth, claim: groups, equals: finance}]`,
+			want:     false,
+		},
+		{
+			name:     "claim of unverified auth service",
+			policies: `[{authService: some-auth, claim: sub, exists: true}]`,
+			want:     false,
+		},
+		{
+			name:     "in",
+			policies: `[{authService: my-auth, claim: groups, in: [hr, analysts]}]`,
+			want:     true,
+		},
+		{
+			name:     "not in",
+			policies: `[{authService: my-auth, claim: sub, in: [other_user]}]`,
+			want:     false,
+		},
+		{
+			name:     "regex",
+			policies: `[{authService: my-auth, claim: email, regex: '.*@example\.com'}]`,
+			want:     true,
+		},
+		{
+			name:     "regex not matching",
+			policies: `[{authService: my-auth, claim: email, regex: '.*@google\.com'}]`,
+			want:     false,
+		},
+		{
+			name:     "regex matching part of the claim",
+			policies: `[{authService: my-auth, claim: email, regex: 'example\.com'}]`,
+			want:     false,
+		},
+		{
+			name:     "regex alternatives",
+			policies: `[{authService: my-auth, claim: groups, regex: 'hr|fin.*'}]`,
+			want:     true,
+		},
+		{
+			name:     "exists",
+			policies: `[{authService: my-auth, claim: email, exists: true}, {authService: my-auth, claim: phone, exists: false}]`,
+			want:     true,
+		},
+		{
+			name:     "nested claim",
+			policies: `[{authService: my-auth, claim: realm_access.roles, equals: admin}]`,
+			want:     true,
+		},
+		{
+			name:     "claim named with dots",
+			policies: `[{authService: my-auth, claim: https://example.com/team, equals: data}]`,
+			want:     true,
+		},
+		{
+			name:     "all policies must match",
+			policies: `[{authService: my-auth, claim: groups, equals: finance}, {authService: my-auth, claim: groups, equals: hr}]`,
+			want:     false,
+		},
+		{
+			name:     "any",
+			policies: `[{any: [{authService: other-auth, claim: sub, equals: some_user}, {authService: my-auth, claim: sub, equals: some_user}]}]`,
+			want:     true,
+		},
+		{
+			name:     "any not matching",
+			policies: `[{any: [{authService: other-auth, claim: sub, equals: some_user}, {authService: my-auth, claim: groups, equals: hr}]}]`,
+			want:     false,
+		},
+		{
+			name:     "all with inherited auth service",
+			policies: `[{authService: my-auth, all: [{claim: groups, equals: finance}, {any: [{claim: level, in: [2, 3]}, {claim: realm_access.roles, equals: admin}]}]}]`,
+			want:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var policies tools.AuthPolicies
+			if err := yaml.Unmarshal([]byte(tc.policies), &policies); err != nil {
+				t.Fatalf("unable to parse policies: %s", err)
+			}
+			if err := policies.Initialize(); err != nil {
+				t.Fatalf("unable to initialize policies: %s", err)
+			}
+			if got := policies.Allows(claims); got != tc.want {
+				t.Fatalf("unexpected result: got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthPoliciesErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policies string
+		wantErr  string
+	}{
+		{
+			name:     "no predicate",
+			policies: `[{authService: my-auth, claim: sub}]`,
+			wantErr:  "exactly one of",
+		},
+		{
+			name:     "several predicates",
+			policies: `[{authService: my-auth, claim: sub, equals: a, in: [a]}]`,
+			wantErr:  "exactly one of",
+		},
+		{
+			name:     "missing auth service",
+			policies: `[{claim: sub, equals: a}]`,
+			wantErr:  "must set authService and claim",
+		},
+		{
+			name:     "missing claim",
+			policies: `[{authService: my-auth, equals: a}]`,
+			wantErr:  "must set authService and claim",
+		},
+		{
+			name:     "invalid regex",
+			policies: `[{authService: my-auth, claim: sub, regex: '('}]`,
+			wantErr:  "unable to compile regex",
+		},
+		{
+			name:     "empty combinator",
+			policies: `[{any: []}]`,
+			wantErr:  "must combine at least one policy",
+		},
+		{
+			name:     "combinator with claim",
+			policies: `[{authService: my-auth, claim: sub, all: [{equals: a}]}]`,
+			wantErr:  "can not set a claim",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var policies tools.AuthPolicies
+			if err := yaml.Unmarshal([]byte(tc.policies), &policies); err != nil {
+				t.Fatalf("unable to parse policies: %s", err)
+			}
+			err := policies.Initialize()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("unexpected error: got %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ package postgresexecutesql
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -55,11 +56,13 @@ var _ compatibleSource = &postgres.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -87,8 +90,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -105,7 +110,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -118,34 +123,48 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the SQL and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	sql, ok := sliceParams[0].(string)
-	if !ok {
-		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
-	}
-
-	results, err := t.Pool.Query(ctx, sql)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	fields := results.FieldDescriptions()
+// InvokeStream runs the SQL and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		sql, ok := sliceParams[0].(string)
+		if !ok {
+			yield(nil, fmt.Errorf("unable to get cast %s", sliceParams[0]))
+			return
+		}
 
-	var out []any
-	for results.Next() {
-		v, err := results.Values()
+		results, err := t.Pool.Query(ctx, sql)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
+
+		defer results.Close()
+
+		fields := results.FieldDescriptions()
+		for results.Next() {
+			v, err := results.Values()
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, f := range fields {
+				vMap[f.Name] = v[i]
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		vMap := make(map[string]any)
-		for i, f := range fields {
-			vMap[f.Name] = v[i]
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
 		}
-		out = append(out, vMap)
 	}
-
-	return out, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
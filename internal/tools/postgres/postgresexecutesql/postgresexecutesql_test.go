@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/googleapis/genai-toolbox/internal/server"
 	"github.com/googleapis/genai-toolbox/internal/testutils"
+	"github.com/googleapis/genai-toolbox/internal/tools"
 	"github.com/googleapis/genai-toolbox/internal/tools/postgres/postgresexecutesql"
 )
 
@@ -29,6 +30,7 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
+	destructive := true
 	tcs := []struct {
 		desc string
 		in   string
@@ -56,6 +58,30 @@ func TestParseFromYamlExecuteSql(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "with title and annotations",
+			in: `
+			tools:
+				example_tool:
+					kind: postgres-execute-sql
+					source: my-instance
+					description: some description
+					title: Execute SQL
+					annotations:
+						destructiveHint: true
+			`,
+			want: server.ToolConfigs{
+				"example_tool": postgresexecutesql.Config{
+					Name:         "example_tool",
+					Kind:         "postgres-execute-sql",
+					Source:       "my-instance",
+					Description:  "some description",
+					AuthRequired: []string{},
+					Title:        "Execute SQL",
+					Annotations:  &tools.ToolAnnotations{DestructiveHint: &destructive},
+				},
+			},
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.desc, func(t *testing.T) {
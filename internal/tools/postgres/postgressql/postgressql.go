@@ -17,6 +17,7 @@ package postgressql
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -55,14 +56,16 @@ var _ compatibleSource = &postgres.Source{}
 var compatibleSources = [...]string{alloydbpg.SourceKind, cloudsqlpg.SourceKind, postgres.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -88,9 +91,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -110,7 +116,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -126,39 +132,54 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
-
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
-	}
-	sliceParams := newParams.AsSlice()
-	results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	fields := results.FieldDescriptions()
+// InvokeStream runs the statement and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
+		}
 
-	var out []any
-	for results.Next() {
-		v, err := results.Values()
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
+			yield(nil, fmt.Errorf("unable to extract standard params %w", err))
+			return
 		}
-		vMap := make(map[string]any)
-		for i, f := range fields {
-			vMap[f.Name] = v[i]
+		sliceParams := newParams.AsSlice()
+		results, err := t.Pool.Query(ctx, newStatement, sliceParams...)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
 		}
-		out = append(out, vMap)
-	}
 
-	return out, nil
+		defer results.Close()
+
+		fields := results.FieldDescriptions()
+		for results.Next() {
+			v, err := results.Values()
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+			vMap := make(map[string]any)
+			for i, f := range fields {
+				vMap[f.Name] = v[i]
+			}
+			if !yield(vMap, nil) {
+				return
+			}
+		}
+		if err := results.Err(); err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+		}
+	}
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -17,6 +17,7 @@ package spannerexecutesql
 import (
 	"context"
 	"fmt"
+	"iter"
 
 	"cloud.google.com/go/spanner"
 	yaml "github.com/goccy/go-yaml"
@@ -53,12 +54,14 @@ var _ compatibleSource = &spannerdb.Source{}
 var compatibleSources = [...]string{spannerdb.SourceKind}
 
 type Config struct {
-	Name         string   `yaml:"name" validate:"required"`
-	Kind         string   `yaml:"kind" validate:"required"`
-	Source       string   `yaml:"source" validate:"required"`
-	Description  string   `yaml:"description" validate:"required"`
-	AuthRequired []string `yaml:"authRequired"`
-	ReadOnly     bool     `yaml:"readOnly"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	ReadOnly     bool                   `yaml:"readOnly"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -86,8 +89,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -106,7 +111,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name         string           `yaml:"name"`
@@ -121,60 +126,85 @@ type Tool struct {
 }
 
 // processRows iterates over the spanner.RowIterator and converts each row to a map[string]any.
-func processRows(iter *spanner.RowIterator) ([]any, error) {
-	var out []any
-	defer iter.Stop()
-
-	for {
-		row, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
-		}
+func processRows(it *spanner.RowIterator) ([]any, error) {
+	return tools.CollectResults(rows(it))
+}
 
-		vMap := make(map[string]any)
-		cols := row.ColumnNames()
-		for i, c := range cols {
-			vMap[c] = row.ColumnValue(i)
+// rows yields the rows of the spanner.RowIterator as a map[string]any, and
+// stops the iterator once done.
+func rows(it *spanner.RowIterator) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		defer it.Stop()
+		for {
+			row, err := it.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+
+			vMap := make(map[string]any)
+			cols := row.ColumnNames()
+			for i, c := range cols {
+				vMap[c] = row.ColumnValue(i)
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		out = append(out, vMap)
 	}
-	return out, nil
 }
 
+// Invoke runs the SQL and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	sliceParams := params.AsSlice()
-	sql, ok := sliceParams[0].(string)
-	if !ok {
-		return nil, fmt.Errorf("unable to get cast %s", sliceParams[0])
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	var results []any
-	var opErr error
-	stmt := spanner.Statement{SQL: sql}
+// InvokeStream runs the SQL and yields its rows. The rows of read-only
+// statements are yielded as they are read. The rows of read-write
+// transactions are yielded once the transaction commits, since Spanner may
+// run the transaction again if it aborts.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		sliceParams := params.AsSlice()
+		sql, ok := sliceParams[0].(string)
+		if !ok {
+			yield(nil, fmt.Errorf("unable to get cast %s", sliceParams[0]))
+			return
+		}
+		stmt := spanner.Statement{SQL: sql}
+
+		if t.ReadOnly {
+			for row, err := range rows(t.Client.Single().Query(ctx, stmt)) {
+				if err != nil {
+					yield(nil, fmt.Errorf("unable to execute query: %w", err))
+					return
+				}
+				if !yield(row, nil) {
+					return
+				}
+			}
+			return
+		}
 
-	if t.ReadOnly {
-		iter := t.Client.Single().Query(ctx, stmt)
-		results, opErr = processRows(iter)
-	} else {
-		_, opErr = t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+		var results []any
+		_, err := t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
 			var err error
-			iter := txn.Query(ctx, stmt)
-			results, err = processRows(iter)
-			if err != nil {
-				return err
-			}
-			return nil
+			results, err = processRows(txn.Query(ctx, stmt))
+			return err
 		})
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
+		for _, row := range results {
+			if !yield(row, nil) {
+				return
+			}
+		}
 	}
-
-	if opErr != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", opErr)
-	}
-
-	return results, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
@@ -17,6 +17,7 @@ package spannersql
 import (
 	"context"
 	"fmt"
+	"iter"
 	"strings"
 
 	"cloud.google.com/go/spanner"
@@ -54,15 +55,17 @@ var _ compatibleSource = &spannerdb.Source{}
 var compatibleSources = [...]string{spannerdb.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	ReadOnly           bool             `yaml:"readOnly"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	ReadOnly           bool                   `yaml:"readOnly"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -88,9 +91,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -112,7 +118,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -141,71 +147,100 @@ func getMapParams(params tools.ParamValues, dialect string) (map[string]interfac
 }
 
 // processRows iterates over the spanner.RowIterator and converts each row to a map[string]any.
-func processRows(iter *spanner.RowIterator) ([]any, error) {
-	var out []any
-	defer iter.Stop()
-
-	for {
-		row, err := iter.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("unable to parse row: %w", err)
-		}
+func processRows(it *spanner.RowIterator) ([]any, error) {
+	return tools.CollectResults(rows(it))
+}
 
-		vMap := make(map[string]any)
-		cols := row.ColumnNames()
-		for i, c := range cols {
-			vMap[c] = row.ColumnValue(i)
+// rows yields the rows of the spanner.RowIterator as a map[string]any, and
+// stops the iterator once done.
+func rows(it *spanner.RowIterator) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		defer it.Stop()
+		for {
+			row, err := it.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("unable to parse row: %w", err))
+				return
+			}
+
+			vMap := make(map[string]any)
+			cols := row.ColumnNames()
+			for i, c := range cols {
+				vMap[c] = row.ColumnValue(i)
+			}
+			if !yield(vMap, nil) {
+				return
+			}
 		}
-		out = append(out, vMap)
 	}
-	return out, nil
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
-	}
-	mapParams, err := getMapParams(newParams, t.dialect)
-	if err != nil {
-		return nil, fmt.Errorf("fail to get map params: %w", err)
-	}
+// InvokeStream runs the statement and yields its rows. The rows of read-only
+// statements are yielded as they are read. The rows of read-write
+// transactions are yielded once the transaction commits, since Spanner may
+// run the transaction again if it aborts.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
+		}
 
-	var results []any
-	var opErr error
-	stmt := spanner.Statement{
-		SQL:    newStatement,
-		Params: mapParams,
-	}
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract standard params %w", err))
+			return
+		}
+		mapParams, err := getMapParams(newParams, t.dialect)
+		if err != nil {
+			yield(nil, fmt.Errorf("fail to get map params: %w", err))
+			return
+		}
 
-	if t.ReadOnly {
-		iter := t.Client.Single().Query(ctx, stmt)
-		results, opErr = processRows(iter)
-	} else {
-		_, opErr = t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
-			iter := txn.Query(ctx, stmt)
-			results, err = processRows(iter)
-			if err != nil {
-				return err
+		stmt := spanner.Statement{
+			SQL:    newStatement,
+			Params: mapParams,
+		}
+
+		if t.ReadOnly {
+			for row, err := range rows(t.Client.Single().Query(ctx, stmt)) {
+				if err != nil {
+					yield(nil, fmt.Errorf("unable to execute client: %w", err))
+					return
+				}
+				if !yield(row, nil) {
+					return
+				}
 			}
-			return nil
-		})
-	}
+			return
+		}
 
-	if opErr != nil {
-		return nil, fmt.Errorf("unable to execute client: %w", opErr)
+		var results []any
+		_, err = t.Client.ReadWriteTransaction(ctx, func(ctx context.Context, txn *spanner.ReadWriteTransaction) error {
+			var err error
+			results, err = processRows(txn.Query(ctx, stmt))
+			return err
+		})
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute client: %w", err))
+			return
+		}
+		for _, row := range results {
+			if !yield(row, nil) {
+				return
+			}
+		}
 	}
-
-	return results, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
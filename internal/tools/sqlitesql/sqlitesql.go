@@ -18,6 +18,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"iter"
 
 	yaml "github.com/goccy/go-yaml"
 	"github.com/googleapis/genai-toolbox/internal/sources"
@@ -51,14 +52,16 @@ var _ compatibleSource = &sqlite.Source{}
 var compatibleSources = [...]string{sqlite.SourceKind}
 
 type Config struct {
-	Name               string           `yaml:"name" validate:"required"`
-	Kind               string           `yaml:"kind" validate:"required"`
-	Source             string           `yaml:"source" validate:"required"`
-	Description        string           `yaml:"description" validate:"required"`
-	Statement          string           `yaml:"statement" validate:"required"`
-	AuthRequired       []string         `yaml:"authRequired"`
-	Parameters         tools.Parameters `yaml:"parameters"`
-	TemplateParameters tools.Parameters `yaml:"templateParameters"`
+	Name               string                 `yaml:"name" validate:"required"`
+	Kind               string                 `yaml:"kind" validate:"required"`
+	Source             string                 `yaml:"source" validate:"required"`
+	Description        string                 `yaml:"description" validate:"required"`
+	Statement          string                 `yaml:"statement" validate:"required"`
+	AuthRequired       []string               `yaml:"authRequired"`
+	Parameters         tools.Parameters       `yaml:"parameters"`
+	TemplateParameters tools.Parameters       `yaml:"templateParameters"`
+	Title              string                 `yaml:"title"`
+	Annotations        *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -84,9 +87,12 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 	allParameters, paramManifest, paramMcpManifest := tools.ProcessParameters(cfg.TemplateParameters, cfg.Parameters)
 
 	mcpManifest := tools.McpManifest{
-		Name:        cfg.Name,
-		Description: cfg.Description,
-		InputSchema: paramMcpManifest,
+		Name:         cfg.Name,
+		Title:        cfg.Title,
+		Description:  cfg.Description,
+		InputSchema:  paramMcpManifest,
+		OutputSchema: tools.RowsOutputSchema,
+		Annotations:  cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -106,7 +112,7 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 }
 
 // validate interface
-var _ tools.Tool = Tool{}
+var _ tools.StreamingTool = Tool{}
 
 type Tool struct {
 	Name               string           `yaml:"name"`
@@ -122,70 +128,82 @@ type Tool struct {
 	mcpManifest tools.McpManifest
 }
 
+// Invoke runs the statement and returns all its rows.
 func (t Tool) Invoke(ctx context.Context, params tools.ParamValues) ([]any, error) {
-	paramsMap := params.AsMap()
-	newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract template params %w", err)
-	}
+	return tools.CollectResults(t.InvokeStream(ctx, params))
+}
 
-	newParams, err := tools.GetParams(t.Parameters, paramsMap)
-	if err != nil {
-		return nil, fmt.Errorf("unable to extract standard params %w", err)
-	}
+// InvokeStream runs the statement and yields its rows as they are read.
+func (t Tool) InvokeStream(ctx context.Context, params tools.ParamValues) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		paramsMap := params.AsMap()
+		newStatement, err := tools.ResolveTemplateParams(t.TemplateParameters, t.Statement, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract template params %w", err))
+			return
+		}
 
-	// Execute the SQL query with parameters
-	rows, err := t.Db.QueryContext(ctx, newStatement, newParams.AsSlice()...)
-	if err != nil {
-		return nil, fmt.Errorf("unable to execute query: %w", err)
-	}
-	defer rows.Close()
+		newParams, err := tools.GetParams(t.Parameters, paramsMap)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to extract standard params %w", err))
+			return
+		}
 
-	// Get column names
-	cols, err := rows.Columns()
-	if err != nil {
-		return nil, fmt.Errorf("unable to get column names: %w", err)
-	}
+		// Execute the SQL query with parameters
+		rows, err := t.Db.QueryContext(ctx, newStatement, newParams.AsSlice()...)
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to execute query: %w", err))
+			return
+		}
+		defer rows.Close()
 
-	values := make([]any, len(cols))
-	valuePtrs := make([]any, len(cols))
-	for i := range values {
-		valuePtrs[i] = &values[i]
-	}
+		// Get column names
+		cols, err := rows.Columns()
+		if err != nil {
+			yield(nil, fmt.Errorf("unable to get column names: %w", err))
+			return
+		}
 
-	// Prepare the result slice
-	var result []any
-	// Iterate through the rows
-	for rows.Next() {
-		// Scan the row into the value pointers
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("unable to scan row: %w", err)
+		values := make([]any, len(cols))
+		valuePtrs := make([]any, len(cols))
+		for i := range values {
+			valuePtrs[i] = &values[i]
 		}
 
-		// Create a map for this row
-		rowMap := make(map[string]interface{})
-		for i, col := range cols {
-			val := values[i]
-			// Handle nil values
-			if val == nil {
-				rowMap[col] = nil
-				continue
+		// Iterate through the rows
+		for rows.Next() {
+			// Scan the row into the value pointers
+			if err := rows.Scan(valuePtrs...); err != nil {
+				yield(nil, fmt.Errorf("unable to scan row: %w", err))
+				return
+			}
+
+			// Create a map for this row
+			rowMap := make(map[string]interface{})
+			for i, col := range cols {
+				val := values[i]
+				// Handle nil values
+				if val == nil {
+					rowMap[col] = nil
+					continue
+				}
+				// Store the value in the map
+				rowMap[col] = val
+			}
+			if !yield(rowMap, nil) {
+				return
 			}
-			// Store the value in the map
-			rowMap[col] = val
 		}
-		result = append(result, rowMap)
-	}
 
-	if err = rows.Close(); err != nil {
-		return nil, fmt.Errorf("unable to close rows: %w", err)
-	}
+		if err = rows.Close(); err != nil {
+			yield(nil, fmt.Errorf("unable to close rows: %w", err))
+			return
+		}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		if err = rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("error iterating rows: %w", err))
+		}
 	}
-
-	return result, nil
 }
 
 func (t Tool) ParseParams(data map[string]any, claims map[string]map[string]any) (tools.ParamValues, error) {
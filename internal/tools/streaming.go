@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"iter"
+)
+
+// StreamingTool is an optional interface of tools that yield their results
+// one at a time as they are produced, e.g. the rows of a query, so that they
+// can be streamed to clients without holding all of them in memory.
+type StreamingTool interface {
+	Tool
+	// InvokeStream invokes the tool and yields its results. An error is
+	// yielded at most once, as the last value of the sequence. Resources of
+	// the invocation are released if the caller stops the iteration early.
+	InvokeStream(context.Context, ParamValues) iter.Seq2[any, error]
+}
+
+// Stream returns the results of a tool as a sequence. The results of tools
+// that are not a StreamingTool are yielded once the invocation returns.
+func Stream(ctx context.Context, t Tool, params ParamValues) iter.Seq2[any, error] {
+	if st, ok := t.(StreamingTool); ok {
+		return st.InvokeStream(ctx, params)
+	}
+	return func(yield func(any, error) bool) {
+		results, err := t.Invoke(ctx, params)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, r := range results {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CollectResults returns all the results of a sequence, or its error. It is
+// used to implement Invoke for a StreamingTool.
+func CollectResults(results iter.Seq2[any, error]) ([]any, error) {
+	var out []any
+	for r, err := range results {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestStream(t *testing.T) {
+	var got tools.ParamValues
+	tool := listTool{results: []any{"a", "b", "c"}, got: &got}
+	params := tools.ParamValues{{Name: "p", Value: 1}}
+
+	var results []any
+	for r, err := range tools.Stream(context.Background(), tool, params) {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		results = append(results, r)
+		if len(results) == 2 {
+			break
+		}
+	}
+	if diff := cmp.Diff([]any{"a", "b"}, results); diff != "" {
+		t.Fatalf("incorrect results (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(params, got); diff != "" {
+		t.Fatalf("incorrect params (-want +got):\n%s", diff)
+	}
+}
+
+func TestCollectResults(t *testing.T) {
+	seq := func(err error) iter.Seq2[any, error] {
+		return func(yield func(any, error) bool) {
+			if !yield(1, nil) || !yield(2, nil) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+			}
+		}
+	}
+
+	got, err := tools.CollectResults(seq(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]any{1, 2}, got); diff != "" {
+		t.Fatalf("incorrect results (-want +got):\n%s", diff)
+	}
+
+	if _, err := tools.CollectResults(seq(fmt.Errorf("failed"))); err == nil || err.Error() != "failed" {
+		t.Fatalf("unexpected error: got %v, want %q", err, "failed")
+	}
+}
@@ -63,6 +63,15 @@ type ToolConfig interface {
 	Initialize(map[string]sources.Source) (Tool, error)
 }
 
+// MultiToolConfig is an optional interface of tool configs that expand into
+// several tools, e.g. all the tools of an upstream MCP server. Tools are
+// initialized with InitializeTools instead of Initialize, and are registered
+// under the names of the returned map.
+type MultiToolConfig interface {
+	ToolConfig
+	InitializeTools(map[string]sources.Source) (map[string]Tool, error)
+}
+
 type Tool interface {
 	Invoke(context.Context, ParamValues) ([]any, error)
 	ParseParams(map[string]any, map[string]map[string]any) (ParamValues, error)
@@ -82,10 +91,63 @@ type Manifest struct {
 type McpManifest struct {
 	// The name of the tool.
 	Name string `json:"name"`
+	// A human-readable title of the tool.
+	Title string `json:"title,omitempty"`
 	// A human-readable description of the tool.
 	Description string `json:"description,omitempty"`
 	// A JSON Schema object defining the expected parameters for the tool.
 	InputSchema McpToolsSchema `json:"inputSchema,omitempty"`
+	// A JSON Schema object defining the structure of the tool's output, the
+	// results of the tool are returned in the ResultKey property.
+	OutputSchema *McpSchema `json:"outputSchema,omitempty"`
+	// Optional hints describing the behavior of the tool.
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ResultKey is the property of the structured output that holds the results
+// of a tool invocation.
+const ResultKey = "result"
+
+// McpSchema is a JSON Schema object describing the structured output of a tool.
+type McpSchema struct {
+	Type        string                `json:"type"`
+	Description string                `json:"description,omitempty"`
+	Properties  map[string]*McpSchema `json:"properties,omitempty"`
+	Items       *McpSchema            `json:"items,omitempty"`
+	Required    []string              `json:"required,omitempty"`
+}
+
+// NewResultOutputSchema returns the output schema of a tool that returns a
+// list of items matching the item schema.
+func NewResultOutputSchema(item *McpSchema) *McpSchema {
+	return &McpSchema{
+		Type: "object",
+		Properties: map[string]*McpSchema{
+			ResultKey: {Type: "array", Items: item},
+		},
+		Required: []string{ResultKey},
+	}
+}
+
+// RowsOutputSchema is the output schema of tools that return rows, each row
+// being an object that maps the column names to their values. It does not
+// describe the columns, which are only known once the statement runs, so it
+// only declares that the results are a list of objects.
+var RowsOutputSchema = NewResultOutputSchema(&McpSchema{Type: "object", Description: "A row, mapping the column names to their values."})
+
+// ToolAnnotations are hints describing the behavior of a tool to clients.
+// All properties are hints, clients should not rely on them for security.
+type ToolAnnotations struct {
+	// If true, the tool does not modify its environment.
+	ReadOnlyHint *bool `yaml:"readOnlyHint" json:"readOnlyHint,omitempty"`
+	// If true, the tool may perform destructive updates to its environment.
+	// Only meaningful when readOnlyHint is false.
+	DestructiveHint *bool `yaml:"destructiveHint" json:"destructiveHint,omitempty"`
+	// If true, calling the tool repeatedly with the same arguments has no
+	// additional effect on its environment.
+	IdempotentHint *bool `yaml:"idempotentHint" json:"idempotentHint,omitempty"`
+	// If true, the tool may interact with an "open world" of external entities.
+	OpenWorldHint *bool `yaml:"openWorldHint" json:"openWorldHint,omitempty"`
 }
 
 // Helper function that returns if a tool invocation request is authorized
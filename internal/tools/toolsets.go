@@ -15,24 +15,125 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+)
+
+var (
+	// ErrToolNotIncluded is the error of tools that are not part of the
+	// toolset.
+	ErrToolNotIncluded = errors.New("invalid tool name")
+	// ErrToolUnauthorized is the error of requests that none of the
+	// authRequired services of the tool verified.
+	ErrToolUnauthorized = errors.New("tool invocation not authorized. Please make sure your specify correct auth headers")
+	// ErrToolNotAllowed is the error of requests whose claims the auth
+	// policies of the tool or toolset do not allow.
+	ErrToolNotAllowed = errors.New("not allowed by the auth policies for the verified claims")
 )
 
 type ToolsetConfig struct {
 	Name      string   `yaml:"name"`
 	ToolNames []string `yaml:",inline"`
+	// Strict toolsets are the only way to use their tools, which are left out
+	// of the default toolset.
+	Strict bool `yaml:"strict"`
+	// Description describes the toolset to clients of the Toolbox SDKs.
+	Description string `yaml:"description"`
+	// Instructions describe how to use the toolset, and are returned to MCP
+	// clients when they initialize.
+	Instructions string `yaml:"instructions"`
+	// AuthPolicies must all match for a request to use the tools of the
+	// toolset.
+	AuthPolicies AuthPolicies `yaml:"authPolicies"`
+	// Sources are the names of the sources whose resources the toolset
+	// publishes to MCP clients.
+	Sources []string `yaml:"sources"`
 }
 
 type Toolset struct {
-	Name        string          `yaml:"name"`
+	Name         string `yaml:"name"`
+	Instructions string `yaml:"instructions"`
+	// ToolNames lists the names of the tools in the order of the toolset.
+	ToolNames   []string        `yaml:",inline"`
 	Tools       []*Tool         `yaml:",inline"`
 	Manifest    ToolsetManifest `yaml:",inline"`
 	McpManifest []McpManifest   `yaml:",inline"`
+	// AuthPolicies are the policies of the toolset, and ToolAuthPolicies the
+	// policies of each of its tools.
+	AuthPolicies     AuthPolicies            `yaml:"authPolicies"`
+	ToolAuthPolicies map[string]AuthPolicies `yaml:"toolAuthPolicies"`
+	// SourceNames lists the names of the sources whose resources the toolset
+	// publishes.
+	SourceNames []string `yaml:"sources"`
+}
+
+// Contains returns true if the toolset includes the tool.
+func (t Toolset) Contains(toolName string) bool {
+	_, ok := t.Manifest.ToolsManifest[toolName]
+	return ok
+}
+
+// Allows returns true if the policies of the toolset and of the tool allow a
+// request with the claims to use the tool.
+func (t Toolset) Allows(toolName string, claimsFromAuth map[string]map[string]any) bool {
+	return t.AuthPolicies.Allows(claimsFromAuth) && t.ToolAuthPolicies[toolName].Allows(claimsFromAuth)
+}
+
+// Authorize returns an error if a request with the claims can not use the
+// tool of the toolset, i.e. if the toolset does not include the tool, if the
+// request is not authorized for the tool, or if the auth policies do not
+// allow it.
+func (t Toolset) Authorize(toolName string, tool Tool, claimsFromAuth map[string]map[string]any) error {
+	if !t.Contains(toolName) {
+		return fmt.Errorf("%w: tool with name %q is not part of toolset %q", ErrToolNotIncluded, toolName, t.Name)
+	}
+	if !tool.Authorized(slices.Collect(maps.Keys(claimsFromAuth))) {
+		return ErrToolUnauthorized
+	}
+	if !t.Allows(toolName, claimsFromAuth) {
+		return fmt.Errorf("tool %q is %w", toolName, ErrToolNotAllowed)
+	}
+	return nil
+}
+
+// AllowedToolNames returns the names of the tools that a request with the
+// claims is allowed to use, in the order of the toolset.
+func (t Toolset) AllowedToolNames(claimsFromAuth map[string]map[string]any) []string {
+	if t.AuthPolicies == nil && t.ToolAuthPolicies == nil {
+		return t.ToolNames
+	}
+	names := make([]string, 0, len(t.ToolNames))
+	for _, name := range t.ToolNames {
+		if t.Allows(name, claimsFromAuth) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AllowedMcpManifest returns the MCP manifests of the tools that a request
+// with the claims is allowed to use, in the order of the toolset.
+func (t Toolset) AllowedMcpManifest(claimsFromAuth map[string]map[string]any) []McpManifest {
+	if t.AuthPolicies == nil && t.ToolAuthPolicies == nil {
+		return t.McpManifest
+	}
+	manifests := make([]McpManifest, 0, len(t.McpManifest))
+	for i, name := range t.ToolNames {
+		if t.Allows(name, claimsFromAuth) {
+			manifests = append(manifests, t.McpManifest[i])
+		}
+	}
+	return manifests
 }
 
 type ToolsetManifest struct {
 	ServerVersion string              `json:"serverVersion"`
+	Description   string              `json:"description,omitempty"`
 	ToolsManifest map[string]Manifest `json:"tools"`
+	// NextPageToken is set if the toolset has more tools than listed.
+	NextPageToken string `json:"nextPageToken,omitempty"`
 }
 
 func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool) (Toolset, error) {
@@ -40,19 +141,27 @@ func (t ToolsetConfig) Initialize(serverVersion string, toolsMap map[string]Tool
 	// Check each declared tool name exists
 	var toolset Toolset
 	toolset.Name = t.Name
+	toolset.Instructions = t.Instructions
 	if !IsValidName(toolset.Name) {
-		return toolset, fmt.Errorf("invalid toolset name: %s", t)
+		return toolset, fmt.Errorf("invalid toolset name: %s", t.Name)
+	}
+	if err := t.AuthPolicies.Initialize(); err != nil {
+		return toolset, err
 	}
-	toolset.Tools = make([]*Tool, len(t.ToolNames))
+	toolset.AuthPolicies = t.AuthPolicies
+	toolset.SourceNames = t.Sources
+	toolset.Tools = make([]*Tool, 0, len(t.ToolNames))
 	toolset.Manifest = ToolsetManifest{
 		ServerVersion: serverVersion,
+		Description:   t.Description,
 		ToolsManifest: make(map[string]Manifest),
 	}
 	for _, toolName := range t.ToolNames {
 		tool, ok := toolsMap[toolName]
 		if !ok {
-			return toolset, fmt.Errorf("tool does not exist: %s", t)
+			return toolset, fmt.Errorf("tool %q of toolset %q does not exist", toolName, t.Name)
 		}
+		toolset.ToolNames = append(toolset.ToolNames, toolName)
 		toolset.Tools = append(toolset.Tools, &tool)
 		toolset.Manifest.ToolsManifest[toolName] = tool.Manifest()
 		toolset.McpManifest = append(toolset.McpManifest, tool.McpManifest())
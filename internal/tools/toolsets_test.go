@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/googleapis/genai-toolbox/internal/tools"
+)
+
+func TestToolsetInitialize(t *testing.T) {
+	toolsMap := map[string]tools.Tool{
+		"tool_a": listTool{},
+		"tool_b": listTool{},
+	}
+	tc := tools.ToolsetConfig{Name: "my_toolset", ToolNames: []string{"tool_b", "tool_a"}, Strict: true}
+	toolset, err := tc.Initialize("0.0.0", toolsMap)
+	if err != nil {
+		t.Fatalf("unable to initialize toolset: %s", err)
+	}
+	if len(toolset.Tools) != 2 || len(toolset.McpManifest) != 2 {
+		t.Fatalf("unexpected tools: %v", toolset.Tools)
+	}
+	for i, tool := range toolset.Tools {
+		if tool == nil {
+			t.Fatalf("tool %d of the toolset is nil", i)
+		}
+	}
+
+	tc.ToolNames = []string{"tool_a", "tool_c"}
+	_, err = tc.Initialize("0.0.0", toolsMap)
+	if err == nil || err.Error() != `tool "tool_c" of toolset "my_toolset" does not exist` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc.Name = "my toolset"
+	_, err = tc.Initialize("0.0.0", toolsMap)
+	if err == nil || !strings.HasSuffix(err.Error(), "invalid toolset name: my toolset") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -50,13 +50,15 @@ var _ compatibleSource = &valkeysrc.Source{}
 var compatibleSources = [...]string{valkeysrc.SourceKind, valkeysrc.SourceKind}
 
 type Config struct {
-	Name         string           `yaml:"name" validate:"required"`
-	Kind         string           `yaml:"kind" validate:"required"`
-	Source       string           `yaml:"source" validate:"required"`
-	Description  string           `yaml:"description" validate:"required"`
-	Commands     [][]string       `yaml:"commands" validate:"required"`
-	AuthRequired []string         `yaml:"authRequired"`
-	Parameters   tools.Parameters `yaml:"parameters"`
+	Name         string                 `yaml:"name" validate:"required"`
+	Kind         string                 `yaml:"kind" validate:"required"`
+	Source       string                 `yaml:"source" validate:"required"`
+	Description  string                 `yaml:"description" validate:"required"`
+	Commands     [][]string             `yaml:"commands" validate:"required"`
+	AuthRequired []string               `yaml:"authRequired"`
+	Parameters   tools.Parameters       `yaml:"parameters"`
+	Title        string                 `yaml:"title"`
+	Annotations  *tools.ToolAnnotations `yaml:"annotations"`
 }
 
 // validate interface
@@ -81,8 +83,10 @@ func (cfg Config) Initialize(srcs map[string]sources.Source) (tools.Tool, error)
 
 	mcpManifest := tools.McpManifest{
 		Name:        cfg.Name,
+		Title:       cfg.Title,
 		Description: cfg.Description,
 		InputSchema: cfg.Parameters.McpManifest(),
+		Annotations: cfg.Annotations,
 	}
 
 	// finish tool setup
@@ -16,9 +16,12 @@ package util
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	yaml "github.com/goccy/go-yaml"
@@ -121,3 +124,86 @@ func InstrumentationFromContext(ctx context.Context) (*telemetry.Instrumentation
 	}
 	return nil, fmt.Errorf("unable to retrieve instrumentation")
 }
+
+// claimsKey is the key used to store the claims of the verified auth services
+// within context
+const claimsKey contextKey = "claims"
+
+// WithClaims adds the claims of the verified auth services into the context
+// as a value. The claims are mapped by the name of the auth service.
+func WithClaims(ctx context.Context, claims map[string]map[string]any) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext retrieves the claims of the verified auth services, or an
+// empty map if no auth service was verified.
+func ClaimsFromContext(ctx context.Context) map[string]map[string]any {
+	if claims, ok := ctx.Value(claimsKey).(map[string]map[string]any); ok {
+		return claims
+	}
+	return make(map[string]map[string]any)
+}
+
+// clientCertificatesKey is the key used to store the verified certificate
+// chain of the client within context
+const clientCertificatesKey contextKey = "clientCertificates"
+
+// WithClientCertificates adds the verified certificate chain of the client
+// into the context as a value. The chain starts with the certificate of the
+// client.
+func WithClientCertificates(ctx context.Context, chain []*x509.Certificate) context.Context {
+	return context.WithValue(ctx, clientCertificatesKey, chain)
+}
+
+// ClientCertificatesFromContext retrieves the verified certificate chain of
+// the client, or nil if the client did not present a verified certificate.
+func ClientCertificatesFromContext(ctx context.Context) []*x509.Certificate {
+	if chain, ok := ctx.Value(clientCertificatesKey).([]*x509.Certificate); ok {
+		return chain
+	}
+	return nil
+}
+
+// progressReporterKey is the key used to store the progress reporter within
+// context
+const progressReporterKey contextKey = "progressReporter"
+
+// ProgressReporter reports the progress of a tool invocation to the client.
+// total is 0 if unknown.
+type ProgressReporter func(progress, total float64, message string)
+
+// WithProgressReporter adds a progress reporter into the context as a value
+func WithProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressReporterKey, reporter)
+}
+
+// ReportProgress reports the progress of a tool invocation. It does nothing
+// if the client did not request progress notifications.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if reporter, ok := ctx.Value(progressReporterKey).(ProgressReporter); ok {
+		reporter(progress, total, message)
+	}
+}
+
+// Paginate returns the bounds of the page of a list of total items that starts
+// at cursor, and the cursor of the next page. The cursor of the first page, and
+// the next cursor of the last page, are empty. A pageSize of 0 returns all of
+// the items from the cursor.
+func Paginate(total int, cursor string, pageSize int) (start, end int, next string, err error) {
+	if cursor != "" {
+		b, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+		start, err = strconv.Atoi(string(b))
+		if err != nil || start < 0 || start > total {
+			return 0, 0, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+	end = total
+	if pageSize > 0 && start+pageSize < total {
+		end = start + pageSize
+		next = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+	return start, end, next, nil
+}
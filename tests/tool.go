@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"reflect"
 	"strings"
@@ -606,6 +607,12 @@ func RunInitialize(t *testing.T, protocolVersion string) string {
 
 // RunMCPToolCallMethod runs the tool/call for mcp endpoint
 func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant string) {
+	// Get ID token
+	idToken, err := GetGoogleIdToken(ClientId)
+	if err != nil {
+		t.Fatalf("error getting Google ID token: %s", err)
+	}
+
 	sessionId := RunInitialize(t, "2024-11-05")
 	header := map[string]string{}
 	if sessionId != "" {
@@ -706,7 +713,24 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 					"arguments": map[string]any{},
 				},
 			},
-			want: "{\"jsonrpc\":\"2.0\",\"id\":\"invoke my-auth-required-tool\",\"error\":{\"code\":-32600,\"message\":\"unauthorized Tool call: `authRequired` is set for the target Tool\"}}",
+			want: "{\"jsonrpc\":\"2.0\",\"id\":\"invoke my-auth-required-tool\",\"error\":{\"code\":-32600,\"message\":\"unauthorized Tool call: please make sure you specify the correct auth headers\"}}",
+		},
+		{
+			name:          "MCP Invoke my-auth-required-tool with auth token",
+			api:           "http://127.0.0.1:5000/mcp",
+			requestHeader: map[string]string{"my-google-auth_token": idToken},
+			requestBody: jsonrpc.JSONRPCRequest{
+				Jsonrpc: "2.0",
+				Id:      "invoke my-auth-required-tool with auth token",
+				Request: jsonrpc.Request{
+					Method: "tools/call",
+				},
+				Params: map[string]any{
+					"name":      "my-auth-required-tool",
+					"arguments": map[string]any{},
+				},
+			},
+			want: "{\"jsonrpc\":\"2.0\",\"id\":\"invoke my-auth-required-tool with auth token\",\"result\":{\"content\":[",
 		},
 		{
 			name:          "MCP Invoke my-fail-tool",
@@ -733,7 +757,9 @@ func RunMCPToolCallMethod(t *testing.T, invokeParamWant, failInvocationWant stri
 				t.Fatalf("unexpected error during marshaling of request body")
 			}
 
-			_, respBody := runRequest(t, http.MethodPost, tc.api, bytes.NewBuffer(reqMarshal), header)
+			requestHeader := maps.Clone(header)
+			maps.Copy(requestHeader, tc.requestHeader)
+			_, respBody := runRequest(t, http.MethodPost, tc.api, bytes.NewBuffer(reqMarshal), requestHeader)
 			got := string(bytes.TrimSpace(respBody))
 
 			if !strings.Contains(got, tc.want) {